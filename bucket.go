@@ -13,25 +13,55 @@ type Size interface {
 type Bucket interface {
 	BucketObject
 	BucketObjects
+	BucketMultipart
+	BucketVersioning
+	ObjectTagging
+	ObjectArchiving
 	ObjectSigner
 }
 
 type BucketObject interface {
-	GetObject(ctx context.Context, path string) (Object, error)
-	PutObject(ctx context.Context, path string, reader io.Reader) error
+	GetObject(ctx context.Context, path string, opts ...GetOption) (Object, error)
+	GetObjectRange(ctx context.Context, path string, rng Range, opts ...GetOption) (Object, error)
+	PutObject(ctx context.Context, path string, reader io.Reader, opts ...PutOption) error
 	PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl ACL) error
 	HeadObject(ctx context.Context, path string) (bool, error)
-	DeleteObject(ctx context.Context, path string) error
+	StatObject(ctx context.Context, path string, opts ...GetOption) (*ObjectInfo, error)
+	DeleteObject(ctx context.Context, path string, opts ...DeleteOption) error
+	CopyObject(ctx context.Context, src string, dst string, opts ...CopyOption) error
+	MoveObject(ctx context.Context, src string, dst string, opts ...CopyOption) error
 	GetObjectSize(ctx context.Context, path string) (Size, error)
 }
 
 type BucketObjects interface {
+	// ListObjects returns every object under prefix; each entry is an *ObjectInfo.
 	ListObjects(ctx context.Context, prefix string) ([]ObjectMeta, error)
+	ListObjectsPage(ctx context.Context, prefix string, opts ...ListOption) (*ObjectPage, error)
 	DeleteObjects(ctx context.Context, paths []string) error
 }
 
+// BucketMultipart uploads an object in parts. Parts may be uploaded
+// concurrently, from different processes, and in any order; the object
+// appears once CompleteMultipartUpload is called with the parts to assemble.
+type BucketMultipart interface {
+	InitiateMultipartUpload(ctx context.Context, path string, opts ...PutOption) (string, error)
+	// UploadPart takes the PutOptions of the upload for the SSE-C key, which
+	// every part is sent with.
+	UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...PutOption) (Part, error)
+	ListParts(ctx context.Context, path string, uploadID string) ([]Part, error)
+	// CompleteMultipartUpload checks the PutOptions.Conditions of opts against
+	// the object the upload replaces.
+	CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []Part, opts ...PutOption) error
+	AbortMultipartUpload(ctx context.Context, path string, uploadID string) error
+}
+
 type ObjectSigner interface {
-	SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration) (string, error)
+	// SignURL presigns a request with method to the object at path. The
+	// response overrides of opts apply to GET requests and the request
+	// constraints to PUT requests.
+	SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...SignOption) (string, error)
+	// SignPostPolicy presigns a browser form upload restricted by policy.
+	SignPostPolicy(ctx context.Context, policy PostPolicy, expiredInDur time.Duration) (*PostForm, error)
 }
 
 type size int64
@@ -0,0 +1,49 @@
+package compress
+
+import (
+	"compress/gzip"
+	"fmt"
+	"github.com/klauspost/compress/zstd"
+	"io"
+)
+
+// Codec names a compression format. It is recorded with each compressed
+// object, so objects stay readable when the codec of the bucket changes.
+type Codec string
+
+const (
+	Gzip Codec = "gzip"
+	Zstd Codec = "zstd"
+)
+
+func (t Codec) Validate() error {
+	switch t {
+	case Gzip, Zstd:
+		return nil
+	}
+	return fmt.Errorf("unknown codec %q", string(t))
+}
+
+func (t Codec) newWriter(w io.Writer) (io.WriteCloser, error) {
+	switch t {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	}
+	return nil, t.Validate()
+}
+
+func (t Codec) newReader(r io.Reader) (io.ReadCloser, error) {
+	switch t {
+	case Gzip:
+		return gzip.NewReader(r)
+	case Zstd:
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+	return nil, t.Validate()
+}
@@ -0,0 +1,239 @@
+package compress
+
+import (
+	"bytes"
+	"context"
+	"github.com/burybell/osi"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	Name = "compress"
+
+	DefaultMinSize = 1 << 10
+
+	// The codec and the size before compression are kept in the user
+	// metadata of compressed objects.
+	metaCodec = "osi-compression"
+	metaSize  = "osi-compression-size"
+)
+
+// DefaultContentTypes are the text formats compressed by default.
+var DefaultContentTypes = []string{
+	"text/",
+	"application/json",
+	"application/x-ndjson",
+	"application/xml",
+	"application/javascript",
+	"application/yaml",
+	"application/x-yaml",
+	"image/svg+xml",
+}
+
+type Options struct {
+	Codec        Codec
+	MinSize      int64
+	ContentTypes []string
+}
+
+type Option func(opts *Options)
+
+func WithCodec(codec Codec) Option {
+	return func(opts *Options) {
+		opts.Codec = codec
+	}
+}
+
+// WithMinSize stores objects smaller than minSize as they are.
+func WithMinSize(minSize int64) Option {
+	return func(opts *Options) {
+		opts.MinSize = minSize
+	}
+}
+
+// WithContentTypes replaces the content types that are compressed. A type
+// ending in a slash matches every subtype, and the empty type matches objects
+// without a content type.
+func WithContentTypes(contentTypes ...string) Option {
+	return func(opts *Options) {
+		opts.ContentTypes = contentTypes
+	}
+}
+
+func NewOptions(opts ...Option) *Options {
+	options := &Options{Codec: Gzip, MinSize: DefaultMinSize, ContentTypes: DefaultContentTypes}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.MinSize < 0 {
+		options.MinSize = 0
+	}
+	return options
+}
+
+func (t *Options) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	for _, allowed := range t.ContentTypes {
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// bucket compresses the bodies of objects whose content type is allowed and
+// whose size reaches MinSize, and decompresses them on reads. Objects stored
+// as they are, by the threshold or by another writer, are passed through.
+//
+// Compressed bodies cannot be read from the middle, so ranged reads of
+// compressed objects fail with NotSupported, on which osi.Download reads them
+// whole. StatObject reports the size before compression when the size of the
+// body was known to PutObject, and the stored size otherwise; listings report
+// the stored sizes. Multipart uploads and presigned URLs bypass the wrapper,
+// storing bodies as they are and serving them as they are stored.
+type bucket struct {
+	osi.Bucket
+	options *Options
+}
+
+// NewBucket wraps bkt so that bodies are compressed before they are stored.
+func NewBucket(bkt osi.Bucket, opts ...Option) (osi.Bucket, error) {
+	options := NewOptions(opts...)
+	if err := options.Codec.Validate(); err != nil {
+		return nil, err
+	}
+	return &bucket{Bucket: bkt, options: options}, nil
+}
+
+// PutObject leaves bodies with a Content-Encoding of their own as they are.
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	if options.ContentEncoding != "" || !t.options.compressible(options.ContentType) {
+		return t.Bucket.PutObject(ctx, path, reader, opts...)
+	}
+	size, known := osi.ReaderSize(reader)
+	if known && size < t.options.MinSize {
+		return t.Bucket.PutObject(ctx, path, reader, opts...)
+	}
+	if !known {
+		head := make([]byte, t.options.MinSize)
+		n, err := io.ReadFull(reader, head)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return t.Bucket.PutObject(ctx, path, bytes.NewReader(head[:n]), opts...)
+		}
+		if err != nil {
+			return err
+		}
+		reader = io.MultiReader(bytes.NewReader(head), reader)
+	}
+
+	metadata := map[string]string{metaCodec: string(t.options.Codec)}
+	if known {
+		metadata[metaSize] = strconv.FormatInt(size, 10)
+	}
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w, err := t.options.Codec.newWriter(pw)
+		if err == nil {
+			_, err = io.Copy(w, reader)
+			if closeErr := w.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	err := t.Bucket.PutObject(ctx, path, pr, append(opts[:len(opts):len(opts)], osi.WithMetadata(metadata))...)
+	// a put that fails early leaves the compressor blocked on the pipe
+	if err != nil {
+		_ = pr.CloseWithError(err)
+	} else {
+		_ = pr.Close()
+	}
+	<-done
+	return err
+}
+
+func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
+}
+
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	return osi.GetPinned(ctx, t.Bucket, path, func(info *osi.ObjectInfo, opts []osi.GetOption) (osi.Object, error) {
+		return t.open(ctx, path, info, opts)
+	}, opts...)
+}
+
+func (t *bucket) open(ctx context.Context, path string, info *osi.ObjectInfo, opts []osi.GetOption) (osi.Object, error) {
+	codec, ok := info.Metadata[metaCodec]
+	if !ok {
+		return t.Bucket.GetObject(ctx, path, opts...)
+	}
+	if err := Codec(codec).Validate(); err != nil {
+		return nil, err
+	}
+	object, err := t.Bucket.GetObject(ctx, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	body, err := Codec(codec).newReader(object)
+	if err != nil {
+		_ = object.Close()
+		return nil, err
+	}
+	return osi.NewObject(object.Bucket(), object.ObjectPath(), object.ObjectACL(), &readCloser{Reader: body, closers: []io.Closer{body, object}}), nil
+}
+
+// GetObjectRange fails with NotSupported for compressed objects.
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	return osi.GetPinned(ctx, t.Bucket, path, func(info *osi.ObjectInfo, opts []osi.GetOption) (osi.Object, error) {
+		if _, ok := info.Metadata[metaCodec]; ok {
+			return nil, &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "ranged read of a compressed object"}
+		}
+		return t.Bucket.GetObjectRange(ctx, path, rng, opts...)
+	}, opts...)
+}
+
+// StatObject leaves the codec out of the metadata.
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	info, err := t.Bucket.StatObject(ctx, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if size, err := strconv.ParseInt(info.Metadata[metaSize], 10, 64); err == nil && info.Metadata[metaCodec] != "" {
+		info.Size = size
+	}
+	delete(info.Metadata, metaCodec)
+	delete(info.Metadata, metaSize)
+	return info, nil
+}
+
+func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
+	info, err := t.StatObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return osi.NewSize(info.Size), nil
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (t *readCloser) Close() error {
+	var err error
+	for _, closer := range t.closers {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
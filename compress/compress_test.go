@@ -0,0 +1,147 @@
+package compress_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/compress"
+	"github.com/burybell/osi/local"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+var (
+	ctx   = context.Background()
+	plain osi.Bucket
+)
+
+func init() {
+	dir, err := os.MkdirTemp("", "osi-compress-")
+	if err != nil {
+		panic(err)
+	}
+	store := local.MustNewObjectStore(local.Config{BasePath: dir})
+	if err := store.CreateBucket(ctx, "example"); err != nil {
+		panic(err)
+	}
+	plain = store.Bucket("example")
+}
+
+func read(t *testing.T, bucket osi.Bucket, path string) []byte {
+	object, err := bucket.GetObject(ctx, path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer object.Close()
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	return bs
+}
+
+func TestBucket_PutObject(t *testing.T) {
+	content := []byte(strings.Repeat(`{"level":"info","msg":"request served"}`+"\n", 100))
+	for _, codec := range []compress.Codec{compress.Gzip, compress.Zstd} {
+		bucket, err := compress.NewBucket(plain, compress.WithCodec(codec))
+		assert.NoError(t, err)
+		assert.NoError(t, bucket.PutObject(ctx, "test/log.json", bytes.NewReader(content), osi.WithMetadata(map[string]string{"team": "storage"})))
+		defer bucket.DeleteObject(ctx, "test/log.json")
+
+		assert.Equal(t, content, read(t, bucket, "test/log.json"))
+		stored, err := plain.StatObject(ctx, "test/log.json")
+		assert.NoError(t, err)
+		assert.Less(t, stored.Size, int64(len(content))/10)
+		assert.Equal(t, string(codec), stored.Metadata["osi-compression"])
+
+		info, err := bucket.StatObject(ctx, "test/log.json")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), info.Size)
+		assert.Equal(t, map[string]string{"team": "storage"}, info.Metadata)
+
+		// a reader of unknown size is compressed all the same
+		assert.NoError(t, bucket.PutObject(ctx, "test/log.json", io.NopCloser(bytes.NewReader(content))))
+		assert.Equal(t, content, read(t, bucket, "test/log.json"))
+	}
+}
+
+func TestBucket_PassThrough(t *testing.T) {
+	bucket, err := compress.NewBucket(plain)
+	assert.NoError(t, err)
+	large := bytes.Repeat([]byte("a"), 4096)
+	for path, content := range map[string][]byte{
+		"test/small.json":      []byte(`{}`),
+		"test/photo.jpg":       large,
+		"test/stream-small.js": []byte("var a = 1;"),
+	} {
+		var reader io.Reader = bytes.NewReader(content)
+		if strings.HasPrefix(path, "test/stream") {
+			reader = io.NopCloser(reader)
+		}
+		assert.NoError(t, bucket.PutObject(ctx, path, reader))
+		defer bucket.DeleteObject(ctx, path)
+		stored, err := plain.StatObject(ctx, path)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), stored.Size, path)
+		assert.Equal(t, content, read(t, bucket, path))
+	}
+
+	assert.NoError(t, bucket.PutObject(ctx, "test/encoded.txt", bytes.NewReader(large), osi.WithContentEncoding("br")))
+	defer bucket.DeleteObject(ctx, "test/encoded.txt")
+	stored, err := plain.StatObject(ctx, "test/encoded.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(large)), stored.Size)
+
+	object, err := bucket.GetObjectRange(ctx, "test/photo.jpg", osi.NewRange(0, 4))
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("aaaa"), bs)
+	_ = object.Close()
+}
+
+func TestBucket_GetObjectRange(t *testing.T) {
+	bucket, err := compress.NewBucket(plain, compress.WithMinSize(0))
+	assert.NoError(t, err)
+	content := []byte(strings.Repeat("some text ", 1000))
+	assert.NoError(t, bucket.PutObject(ctx, "test/range.txt", bytes.NewReader(content)))
+	defer bucket.DeleteObject(ctx, "test/range.txt")
+	_, err = bucket.GetObjectRange(ctx, "test/range.txt", osi.NewRange(0, 4))
+	assert.ErrorIs(t, err, osi.NotSupported)
+
+	// Download falls back to a whole read
+	w := &buffer{}
+	n, err := osi.Download(ctx, bucket, "test/range.txt", w, osi.WithDownloadPartSize(100))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), n)
+	assert.Equal(t, content, w.bs)
+}
+
+type buffer struct {
+	bs []byte
+}
+
+func (t *buffer) WriteAt(p []byte, off int64) (int, error) {
+	if end := int(off) + len(p); end > len(t.bs) {
+		t.bs = append(t.bs, make([]byte, end-len(t.bs))...)
+	}
+	return copy(t.bs[off:], p), nil
+}
+
+func TestWithContentTypes(t *testing.T) {
+	bucket, err := compress.NewBucket(plain, compress.WithMinSize(0), compress.WithContentTypes("", "application/octet-stream"))
+	assert.NoError(t, err)
+	content := bytes.Repeat([]byte("a"), 4096)
+	for path, contentType := range map[string]string{"test/no-extension": "", "test/blob": "application/octet-stream", "test/page.html": "text/html"} {
+		assert.NoError(t, bucket.PutObject(ctx, path, bytes.NewReader(content), osi.WithContentType(contentType)))
+		defer bucket.DeleteObject(ctx, path)
+		stored, err := plain.StatObject(ctx, path)
+		assert.NoError(t, err)
+		assert.Equal(t, path == "test/page.html", stored.Size == int64(len(content)), path)
+		assert.Equal(t, content, read(t, bucket, path))
+	}
+
+	_, err = compress.NewBucket(plain, compress.WithCodec("lz4"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,176 @@
+package osi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// maxPinnedAttempts bounds how often GetPinned reads again when the object is
+// replaced between reading its metadata and its body.
+const maxPinnedAttempts = 3
+
+// Conditions are preconditions on the current state of an object, with the
+// semantics of the HTTP conditional headers. An IfNoneMatch of "*" makes a put
+// create-only; an IfMatch of the ETag last read makes it a compare-and-swap.
+type Conditions struct {
+	IfMatch           string
+	IfNoneMatch       string
+	IfModifiedSince   time.Time
+	IfUnmodifiedSince time.Time
+}
+
+func (t Conditions) IsZero() bool {
+	return t.IfMatch == "" && t.IfNoneMatch == "" && t.IfModifiedSince.IsZero() && t.IfUnmodifiedSince.IsZero()
+}
+
+// Header returns the conditions as HTTP request headers.
+func (t Conditions) Header() http.Header {
+	var header = make(http.Header)
+	if t.IfMatch != "" {
+		header.Set("If-Match", QuoteETag(t.IfMatch))
+	}
+	if t.IfNoneMatch != "" {
+		header.Set("If-None-Match", QuoteETag(t.IfNoneMatch))
+	}
+	if !t.IfModifiedSince.IsZero() {
+		header.Set("If-Modified-Since", t.IfModifiedSince.UTC().Format(http.TimeFormat))
+	}
+	if !t.IfUnmodifiedSince.IsZero() {
+		header.Set("If-Unmodified-Since", t.IfUnmodifiedSince.UTC().Format(http.TimeFormat))
+	}
+	return header
+}
+
+// Check evaluates the conditions for a request with method against info, the
+// current state of the object, or nil when there is none. A failed
+// IfNoneMatch or IfModifiedSince yields NotModified for GET and HEAD requests
+// and PreconditionFailed otherwise, as it does over HTTP.
+func (t Conditions) Check(method string, info *ObjectInfo) error {
+	if t.IfMatch != "" {
+		if info == nil || !matchETag(t.IfMatch, info.ETag) {
+			return PreconditionFailed
+		}
+	} else if !t.IfUnmodifiedSince.IsZero() && info != nil && modifiedSince(info.LastModified, t.IfUnmodifiedSince) {
+		return PreconditionFailed
+	}
+
+	notModified := error(PreconditionFailed)
+	if method == http.MethodGet || method == http.MethodHead {
+		notModified = NotModified
+	}
+	if t.IfNoneMatch != "" {
+		if info != nil && matchETag(t.IfNoneMatch, info.ETag) {
+			return notModified
+		}
+	} else if !t.IfModifiedSince.IsZero() && info != nil && !modifiedSince(info.LastModified, t.IfModifiedSince) {
+		return notModified
+	}
+	return nil
+}
+
+func matchETag(condition string, etag string) bool {
+	return condition == "*" || TrimETag(condition) == TrimETag(etag)
+}
+
+// modifiedSince compares at the one second resolution of HTTP dates.
+func modifiedSince(lastModified time.Time, since time.Time) bool {
+	return lastModified.Truncate(time.Second).After(since.Truncate(time.Second))
+}
+
+// QuoteETag quotes etag for use in a header; "*" is left as it is.
+func QuoteETag(etag string) string {
+	if etag == "*" {
+		return etag
+	}
+	return "\"" + TrimETag(etag) + "\""
+}
+
+type GetOptions struct {
+	Conditions  Conditions
+	VersionID   string
+	CustomerKey []byte
+}
+
+type GetOption func(opts *GetOptions)
+
+func WithGetConditions(conditions Conditions) GetOption {
+	return func(opts *GetOptions) {
+		opts.Conditions = conditions
+	}
+}
+
+// WithGetVersion reads the version versionID instead of the latest one.
+func WithGetVersion(versionID string) GetOption {
+	return func(opts *GetOptions) {
+		opts.VersionID = versionID
+	}
+}
+
+// WithCustomerKey reads an object stored with SSE-C under key.
+func WithCustomerKey(key []byte) GetOption {
+	return func(opts *GetOptions) {
+		opts.CustomerKey = key
+	}
+}
+
+func NewGetOptions(opts ...GetOption) *GetOptions {
+	options := &GetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// GetPinned reads the metadata of the object at path from bkt and passes it
+// to open along with opts pinned to the ETag read, so that the body open reads
+// is the one the metadata describes. The conditions of opts are evaluated
+// against the metadata. A pinned read failing with PreconditionFailed means
+// the object was replaced in between; the read then starts over.
+func GetPinned(ctx context.Context, bkt Bucket, path string, open func(info *ObjectInfo, opts []GetOption) (Object, error), opts ...GetOption) (Object, error) {
+	options := NewGetOptions(opts...)
+	for attempt := 1; ; attempt++ {
+		info, err := bkt.StatObject(ctx, path, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if err := options.Conditions.Check(http.MethodGet, info); err != nil {
+			return nil, err
+		}
+		object, err := open(info, append(opts[:len(opts):len(opts)], WithGetConditions(Conditions{IfMatch: info.ETag})))
+		if errors.Is(err, PreconditionFailed) && attempt < maxPinnedAttempts {
+			continue
+		}
+		return object, err
+	}
+}
+
+type DeleteOptions struct {
+	Conditions Conditions
+	VersionID  string
+}
+
+type DeleteOption func(opts *DeleteOptions)
+
+func WithDeleteConditions(conditions Conditions) DeleteOption {
+	return func(opts *DeleteOptions) {
+		opts.Conditions = conditions
+	}
+}
+
+// WithDeleteVersion removes the version versionID for good instead of
+// adding a delete marker.
+func WithDeleteVersion(versionID string) DeleteOption {
+	return func(opts *DeleteOptions) {
+		opts.VersionID = versionID
+	}
+}
+
+func NewDeleteOptions(opts ...DeleteOption) *DeleteOptions {
+	options := &DeleteOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
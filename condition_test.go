@@ -0,0 +1,89 @@
+package osi_test
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/local"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditions_Check(t *testing.T) {
+	now := time.Now()
+	info := osi.NewObjectInfo("test", "test/example.txt")
+	info.ETag = "abc"
+	info.LastModified = now
+
+	assert.NoError(t, osi.Conditions{}.Check(http.MethodGet, info))
+	assert.NoError(t, osi.Conditions{IfMatch: `"abc"`}.Check(http.MethodPut, info))
+	assert.NoError(t, osi.Conditions{IfMatch: "*"}.Check(http.MethodPut, info))
+	assert.ErrorIs(t, osi.Conditions{IfMatch: "def"}.Check(http.MethodPut, info), osi.PreconditionFailed)
+	assert.ErrorIs(t, osi.Conditions{IfMatch: "abc"}.Check(http.MethodPut, nil), osi.PreconditionFailed)
+
+	assert.NoError(t, osi.Conditions{IfNoneMatch: "*"}.Check(http.MethodPut, nil))
+	assert.ErrorIs(t, osi.Conditions{IfNoneMatch: "*"}.Check(http.MethodPut, info), osi.PreconditionFailed)
+	assert.ErrorIs(t, osi.Conditions{IfNoneMatch: "abc"}.Check(http.MethodGet, info), osi.NotModified)
+	assert.NoError(t, osi.Conditions{IfNoneMatch: "def"}.Check(http.MethodGet, info))
+
+	assert.ErrorIs(t, osi.Conditions{IfModifiedSince: now}.Check(http.MethodGet, info), osi.NotModified)
+	assert.NoError(t, osi.Conditions{IfModifiedSince: now.Add(-time.Minute)}.Check(http.MethodGet, info))
+	assert.NoError(t, osi.Conditions{IfUnmodifiedSince: now}.Check(http.MethodDelete, info))
+	assert.ErrorIs(t, osi.Conditions{IfUnmodifiedSince: now.Add(-time.Minute)}.Check(http.MethodDelete, info), osi.PreconditionFailed)
+}
+
+func TestConditions_Header(t *testing.T) {
+	since := time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC)
+	header := osi.Conditions{IfMatch: "abc", IfNoneMatch: "*", IfModifiedSince: since}.Header()
+	assert.Equal(t, `"abc"`, header.Get("If-Match"))
+	assert.Equal(t, "*", header.Get("If-None-Match"))
+	assert.Equal(t, "Sun, 01 Oct 2023 08:00:00 GMT", header.Get("If-Modified-Since"))
+	assert.Empty(t, header.Get("If-Unmodified-Since"))
+}
+
+// replacingBucket replaces the object the given number of times, each right
+// after its metadata is read.
+type replacingBucket struct {
+	osi.Bucket
+	replacements int
+}
+
+func (t *replacingBucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	info, err := t.Bucket.StatObject(ctx, path, opts...)
+	if err == nil && t.replacements > 0 {
+		t.replacements--
+		err = t.Bucket.PutObject(ctx, path, strings.NewReader(strings.Repeat("other text ", t.replacements+1)))
+	}
+	return info, err
+}
+
+func TestGetPinned(t *testing.T) {
+	ctx := context.Background()
+	store := local.MustNewObjectStore(local.Config{BasePath: t.TempDir()})
+	assert.NoError(t, store.CreateBucket(ctx, "test"))
+	bucket := &replacingBucket{Bucket: store.Bucket("test")}
+	assert.NoError(t, bucket.PutObject(ctx, "test/pinned.txt", strings.NewReader("some text")))
+
+	var size int64
+	open := func(info *osi.ObjectInfo, opts []osi.GetOption) (osi.Object, error) {
+		size = info.Size
+		return bucket.GetObject(ctx, "test/pinned.txt", opts...)
+	}
+	bucket.replacements = 2
+	object, err := osi.GetPinned(ctx, bucket, "test/pinned.txt", open)
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.NoError(t, object.Close())
+	assert.Equal(t, "other text ", string(bs))
+	assert.Equal(t, int64(len(bs)), size)
+
+	bucket.replacements = 3
+	_, err = osi.GetPinned(ctx, bucket, "test/pinned.txt", open)
+	assert.ErrorIs(t, err, osi.PreconditionFailed)
+	_, err = osi.GetPinned(ctx, bucket, "test/pinned.txt", open, osi.WithGetConditions(osi.Conditions{IfNoneMatch: "*"}))
+	assert.ErrorIs(t, err, osi.NotModified)
+}
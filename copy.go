@@ -0,0 +1,60 @@
+package osi
+
+const (
+	// MaxCopyObjectSize is the largest object the providers copy in a single
+	// request; larger objects are copied part by part.
+	MaxCopyObjectSize = 5 << 30
+	MaxPartCount      = 10000
+	MinCopyPartSize   = 512 << 20
+)
+
+type CopyOptions struct {
+	SourceBucket string
+}
+
+type CopyOption func(opts *CopyOptions)
+
+// WithSourceBucket copies from another bucket of the same ObjectStore.
+func WithSourceBucket(bucket string) CopyOption {
+	return func(opts *CopyOptions) {
+		opts.SourceBucket = bucket
+	}
+}
+
+// NewCopyOptions applies opts for a copy into bucket. The source bucket
+// defaults to the destination bucket.
+func NewCopyOptions(bucket string, opts ...CopyOption) *CopyOptions {
+	options := &CopyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.SourceBucket == "" {
+		options.SourceBucket = bucket
+	}
+	return options
+}
+
+// CopyPartSize returns the part size for a multipart copy of size bytes.
+func CopyPartSize(size int64) int64 {
+	partSize := int64(MinCopyPartSize)
+	for partSize*MaxPartCount < size {
+		partSize *= 2
+	}
+	if partSize > MaxCopyObjectSize {
+		partSize = MaxCopyObjectSize
+	}
+	return partSize
+}
+
+// SplitRange splits size bytes into consecutive ranges of at most partSize bytes.
+func SplitRange(size int64, partSize int64) []Range {
+	var ranges = make([]Range, 0, size/partSize+1)
+	for offset := int64(0); offset < size; offset += partSize {
+		length := partSize
+		if offset+length > size {
+			length = size - offset
+		}
+		ranges = append(ranges, NewRange(offset, length))
+	}
+	return ranges
+}
@@ -0,0 +1,19 @@
+package osi_test
+
+import (
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCopyPartSize(t *testing.T) {
+	assert.Equal(t, int64(osi.MinCopyPartSize), osi.CopyPartSize(6<<30))
+	assert.Equal(t, int64(1<<30), osi.CopyPartSize(6<<40))
+	assert.Equal(t, int64(osi.MaxCopyObjectSize), osi.CopyPartSize(48<<40))
+}
+
+func TestSplitRange(t *testing.T) {
+	ranges := osi.SplitRange(10, 4)
+	assert.Equal(t, []osi.Range{osi.NewRange(0, 4), osi.NewRange(4, 4), osi.NewRange(8, 2)}, ranges)
+	assert.Empty(t, osi.SplitRange(0, 4))
+}
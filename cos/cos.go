@@ -20,6 +20,8 @@ type Config struct {
 	Region string `yaml:"region" mapstructure:"region" json:"region"`
 	KeyID  string `yaml:"key_id" mapstructure:"key_id" json:"key_id"`
 	Secret string `yaml:"secret" mapstructure:"secret" json:"secret"`
+	// Logger receives the requests sent and the URLs presigned.
+	Logger osi.Logger `yaml:"-" mapstructure:"-" json:"-"`
 }
 
 type ObjectStore struct {
@@ -37,11 +39,21 @@ func NewObjectStore(config Config) (osi.ObjectStore, error) {
 		Transport: &cos.AuthorizationTransport{
 			SecretID:  config.KeyID,
 			SecretKey: config.Secret,
+			Transport: config.transport(),
 		},
 	})
 	return &ObjectStore{config: config, client: client}, nil
 }
 
+// transport sends the signed requests through Logger when one is set, and
+// leaves the choice to the SDK otherwise.
+func (t Config) transport() http.RoundTripper {
+	if t.Logger == nil {
+		return nil
+	}
+	return osi.NewLoggingTransport(Name, t.Logger, nil)
+}
+
 func MustNewObjectStore(config Config) osi.ObjectStore {
 	store, err := NewObjectStore(config)
 	if err != nil {
@@ -55,22 +67,73 @@ func (t *ObjectStore) Name() string {
 }
 
 func (t *ObjectStore) Bucket(name string) osi.Bucket {
-	bucketURL, _ := url.Parse(fmt.Sprintf("https://%s.cos.%s.myqcloud.com", name, t.config.Region))
 	return &bucket{
 		config: t.config,
-		client: cos.NewClient(&cos.BaseURL{
-			ServiceURL: t.client.BaseURL.ServiceURL,
-			BucketURL:  bucketURL,
-		}, &http.Client{
-			Transport: &cos.AuthorizationTransport{
-				SecretID:  t.config.KeyID,
-				SecretKey: t.config.Secret,
-			},
-		}),
+		client: t.bucketClient(name, t.config.Region),
 		bucket: name,
 	}
 }
 
+func (t *ObjectStore) bucketClient(name string, region string) *cos.Client {
+	bucketURL, _ := url.Parse(fmt.Sprintf("https://%s.cos.%s.myqcloud.com", name, region))
+	return cos.NewClient(&cos.BaseURL{
+		ServiceURL: t.client.BaseURL.ServiceURL,
+		BucketURL:  bucketURL,
+	}, &http.Client{
+		Transport: &cos.AuthorizationTransport{
+			SecretID:  t.config.KeyID,
+			SecretKey: t.config.Secret,
+			Transport: t.config.transport(),
+		},
+	})
+}
+
+// CreateBucket creates the bucket in the region of its endpoint. Names carry
+// the APPID suffix, as in examplebucket-1250000000.
+func (t *ObjectStore) CreateBucket(ctx context.Context, name string, opts ...osi.BucketOption) error {
+	options := osi.NewBucketOptions(t.config.Region, opts...)
+	putOptions := &cos.BucketPutOptions{}
+	if options.ACL != (aclEnum{}).Default() {
+		putOptions.XCosACL = options.ACL
+	}
+	_, err := t.bucketClient(name, options.Region).Bucket.Put(ctx, putOptions)
+	return toError(err)
+}
+
+func (t *ObjectStore) DeleteBucket(ctx context.Context, name string) error {
+	_, err := t.bucketClient(name, t.config.Region).Bucket.Delete(ctx)
+	return toError(err)
+}
+
+// ListBuckets lists the buckets in the configured region.
+func (t *ObjectStore) ListBuckets(ctx context.Context) ([]osi.BucketInfo, error) {
+	var buckets = make([]osi.BucketInfo, 0)
+	getOptions := &cos.ServiceGetOptions{}
+	for {
+		result, _, err := t.client.Service.Get(ctx, getOptions)
+		if err != nil {
+			return nil, toError(err)
+		}
+		for _, b := range result.Buckets {
+			creationDate, _ := time.Parse(time.RFC3339, b.CreationDate)
+			buckets = append(buckets, osi.BucketInfo{
+				Name:         b.Name,
+				Region:       b.Region,
+				CreationDate: creationDate,
+			})
+		}
+		if !result.IsTruncated {
+			return buckets, nil
+		}
+		getOptions.Marker = result.NextMarker
+	}
+}
+
+func (t *ObjectStore) BucketExists(ctx context.Context, name string) (bool, error) {
+	exist, err := t.bucketClient(name, t.config.Region).Bucket.IsExist(ctx)
+	return exist, toError(err)
+}
+
 func (t *ObjectStore) ACLEnum() osi.ACLEnum {
 	return aclEnum{}
 }
@@ -81,14 +144,36 @@ type bucket struct {
 	bucket string
 }
 
-func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error) {
-	acl, resp, err := t.client.Object.GetACL(ctx, path)
-	if err != nil {
-		if cos.IsNotFoundError(err) {
-			return nil, osi.ObjectNotFound
-		}
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	options := osi.NewGetOptions(opts...)
+	getOpts := &cos.ObjectGetOptions{}
+	if !options.Conditions.IsZero() {
+		header := options.Conditions.Header()
+		getOpts.XOptionHeader = &header
+	}
+	getOpts.XCosSSECustomerAglo, getOpts.XCosSSECustomerKey, getOpts.XCosSSECustomerKeyMD5 = customerKey(options.CustomerKey)
+	return t.getObject(ctx, path, getOpts, versionID(options.VersionID)...)
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	if err := rng.Validate(); err != nil {
 		return nil, err
 	}
+	options := osi.NewGetOptions(opts...)
+	getOpts := &cos.ObjectGetOptions{Range: rng.HeaderValue()}
+	if !options.Conditions.IsZero() {
+		header := options.Conditions.Header()
+		getOpts.XOptionHeader = &header
+	}
+	getOpts.XCosSSECustomerAglo, getOpts.XCosSSECustomerKey, getOpts.XCosSSECustomerKeyMD5 = customerKey(options.CustomerKey)
+	return t.getObject(ctx, path, getOpts, versionID(options.VersionID)...)
+}
+
+func (t *bucket) getObject(ctx context.Context, path string, opts *cos.ObjectGetOptions, id ...string) (osi.Object, error) {
+	acl, resp, err := t.client.Object.GetACL(ctx, path, id...)
+	if err != nil {
+		return nil, toError(err)
+	}
 	_ = resp.Body.Close()
 
 	publicACL := make(map[string]int)
@@ -105,74 +190,189 @@ func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error)
 		resACL = "private"
 	}
 
-	resp, err = t.client.Object.Get(context.TODO(), path, nil)
+	resp, err = t.client.Object.Get(ctx, path, opts, id...)
 	if err != nil {
-		return nil, err
+		return nil, toError(err)
 	}
 	return osi.NewObject(t.bucket, path, resACL, resp.Body), nil
 }
 
-func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader) error {
-	return t.PutObjectWithACL(ctx, path, reader, aclEnum{}.Default())
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	if options.ACL == "" {
+		options.ACL = aclEnum{}.Default()
+	}
+	conditionHeader, err := writeConditions(options.Conditions)
+	if err != nil {
+		return err
+	}
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return err
+	}
+	if err := options.Encryption.Validate(); err != nil {
+		return err
+	}
+	return osi.Upload(ctx, t, path, reader, func(ctx context.Context, reader io.Reader, size int64) error {
+		headers := putHeaderOptions(options)
+		headers.ContentLength = size
+		if conditionHeader != nil {
+			if headers.XOptionHeader == nil {
+				headers.XOptionHeader = &http.Header{}
+			}
+			for key, values := range *conditionHeader {
+				(*headers.XOptionHeader)[key] = values
+			}
+		}
+		_, err := t.client.Object.Put(ctx, path, reader, &cos.ObjectPutOptions{
+			ACLHeaderOptions: &cos.ACLHeaderOptions{
+				XCosACL: options.ACL,
+			},
+			ObjectPutHeaderOptions: headers,
+		})
+		return toError(err)
+	}, opts...)
 }
 
 func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
-	_, err := t.client.Object.Put(ctx, path, reader, &cos.ObjectPutOptions{
-		ACLHeaderOptions: &cos.ACLHeaderOptions{
-			XCosACL: acl,
-		},
-	})
-	return err
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
 }
 
 func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
-	return t.client.Object.IsExist(ctx, path)
+	exist, err := t.client.Object.IsExist(ctx, path)
+	return exist, toError(err)
 }
 
-func (t *bucket) DeleteObject(ctx context.Context, path string) error {
-	_, err := t.client.Object.Delete(ctx, path, nil)
-	return err
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	options := osi.NewDeleteOptions(opts...)
+	if !options.Conditions.IsZero() {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional delete"}
+	}
+	var deleteOpts *cos.ObjectDeleteOptions
+	if options.VersionID != "" {
+		deleteOpts = &cos.ObjectDeleteOptions{VersionId: options.VersionID}
+	}
+	_, err := t.client.Object.Delete(ctx, path, deleteOpts)
+	return toError(err)
+}
+
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	sourceURL := fmt.Sprintf("%s.cos.%s.myqcloud.com/%s", options.SourceBucket, t.config.Region, src)
+	_, _, err := t.client.Object.MultiCopy(ctx, dst, sourceURL, &cos.MultiCopyOptions{ThreadPoolSize: 4})
+	return toError(err)
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	err := t.CopyObject(ctx, src, dst, opts...)
+	if err != nil {
+		return err
+	}
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	if options.SourceBucket == t.bucket {
+		_, err = t.client.Object.Delete(ctx, src, nil)
+		return toError(err)
+	}
+	bucketURL, err := url.Parse(fmt.Sprintf("https://%s.cos.%s.myqcloud.com", options.SourceBucket, t.config.Region))
+	if err != nil {
+		return err
+	}
+	client := cos.NewClient(&cos.BaseURL{BucketURL: bucketURL}, &http.Client{
+		Transport: &cos.AuthorizationTransport{
+			SecretID:  t.config.KeyID,
+			SecretKey: t.config.Secret,
+			Transport: t.config.transport(),
+		},
+	})
+	_, err = client.Object.Delete(ctx, src, nil)
+	return toError(err)
 }
 
 func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
-	var oms = make([]osi.ObjectMeta, 0)
-	var marker = ""
-	for {
-		resp, _, err := t.client.Bucket.Get(ctx, &cos.BucketGetOptions{
-			Prefix:  prefix,
-			Marker:  marker,
-			MaxKeys: 200,
-		})
-		if err != nil {
-			return oms, err
-		}
-		for _, object := range resp.Contents {
-			if strings.HasSuffix(object.Key, "/") {
-				continue
-			}
-			oms = append(oms, osi.NewObjectMeta(t.bucket, object.Key))
-		}
-		if !resp.IsTruncated {
-			return oms, nil
-		} else {
-			marker = resp.NextMarker
+	return osi.ListAll(ctx, t, prefix)
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	var marker = options.ContinuationToken
+	if marker == "" {
+		marker = options.StartAfter
+	}
+	resp, _, err := t.client.Bucket.Get(ctx, &cos.BucketGetOptions{
+		Prefix:    prefix,
+		Delimiter: options.Delimiter,
+		Marker:    marker,
+		MaxKeys:   options.PageSize,
+	})
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.ObjectPage{
+		Objects:        make([]*osi.ObjectInfo, 0, len(resp.Contents)),
+		CommonPrefixes: resp.CommonPrefixes,
+		IsTruncated:    resp.IsTruncated,
+	}
+	var lastKey = ""
+	for _, object := range resp.Contents {
+		lastKey = object.Key
+		if strings.HasSuffix(object.Key, "/") {
+			continue
 		}
+		page.Objects = append(page.Objects, listedObject(t.bucket, object))
 	}
+	if resp.IsTruncated {
+		page.NextContinuationToken = nextMarker(resp.NextMarker, lastKey, resp.CommonPrefixes)
+	}
+	return page, nil
 }
 
 func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
-	resp, err := t.client.Object.Head(ctx, path, nil)
+	info, err := t.StatObject(ctx, path)
 	if err != nil {
-		if cos.IsNotFoundError(err) {
-			return nil, osi.ObjectNotFound
-		}
 		return nil, err
 	}
-	return osi.NewSize(resp.ContentLength), nil
+	return osi.NewSize(info.Size), nil
 }
 
-func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration) (string, error) {
-	rawURL, err := t.client.Object.GetPresignedURL(ctx, method, path, t.config.KeyID, t.config.Secret, expiredInDur, nil)
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	options := osi.NewGetOptions(opts...)
+	headOpts := &cos.ObjectHeadOptions{}
+	headOpts.XCosSSECustomerAglo, headOpts.XCosSSECustomerKey, headOpts.XCosSSECustomerKeyMD5 = customerKey(options.CustomerKey)
+	resp, err := t.client.Object.Head(ctx, path, headOpts, versionID(options.VersionID)...)
+	if err != nil {
+		return nil, toError(err)
+	}
+	info := osi.NewObjectInfo(t.bucket, path)
+	info.Size = resp.ContentLength
+	info.ETag = osi.TrimETag(resp.Header.Get("ETag"))
+	info.VersionID = resp.Header.Get("x-cos-version-id")
+	info.LastModified, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	info.ContentType = resp.Header.Get("Content-Type")
+	info.ContentEncoding = resp.Header.Get("Content-Encoding")
+	info.CacheControl = resp.Header.Get("Cache-Control")
+	info.ContentDisposition = resp.Header.Get("Content-Disposition")
+	info.Expires, _ = http.ParseTime(resp.Header.Get("Expires"))
+	info.StorageClass = storageClass(resp.Header.Get("x-cos-storage-class"))
+	info.Restore = osi.ParseRestoreStatus(resp.Header.Get("x-cos-restore"))
+	info.Encryption = osi.ParseEncryptionMode(resp.Header.Get("x-cos-server-side-encryption"), resp.Header.Get("x-cos-server-side-encryption-customer-algorithm"))
+	info.KMSKeyID = resp.Header.Get(kmsKeyIDHeader)
+	info.Metadata = osi.MetadataFromHeader(resp.Header, "x-cos-meta-")
+	return info, nil
+}
+
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	signed, err := t.signURL(ctx, path, method, expiredInDur, opts...)
+	osi.LogSignURL(ctx, t.config.Logger, Name, t.bucket, path, method, expiredInDur, signed, err)
+	return signed, err
+}
+
+func (t *bucket) signURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	options := osi.NewSignOptions(opts...)
+	query, header := options.Query(), options.Header("x-cos-acl")
+	rawURL, err := t.client.Object.GetPresignedURL(ctx, method, path, t.config.KeyID, t.config.Secret, expiredInDur, &cos.PresignedURLOptions{Query: &query, Header: &header})
 	if err != nil {
 		return "", err
 	}
@@ -204,8 +404,85 @@ func (t *bucket) deleteFiles(ctx context.Context, paths []string) error {
 			Key: paths[i],
 		})
 	}
-	_, _, err := t.client.Object.DeleteMulti(ctx, opts)
-	return err
+	result, _, err := t.client.Object.DeleteMulti(ctx, opts)
+	if err != nil {
+		return toError(err)
+	}
+	if len(result.Errors) > 0 {
+		keys := make([]string, len(result.Errors))
+		for i, failed := range result.Errors {
+			keys[i] = failed.Key
+		}
+		failed := result.Errors[0]
+		return osi.DeleteError(Name, failed.Code, failed.Message, keys)
+	}
+	return nil
+}
+
+// writeConditions maps conditions onto COS, which can only refuse to
+// overwrite an existing object.
+func writeConditions(conditions osi.Conditions) (*http.Header, error) {
+	if conditions.IsZero() {
+		return nil, nil
+	}
+	if conditions != (osi.Conditions{IfNoneMatch: "*"}) {
+		return nil, &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional put supports IfNoneMatch \"*\" only"}
+	}
+	header := make(http.Header)
+	header.Set("x-cos-forbid-overwrite", "true")
+	return &header, nil
+}
+
+func putHeaderOptions(options *osi.PutOptions) *cos.ObjectPutHeaderOptions {
+	opts := &cos.ObjectPutHeaderOptions{
+		ContentType:        options.ContentType,
+		ContentEncoding:    options.ContentEncoding,
+		CacheControl:       options.CacheControl,
+		ContentDisposition: options.ContentDisposition,
+	}
+	if options.StorageClass != "" {
+		opts.XCosStorageClass = storageClasses.Provider(options.StorageClass)
+	}
+	if !options.Expires.IsZero() {
+		opts.Expires = options.Expires.UTC().Format(http.TimeFormat)
+	}
+	if len(options.Metadata) > 0 {
+		metadata := make(http.Header)
+		for key, value := range options.Metadata {
+			metadata.Set("x-cos-meta-"+key, value)
+		}
+		opts.XCosMetaXXX = &metadata
+	}
+	if len(options.Tags) > 0 {
+		header := make(http.Header)
+		header.Set("x-cos-tagging", osi.EncodeTags(options.Tags))
+		opts.XOptionHeader = &header
+	}
+	setEncryption(opts, options.Encryption)
+	return opts
+}
+
+func listedObject(bucket string, object cos.Object) *osi.ObjectInfo {
+	info := osi.NewObjectInfo(bucket, object.Key)
+	info.Size = object.Size
+	info.ETag = osi.TrimETag(object.ETag)
+	info.LastModified, _ = time.Parse(time.RFC3339, object.LastModified)
+	info.StorageClass = storageClass(object.StorageClass)
+	return info
+}
+
+// nextMarker picks the marker for the next page when the service omits
+// NextMarker, which it does for listings without a delimiter.
+func nextMarker(marker string, lastKey string, commonPrefixes []string) string {
+	if marker != "" {
+		return marker
+	}
+	for _, commonPrefix := range commonPrefixes {
+		if commonPrefix > lastKey {
+			lastKey = commonPrefix
+		}
+	}
+	return lastKey
 }
 
 type aclEnum struct {
@@ -0,0 +1,35 @@
+package cos
+
+import (
+	"github.com/burybell/osi"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"net/http"
+)
+
+const kmsKeyIDHeader = "x-cos-server-side-encryption-cos-kms-key-id"
+
+func setEncryption(opts *cos.ObjectPutHeaderOptions, encryption osi.Encryption) {
+	switch encryption.Mode {
+	case osi.EncryptionSSES3:
+		opts.XCosServerSideEncryption = "AES256"
+	case osi.EncryptionSSEKMS:
+		opts.XCosServerSideEncryption = "cos/kms"
+		if encryption.KMSKeyID != "" {
+			if opts.XOptionHeader == nil {
+				opts.XOptionHeader = &http.Header{}
+			}
+			opts.XOptionHeader.Set(kmsKeyIDHeader, encryption.KMSKeyID)
+		}
+	case osi.EncryptionSSEC:
+		opts.XCosSSECustomerAglo, opts.XCosSSECustomerKey, opts.XCosSSECustomerKeyMD5 = customerKey(encryption.CustomerKey)
+	}
+}
+
+// customerKey returns the SSE-C algorithm, key and key MD5 headers.
+func customerKey(key []byte) (string, string, string) {
+	if len(key) == 0 {
+		return "", "", ""
+	}
+	encoded, digest := osi.CustomerKeyHeaders(key)
+	return "AES256", encoded, digest
+}
@@ -0,0 +1,21 @@
+package cos
+
+import (
+	"github.com/burybell/osi"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// toError maps a cos-go-sdk-v5 error onto the osi error taxonomy.
+func toError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if cosErr, ok := cos.IsCOSError(err); ok {
+		var statusCode int
+		if cosErr.Response != nil {
+			statusCode = cosErr.Response.StatusCode
+		}
+		return osi.NewError(Name, cosErr.Code, statusCode, cosErr.RequestID, cosErr.Message, err)
+	}
+	return osi.TransportError(Name, err)
+}
@@ -0,0 +1,97 @@
+package cos
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"io"
+	"sort"
+	"time"
+)
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	options := osi.NewPutOptions(path, opts...)
+	if options.ACL == "" {
+		options.ACL = aclEnum{}.Default()
+	}
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return "", err
+	}
+	if err := options.Encryption.Validate(); err != nil {
+		return "", err
+	}
+	result, _, err := t.client.Object.InitiateMultipartUpload(ctx, path, &cos.InitiateMultipartUploadOptions{
+		ACLHeaderOptions: &cos.ACLHeaderOptions{
+			XCosACL: options.ACL,
+		},
+		ObjectPutHeaderOptions: putHeaderOptions(options),
+	})
+	if err != nil {
+		return "", toError(err)
+	}
+	return result.UploadID, nil
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	if err := osi.ValidatePartNumber(partNumber); err != nil {
+		return osi.Part{}, err
+	}
+	partOpts := &cos.ObjectUploadPartOptions{ContentLength: size}
+	partOpts.XCosSSECustomerAglo, partOpts.XCosSSECustomerKey, partOpts.XCosSSECustomerKeyMD5 = customerKey(osi.NewPutOptions(path, opts...).Encryption.CustomerKey)
+	resp, err := t.client.Object.UploadPart(ctx, path, uploadID, partNumber, reader, partOpts)
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	return osi.Part{PartNumber: partNumber, ETag: osi.TrimETag(resp.Header.Get("ETag")), Size: size}, nil
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	var parts = make([]osi.Part, 0)
+	var marker string
+	for {
+		result, _, err := t.client.Object.ListParts(ctx, path, uploadID, &cos.ObjectListPartsOptions{
+			PartNumberMarker: marker,
+		})
+		if err != nil {
+			return nil, toError(err)
+		}
+		for _, part := range result.Parts {
+			lastModified, _ := time.Parse(time.RFC3339, part.LastModified)
+			parts = append(parts, osi.Part{
+				PartNumber:   part.PartNumber,
+				ETag:         osi.TrimETag(part.ETag),
+				Size:         part.Size,
+				LastModified: lastModified,
+			})
+		}
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	conditionHeader, err := writeConditions(options.Conditions)
+	if err != nil {
+		return err
+	}
+	var completed = make([]cos.Object, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, cos.Object{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].PartNumber < completed[j].PartNumber
+	})
+	_, _, err = t.client.Object.CompleteMultipartUpload(ctx, path, uploadID, &cos.CompleteMultipartUploadOptions{
+		Parts:         completed,
+		XOptionHeader: conditionHeader,
+	})
+	return toError(err)
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	_, err := t.client.Object.AbortMultipartUpload(ctx, path, uploadID)
+	return toError(err)
+}
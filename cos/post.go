@@ -0,0 +1,51 @@
+package cos
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/burybell/osi"
+	"time"
+)
+
+// SignPostPolicy signs the policy with the COS form signature, which the SDK
+// implements for requests only. The signed key time is required by the policy
+// as q-sign-time and posted as q-key-time.
+func (t *bucket) SignPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	form, err := t.signPostPolicy(ctx, policy, expiredInDur)
+	osi.LogSignPostPolicy(ctx, t.config.Logger, Name, t.bucket, policy, expiredInDur, form, err)
+	return form, err
+}
+
+func (t *bucket) signPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	keyTime := fmt.Sprintf("%d;%d", now.Unix(), now.Add(expiredInDur).Unix())
+	fields := policy.FormFields("acl")
+	fields["q-sign-algorithm"] = "sha1"
+	fields["q-ak"] = t.config.KeyID
+	fields["q-sign-time"] = keyTime
+	document, err := policy.Document(t.bucket, now.Add(expiredInDur), fields)
+	if err != nil {
+		return nil, err
+	}
+	delete(fields, "q-sign-time")
+
+	digest := sha1.Sum(document)
+	signKey := hmacSHA1([]byte(t.config.Secret), keyTime)
+	fields["q-key-time"] = keyTime
+	fields["policy"] = base64.StdEncoding.EncodeToString(document)
+	fields["q-signature"] = hex.EncodeToString(hmacSHA1([]byte(hex.EncodeToString(signKey)), hex.EncodeToString(digest[:])))
+	return &osi.PostForm{URL: t.client.BaseURL.BucketURL.String() + "/", Fields: fields}, nil
+}
+
+func hmacSHA1(key []byte, data string) []byte {
+	mac := hmac.New(sha1.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
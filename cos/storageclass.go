@@ -0,0 +1,75 @@
+package cos
+
+import (
+	"context"
+	"fmt"
+	"github.com/burybell/osi"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"net/http"
+	"strings"
+)
+
+var storageClasses = osi.StorageClassNames{
+	osi.StorageClassStandard:    "STANDARD",
+	osi.StorageClassIA:          "STANDARD_IA",
+	osi.StorageClassArchive:     "ARCHIVE",
+	osi.StorageClassDeepArchive: "DEEP_ARCHIVE",
+}
+
+// storageClass returns the class of an object, which COS leaves out of the
+// headers of STANDARD objects.
+func storageClass(class string) string {
+	if class == "" {
+		return osi.StorageClassStandard
+	}
+	return storageClasses.Class(class)
+}
+
+// SetStorageClass keeps the content headers, metadata and managed key
+// encryption of the object. They are sent along with the copy, as multipart
+// copies of large objects do not carry them over.
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	resp, err := t.client.Object.Head(ctx, path, nil)
+	if err != nil {
+		return toError(err)
+	}
+	headers := &cos.ObjectCopyHeaderOptions{
+		CacheControl:             resp.Header.Get("Cache-Control"),
+		ContentDisposition:       resp.Header.Get("Content-Disposition"),
+		ContentEncoding:          resp.Header.Get("Content-Encoding"),
+		ContentType:              resp.Header.Get("Content-Type"),
+		Expires:                  resp.Header.Get("Expires"),
+		XCosMetadataDirective:    "Replaced",
+		XCosStorageClass:         storageClasses.Provider(class),
+		XCosServerSideEncryption: resp.Header.Get("x-cos-server-side-encryption"),
+	}
+	metadata := make(http.Header)
+	for key, values := range resp.Header {
+		if strings.HasPrefix(strings.ToLower(key), "x-cos-meta-") {
+			metadata[key] = values
+		}
+	}
+	headers.XCosMetaXXX = &metadata
+	if keyID := resp.Header.Get(kmsKeyIDHeader); keyID != "" {
+		headers.XOptionHeader = &http.Header{}
+		headers.XOptionHeader.Set(kmsKeyIDHeader, keyID)
+	}
+	sourceURL := fmt.Sprintf("%s.cos.%s.myqcloud.com/%s", t.bucket, t.config.Region, path)
+	_, _, err = t.client.Object.MultiCopy(ctx, path, sourceURL, &cos.MultiCopyOptions{
+		OptCopy:        &cos.ObjectCopyOptions{ObjectCopyHeaderOptions: headers},
+		ThreadPoolSize: 4,
+	})
+	return toError(err)
+}
+
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	if err := osi.ValidateRestore(days, tier); err != nil {
+		return err
+	}
+	opts := &cos.ObjectRestoreOptions{Days: days}
+	if tier != "" {
+		opts.Tier = &cos.CASJobParameters{Tier: string(tier)}
+	}
+	_, err := t.client.Object.PostRestore(ctx, path, opts)
+	return toError(err)
+}
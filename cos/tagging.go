@@ -0,0 +1,36 @@
+package cos
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	result, _, err := t.client.Object.GetTagging(ctx, path)
+	if err != nil {
+		return nil, toError(err)
+	}
+	var tags = make(map[string]string, len(result.TagSet))
+	for _, tag := range result.TagSet {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tags map[string]string) error {
+	if err := osi.ValidateTags(tags); err != nil {
+		return err
+	}
+	var opts = &cos.ObjectPutTaggingOptions{TagSet: make([]cos.ObjectTaggingTag, 0, len(tags))}
+	for key, value := range tags {
+		opts.TagSet = append(opts.TagSet, cos.ObjectTaggingTag{Key: key, Value: value})
+	}
+	_, err := t.client.Object.PutTagging(ctx, path, opts)
+	return toError(err)
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	_, err := t.client.Object.DeleteTagging(ctx, path)
+	return toError(err)
+}
@@ -0,0 +1,84 @@
+package cos
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"strings"
+	"time"
+)
+
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	keyMarker, versionIDMarker, err := osi.ParseVersionToken(options.ContinuationToken)
+	if err != nil {
+		return nil, err
+	}
+	if keyMarker == "" {
+		keyMarker = options.StartAfter
+	}
+	resp, _, err := t.client.Bucket.GetObjectVersions(ctx, &cos.BucketGetObjectVersionsOptions{
+		Prefix:          prefix,
+		Delimiter:       options.Delimiter,
+		KeyMarker:       keyMarker,
+		VersionIdMarker: versionIDMarker,
+		MaxKeys:         options.PageSize,
+	})
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.VersionPage{
+		Versions:       make([]*osi.ObjectVersion, 0, len(resp.Version)+len(resp.DeleteMarker)),
+		CommonPrefixes: resp.CommonPrefixes,
+		IsTruncated:    resp.IsTruncated,
+	}
+	if resp.IsTruncated {
+		page.NextContinuationToken = osi.VersionToken(resp.NextKeyMarker, resp.NextVersionIdMarker)
+	}
+	for _, version := range resp.Version {
+		if strings.HasSuffix(version.Key, "/") {
+			continue
+		}
+		info := osi.NewObjectInfo(t.bucket, version.Key)
+		info.Size = version.Size
+		info.ETag = osi.TrimETag(version.ETag)
+		info.VersionID = version.VersionId
+		info.LastModified, _ = time.Parse(time.RFC3339, version.LastModified)
+		info.StorageClass = storageClass(version.StorageClass)
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: version.IsLatest})
+	}
+	for _, marker := range resp.DeleteMarker {
+		info := osi.NewObjectInfo(t.bucket, marker.Key)
+		info.VersionID = marker.VersionId
+		info.LastModified, _ = time.Parse(time.RFC3339, marker.LastModified)
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: marker.IsLatest, IsDeleteMarker: true})
+	}
+	osi.SortVersions(page.Versions)
+	return page, nil
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	result, _, err := t.client.Bucket.GetVersioning(ctx)
+	if err != nil {
+		return osi.VersioningOff, toError(err)
+	}
+	return osi.VersioningStatus(result.Status), nil
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	_, err := t.client.Bucket.PutVersioning(ctx, &cos.BucketPutVersionOptions{Status: string(status)})
+	return toError(err)
+}
+
+// versionID turns an optional version id into the trailing argument taken by
+// the object calls of the SDK.
+func versionID(id string) []string {
+	if id == "" {
+		return nil
+	}
+	return []string{id}
+}
@@ -0,0 +1,204 @@
+package osi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const DefaultPartRetries = 3
+
+type DownloadOptions struct {
+	PartSize    int64
+	Concurrency int
+	PartRetries int
+	CustomerKey []byte
+	// Logger receives a warning for every part fetched again.
+	Logger Logger
+}
+
+type DownloadOption func(opts *DownloadOptions)
+
+func WithDownloadPartSize(partSize int64) DownloadOption {
+	return func(opts *DownloadOptions) {
+		opts.PartSize = partSize
+	}
+}
+
+func WithDownloadConcurrency(concurrency int) DownloadOption {
+	return func(opts *DownloadOptions) {
+		opts.Concurrency = concurrency
+	}
+}
+
+// WithPartRetries sets how many times a failed part is fetched again before
+// the download gives up.
+func WithPartRetries(retries int) DownloadOption {
+	return func(opts *DownloadOptions) {
+		opts.PartRetries = retries
+	}
+}
+
+// WithDownloadCustomerKey downloads an object stored with SSE-C under key.
+func WithDownloadCustomerKey(key []byte) DownloadOption {
+	return func(opts *DownloadOptions) {
+		opts.CustomerKey = key
+	}
+}
+
+func WithDownloadLogger(logger Logger) DownloadOption {
+	return func(opts *DownloadOptions) {
+		opts.Logger = logger
+	}
+}
+
+func NewDownloadOptions(opts ...DownloadOption) *DownloadOptions {
+	options := &DownloadOptions{PartRetries: DefaultPartRetries}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.PartSize <= 0 {
+		options.PartSize = DefaultPartSize
+	}
+	if options.Concurrency < 1 {
+		options.Concurrency = DefaultConcurrency
+	}
+	if options.PartRetries < 0 {
+		options.PartRetries = 0
+	}
+	return options
+}
+
+// Download writes the object at path into w and returns its size. The object
+// is fetched as concurrent ranged GETs of DownloadOptions.PartSize, each
+// retried on its own; a backend that answers GetObjectRange with NotSupported
+// is read with a single GetObject instead. Every request is pinned to the ETag
+// the object had when the download started, so that the download fails with
+// PreconditionFailed rather than mix the parts of two objects when it is
+// replaced meanwhile.
+func Download(ctx context.Context, bucket BucketObject, path string, w io.WriterAt, opts ...DownloadOption) (int64, error) {
+	options := NewDownloadOptions(opts...)
+	var getOpts []GetOption
+	if options.CustomerKey != nil {
+		getOpts = append(getOpts, WithCustomerKey(options.CustomerKey))
+	}
+	info, err := bucket.StatObject(ctx, path, getOpts...)
+	if err != nil {
+		return 0, err
+	}
+	if info.ETag != "" {
+		getOpts = append(getOpts, WithGetConditions(Conditions{IfMatch: info.ETag}))
+	}
+	ranges := SplitRange(info.Size, options.PartSize)
+	if len(ranges) == 0 {
+		return 0, nil
+	}
+
+	// the first part tells whether the backend serves ranges at all
+	err = downloadPart(ctx, bucket, path, w, ranges[0], options, getOpts)
+	if errors.Is(err, NotSupported) {
+		return downloadStream(ctx, bucket, path, w, getOpts)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	partCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	parts := make(chan Range)
+	for i := 0; i < options.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rng := range parts {
+				if err := downloadPart(partCtx, bucket, path, w, rng, options, getOpts); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+feed:
+	for _, rng := range ranges[1:] {
+		select {
+		case parts <- rng:
+		case <-partCtx.Done():
+			break feed
+		}
+	}
+	close(parts)
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return info.Size, nil
+}
+
+func downloadPart(ctx context.Context, bucket BucketObject, path string, w io.WriterAt, rng Range, options *DownloadOptions, opts []GetOption) error {
+	var err error
+	for attempt := 0; attempt <= options.PartRetries; attempt++ {
+		if attempt > 0 {
+			Log(ctx, options.Logger, LogWarn, "retrying part", Field("key", path), Field("range", rng.HeaderValue()), Field("attempt", attempt+1), Field("error", err))
+			select {
+			case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		err = fetchPart(ctx, bucket, path, w, rng, opts)
+		if err == nil || errors.Is(err, NotSupported) || errors.Is(err, ObjectNotFound) || errors.Is(err, InvalidRange) || errors.Is(err, PreconditionFailed) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func fetchPart(ctx context.Context, bucket BucketObject, path string, w io.WriterAt, rng Range, opts []GetOption) error {
+	object, err := bucket.GetObjectRange(ctx, path, rng, opts...)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+	n, err := io.Copy(&offsetWriter{w: w, offset: rng.Offset}, io.LimitReader(object, rng.Length))
+	if err != nil {
+		return err
+	}
+	if n != rng.Length {
+		return fmt.Errorf("range %s: %w", rng.HeaderValue(), io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+func downloadStream(ctx context.Context, bucket BucketObject, path string, w io.WriterAt, opts []GetOption) (int64, error) {
+	object, err := bucket.GetObject(ctx, path, opts...)
+	if err != nil {
+		return 0, err
+	}
+	defer object.Close()
+	return io.Copy(&offsetWriter{w: w}, object)
+}
+
+type offsetWriter struct {
+	w      io.WriterAt
+	offset int64
+}
+
+func (t *offsetWriter) Write(p []byte) (int, error) {
+	n, err := t.w.WriteAt(p, t.offset)
+	t.offset += int64(n)
+	return n, err
+}
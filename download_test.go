@@ -0,0 +1,64 @@
+package osi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/local"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+type flakyBucket struct {
+	osi.Bucket
+	failures int32
+	ranges   bool
+}
+
+func (t *flakyBucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	if !t.ranges {
+		return nil, osi.NotSupported
+	}
+	if atomic.AddInt32(&t.failures, -1) >= 0 {
+		return nil, errors.New("connection reset")
+	}
+	return t.Bucket.GetObjectRange(ctx, path, rng, opts...)
+}
+
+func TestDownload(t *testing.T) {
+	ctx := context.Background()
+	store := local.MustNewObjectStore(local.Config{BasePath: t.TempDir()})
+	assert.NoError(t, store.CreateBucket(ctx, "test"))
+	bucket := store.Bucket("test")
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	assert.NoError(t, bucket.PutObject(ctx, "test/download.txt", bytes.NewReader(content)))
+
+	for _, flaky := range []*flakyBucket{{Bucket: bucket, ranges: true, failures: 2}, {Bucket: bucket}} {
+		file, err := os.Create(filepath.Join(t.TempDir(), "download.txt"))
+		assert.NoError(t, err)
+		n, err := osi.Download(ctx, flaky, "test/download.txt", file, osi.WithDownloadPartSize(1024), osi.WithDownloadConcurrency(3))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), n)
+		assert.NoError(t, file.Close())
+		bs, err := os.ReadFile(file.Name())
+		assert.NoError(t, err)
+		assert.True(t, bytes.Equal(content, bs))
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "download.txt"))
+	assert.NoError(t, err)
+	defer file.Close()
+	_, err = osi.Download(ctx, &flakyBucket{Bucket: bucket, ranges: true, failures: 10}, "test/download.txt", file, osi.WithPartRetries(1))
+	assert.Error(t, err)
+	_, err = osi.Download(ctx, bucket, "test/not-exist.txt", file)
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	for _, replacing := range []osi.Bucket{&replacingBucket{Bucket: bucket, replacements: 1}, &flakyBucket{Bucket: &replacingBucket{Bucket: bucket, replacements: 1}}} {
+		assert.NoError(t, bucket.PutObject(ctx, "test/download.txt", bytes.NewReader(content)))
+		_, err = osi.Download(ctx, replacing, "test/download.txt", file, osi.WithDownloadPartSize(1024))
+		assert.ErrorIs(t, err, osi.PreconditionFailed)
+	}
+}
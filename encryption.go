@@ -0,0 +1,91 @@
+package osi
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// EncryptionMode is the server-side encryption of an object.
+type EncryptionMode string
+
+const (
+	EncryptionNone EncryptionMode = ""
+	// EncryptionSSES3 encrypts with keys the provider manages.
+	EncryptionSSES3 EncryptionMode = "SSE-S3"
+	// EncryptionSSEKMS encrypts with a key of the key management service of
+	// the provider.
+	EncryptionSSEKMS EncryptionMode = "SSE-KMS"
+	// EncryptionSSEC encrypts with a key the client sends with every request
+	// and the provider does not keep, so reads need the key again.
+	EncryptionSSEC EncryptionMode = "SSE-C"
+)
+
+// CustomerKeySize is the size of SSE-C keys, which are AES-256 keys.
+const CustomerKeySize = 32
+
+// Encryption selects the server-side encryption of an object.
+type Encryption struct {
+	Mode EncryptionMode
+	// KMSKeyID selects the SSE-KMS key; the provider default when empty.
+	KMSKeyID string
+	// CustomerKey is the SSE-C key.
+	CustomerKey []byte
+}
+
+func SSES3() Encryption {
+	return Encryption{Mode: EncryptionSSES3}
+}
+
+func SSEKMS(keyID string) Encryption {
+	return Encryption{Mode: EncryptionSSEKMS, KMSKeyID: keyID}
+}
+
+func SSEC(key []byte) Encryption {
+	return Encryption{Mode: EncryptionSSEC, CustomerKey: key}
+}
+
+func (t Encryption) Validate() error {
+	if t.Mode != EncryptionSSEKMS && t.KMSKeyID != "" {
+		return fmt.Errorf("%w: KMS key id without SSE-KMS", InvalidEncryption)
+	}
+	if t.Mode != EncryptionSSEC && len(t.CustomerKey) != 0 {
+		return fmt.Errorf("%w: customer key without SSE-C", InvalidEncryption)
+	}
+	switch t.Mode {
+	case EncryptionNone, EncryptionSSES3, EncryptionSSEKMS:
+		return nil
+	case EncryptionSSEC:
+		return ValidateCustomerKey(t.CustomerKey)
+	}
+	return fmt.Errorf("%w: unknown mode %q", InvalidEncryption, t.Mode)
+}
+
+func ValidateCustomerKey(key []byte) error {
+	if len(key) != CustomerKeySize {
+		return fmt.Errorf("%w: customer key has %d bytes, %d are required", InvalidEncryption, len(key), CustomerKeySize)
+	}
+	return nil
+}
+
+// CustomerKeyHeaders returns the base64 encoded key and key MD5 sent in the
+// SSE-C headers.
+func CustomerKeyHeaders(key []byte) (string, string) {
+	digest := md5.Sum(key)
+	return base64.StdEncoding.EncodeToString(key), base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// ParseEncryptionMode maps the server-side encryption response headers of
+// the providers, the algorithm and the SSE-C algorithm, onto EncryptionMode.
+func ParseEncryptionMode(algorithm string, customerAlgorithm string) EncryptionMode {
+	switch {
+	case customerAlgorithm != "":
+		return EncryptionSSEC
+	case algorithm == "":
+		return EncryptionNone
+	case strings.Contains(strings.ToLower(algorithm), "kms"):
+		return EncryptionSSEKMS
+	}
+	return EncryptionSSES3
+}
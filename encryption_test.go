@@ -0,0 +1,36 @@
+package osi_test
+
+import (
+	"bytes"
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEncryption_Validate(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, osi.CustomerKeySize)
+	assert.NoError(t, osi.Encryption{}.Validate())
+	assert.NoError(t, osi.SSES3().Validate())
+	assert.NoError(t, osi.SSEKMS("").Validate())
+	assert.NoError(t, osi.SSEKMS("key-id").Validate())
+	assert.NoError(t, osi.SSEC(key).Validate())
+	assert.ErrorIs(t, osi.SSEC(key[1:]).Validate(), osi.InvalidEncryption)
+	assert.ErrorIs(t, osi.Encryption{Mode: osi.EncryptionSSES3, KMSKeyID: "key-id"}.Validate(), osi.InvalidEncryption)
+	assert.ErrorIs(t, osi.Encryption{Mode: osi.EncryptionSSES3, CustomerKey: key}.Validate(), osi.InvalidEncryption)
+	assert.ErrorIs(t, osi.Encryption{Mode: "AES"}.Validate(), osi.InvalidEncryption)
+}
+
+func TestParseEncryptionMode(t *testing.T) {
+	assert.Equal(t, osi.EncryptionNone, osi.ParseEncryptionMode("", ""))
+	assert.Equal(t, osi.EncryptionSSES3, osi.ParseEncryptionMode("AES256", ""))
+	assert.Equal(t, osi.EncryptionSSEKMS, osi.ParseEncryptionMode("aws:kms", ""))
+	assert.Equal(t, osi.EncryptionSSEKMS, osi.ParseEncryptionMode("KMS", ""))
+	assert.Equal(t, osi.EncryptionSSEKMS, osi.ParseEncryptionMode("cos/kms", ""))
+	assert.Equal(t, osi.EncryptionSSEC, osi.ParseEncryptionMode("", "AES256"))
+}
+
+func TestCustomerKeyHeaders(t *testing.T) {
+	key, keyMD5 := osi.CustomerKeyHeaders([]byte("0123456789abcdef0123456789abcdef"))
+	assert.Equal(t, "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=", key)
+	assert.Len(t, keyMD5, 24)
+}
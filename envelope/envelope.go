@@ -0,0 +1,238 @@
+package envelope
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/burybell/osi"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	Name = "envelope"
+
+	DefaultChunkSize = 64 << 10
+	MaxChunkSize     = 16 << 20
+
+	// The envelope of an object is kept in its user metadata.
+	metaKey       = "osi-envelope-key"
+	metaKeyID     = "osi-envelope-key-id"
+	metaChunkSize = "osi-envelope-chunk-size"
+)
+
+type Options struct {
+	ChunkSize int
+}
+
+type Option func(opts *Options)
+
+// WithChunkSize sets how many plaintext bytes are sealed together. Ranged
+// reads fetch and open whole chunks, and every chunk adds a 16 byte tag.
+func WithChunkSize(chunkSize int) Option {
+	return func(opts *Options) {
+		opts.ChunkSize = chunkSize
+	}
+}
+
+func NewOptions(opts ...Option) *Options {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = DefaultChunkSize
+	}
+	if options.ChunkSize > MaxChunkSize {
+		options.ChunkSize = MaxChunkSize
+	}
+	return options
+}
+
+// bucket encrypts bodies on the client. Each object is sealed with a data key
+// of its own, which is stored wrapped by the KeyProvider in the metadata of
+// the object, and opened again on reads. Objects without an envelope are
+// passed through as they are stored.
+//
+// Listings report the stored sizes; StatObject reports the plaintext size.
+// Presigned URLs and direct multipart uploads would bypass the encryption and
+// are not supported, while PutObject still uploads large bodies in parts.
+type bucket struct {
+	osi.Bucket
+	keys    KeyProvider
+	options *Options
+}
+
+// NewBucket wraps bkt so that bodies are encrypted before they leave the
+// process, with data keys wrapped by keys.
+func NewBucket(bkt osi.Bucket, keys KeyProvider, opts ...Option) osi.Bucket {
+	return &bucket{Bucket: bkt, keys: keys, options: NewOptions(opts...)}
+}
+
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return err
+	}
+	keyID, wrapped, err := t.keys.WrapKey(ctx, dataKey)
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{
+		metaKey:       base64.StdEncoding.EncodeToString(wrapped),
+		metaKeyID:     keyID,
+		metaChunkSize: strconv.Itoa(t.options.ChunkSize),
+	}
+	sealed := newSealer(reader, aead, t.options.ChunkSize)
+	return t.Bucket.PutObject(ctx, path, sealed, append(opts[:len(opts):len(opts)], osi.WithMetadata(metadata))...)
+}
+
+func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
+}
+
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	return t.read(ctx, path, nil, opts)
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	if err := rng.Validate(); err != nil {
+		return nil, err
+	}
+	return t.read(ctx, path, &rng, opts)
+}
+
+func (t *bucket) read(ctx context.Context, path string, rng *osi.Range, opts []osi.GetOption) (osi.Object, error) {
+	return osi.GetPinned(ctx, t.Bucket, path, func(info *osi.ObjectInfo, opts []osi.GetOption) (osi.Object, error) {
+		return t.open(ctx, path, info, rng, opts)
+	}, opts...)
+}
+
+func (t *bucket) open(ctx context.Context, path string, info *osi.ObjectInfo, rng *osi.Range, opts []osi.GetOption) (osi.Object, error) {
+	chunkSize, sealed, err := envelopeChunkSize(info)
+	if err != nil {
+		return nil, err
+	}
+	if !sealed {
+		if rng != nil {
+			return t.Bucket.GetObjectRange(ctx, path, *rng, opts...)
+		}
+		return t.Bucket.GetObject(ctx, path, opts...)
+	}
+
+	aead, err := t.unwrap(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	size, err := plainSize(info.Size, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	last := int64(0)
+	if size > 0 {
+		last = (size - 1) / chunkSize
+	}
+	if rng == nil {
+		object, err := t.Bucket.GetObject(ctx, path, opts...)
+		if err != nil {
+			return nil, err
+		}
+		body := &opener{reader: object, aead: aead, last: last, limit: size, sealed: make([]byte, chunkSize+tagSize)}
+		return osi.NewObject(object.Bucket(), object.ObjectPath(), object.ObjectACL(), &readCloser{Reader: body, Closer: object}), nil
+	}
+
+	offset, length, err := rng.Resolve(size)
+	if err != nil {
+		return nil, err
+	}
+	first, end := offset/chunkSize, (offset+length-1)/chunkSize
+	start, stop := first*(chunkSize+tagSize), (end+1)*(chunkSize+tagSize)
+	if stop > info.Size {
+		stop = info.Size
+	}
+	object, err := t.Bucket.GetObjectRange(ctx, path, osi.NewRange(start, stop-start), opts...)
+	if err != nil {
+		return nil, err
+	}
+	body := &opener{reader: object, aead: aead, index: first, last: last, skip: offset - first*chunkSize, limit: length, sealed: make([]byte, chunkSize+tagSize)}
+	return osi.NewObject(object.Bucket(), object.ObjectPath(), object.ObjectACL(), &readCloser{Reader: body, Closer: object}), nil
+}
+
+func (t *bucket) unwrap(ctx context.Context, info *osi.ObjectInfo) (cipher.AEAD, error) {
+	wrapped, err := base64.StdEncoding.DecodeString(info.Metadata[metaKey])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", osi.InvalidEncryption, err)
+	}
+	dataKey, err := t.keys.UnwrapKey(ctx, info.Metadata[metaKeyID], wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(dataKey)
+}
+
+// envelopeChunkSize returns the chunk size of a sealed object, and false for
+// objects stored without an envelope.
+func envelopeChunkSize(info *osi.ObjectInfo) (int64, bool, error) {
+	if _, ok := info.Metadata[metaKey]; !ok {
+		return 0, false, nil
+	}
+	chunkSize, err := strconv.ParseInt(info.Metadata[metaChunkSize], 10, 64)
+	if err != nil || chunkSize <= 0 || chunkSize > MaxChunkSize {
+		return 0, false, fmt.Errorf("%w: invalid chunk size %q", osi.InvalidEncryption, info.Metadata[metaChunkSize])
+	}
+	return chunkSize, true, nil
+}
+
+// StatObject reports the plaintext size of sealed objects and leaves their
+// envelope out of the metadata.
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	info, err := t.Bucket.StatObject(ctx, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	chunkSize, sealed, err := envelopeChunkSize(info)
+	if err != nil || !sealed {
+		return info, err
+	}
+	info.Size, err = plainSize(info.Size, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	delete(info.Metadata, metaKey)
+	delete(info.Metadata, metaKeyID)
+	delete(info.Metadata, metaChunkSize)
+	return info, nil
+}
+
+func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
+	info, err := t.StatObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return osi.NewSize(info.Size), nil
+}
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	return "", &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "multipart upload, use PutObject"}
+}
+
+// SignURL signs requests other than GET and PUT, which would read or write
+// the body unencrypted.
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	if method == http.MethodGet || method == http.MethodPut {
+		return "", &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "presigned " + method}
+	}
+	return t.Bucket.SignURL(ctx, path, method, expiredInDur, opts...)
+}
+
+func (t *bucket) SignPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	return nil, &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "post policy"}
+}
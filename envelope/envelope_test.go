@@ -0,0 +1,275 @@
+package envelope_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/envelope"
+	"github.com/burybell/osi/local"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	ctx    = context.Background()
+	plain  osi.Bucket
+	master = bytes.Repeat([]byte{7}, envelope.KeySize)
+)
+
+func init() {
+	dir, err := os.MkdirTemp("", "osi-envelope-")
+	if err != nil {
+		panic(err)
+	}
+	store := local.MustNewObjectStore(local.Config{BasePath: dir})
+	if err := store.CreateBucket(ctx, "example"); err != nil {
+		panic(err)
+	}
+	plain = store.Bucket("example")
+}
+
+func newBucket(t *testing.T, opts ...envelope.Option) osi.Bucket {
+	keys, err := envelope.NewStaticKey("master", master)
+	assert.NoError(t, err)
+	return envelope.NewBucket(plain, keys, opts...)
+}
+
+// reader returns a func reading the whole of the object a get returns.
+func reader(t *testing.T) func(osi.Object, error) []byte {
+	return func(object osi.Object, err error) []byte {
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		defer object.Close()
+		bs, err := io.ReadAll(object)
+		assert.NoError(t, err)
+		return bs
+	}
+}
+
+func TestBucket_PutObject(t *testing.T) {
+	readAll := reader(t)
+	bucket := newBucket(t, envelope.WithChunkSize(16))
+	for _, size := range []int{0, 1, 15, 16, 17, 53, 64} {
+		content := make([]byte, size)
+		for i := range content {
+			content[i] = byte(i)
+		}
+		path := fmt.Sprintf("test/sealed-%d.bin", size)
+		assert.NoError(t, bucket.PutObject(ctx, path, bytes.NewReader(content), osi.WithMetadata(map[string]string{"team": "storage"})))
+
+		assert.Equal(t, content, readAll(bucket.GetObject(ctx, path)))
+		stored := readAll(plain.GetObject(ctx, path))
+		assert.NotEqual(t, content, stored)
+		assert.Len(t, stored, size+16*((size+15)/16+btoi(size == 0)))
+
+		info, err := bucket.StatObject(ctx, path)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(size), info.Size)
+		assert.Equal(t, map[string]string{"team": "storage"}, info.Metadata)
+		assert.NoError(t, bucket.DeleteObject(ctx, path))
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestBucket_GetObjectRange(t *testing.T) {
+	readAll := reader(t)
+	bucket := newBucket(t, envelope.WithChunkSize(16))
+	content := []byte(strings.Repeat("0123456789", 5))
+	assert.NoError(t, bucket.PutObject(ctx, "test/range.txt", bytes.NewReader(content)))
+	defer bucket.DeleteObject(ctx, "test/range.txt")
+
+	for offset := 0; offset < len(content); offset++ {
+		for _, length := range []int{1, 5, 16, 17, 33} {
+			if offset+length > len(content) {
+				continue
+			}
+			bs := readAll(bucket.GetObjectRange(ctx, "test/range.txt", osi.NewRange(int64(offset), int64(length))))
+			assert.Equal(t, content[offset:offset+length], bs, "offset %d length %d", offset, length)
+		}
+	}
+	assert.Equal(t, content[20:], readAll(bucket.GetObjectRange(ctx, "test/range.txt", osi.RangeFrom(20))))
+	assert.Equal(t, content[len(content)-7:], readAll(bucket.GetObjectRange(ctx, "test/range.txt", osi.RangeLast(7))))
+	_, err := bucket.GetObjectRange(ctx, "test/range.txt", osi.NewRange(int64(len(content)), 1))
+	assert.ErrorIs(t, err, osi.InvalidRange)
+
+	w := &buffer{}
+	n, err := osi.Download(ctx, bucket, "test/range.txt", w, osi.WithDownloadPartSize(7))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), n)
+	assert.Equal(t, content, w.bs)
+}
+
+type buffer struct {
+	bs []byte
+}
+
+func (t *buffer) WriteAt(p []byte, off int64) (int, error) {
+	if end := int(off) + len(p); end > len(t.bs) {
+		t.bs = append(t.bs, make([]byte, end-len(t.bs))...)
+	}
+	return copy(t.bs[off:], p), nil
+}
+
+func TestBucket_Tampered(t *testing.T) {
+	readAll := reader(t)
+	bucket := newBucket(t, envelope.WithChunkSize(16))
+	assert.NoError(t, bucket.PutObject(ctx, "test/tampered.txt", strings.NewReader(strings.Repeat("secret", 10))))
+	defer bucket.DeleteObject(ctx, "test/tampered.txt")
+	info, err := plain.StatObject(ctx, "test/tampered.txt")
+	assert.NoError(t, err)
+	stored := readAll(plain.GetObject(ctx, "test/tampered.txt"))
+
+	// a flipped bit and a dropped last chunk are both detected
+	for _, body := range [][]byte{
+		append(append([]byte{}, stored[:20]...), append([]byte{stored[20] ^ 1}, stored[21:]...)...),
+		stored[:64],
+	} {
+		assert.NoError(t, plain.PutObject(ctx, "test/tampered.txt", bytes.NewReader(body), osi.WithMetadata(info.Metadata)))
+		object, err := bucket.GetObject(ctx, "test/tampered.txt")
+		assert.NoError(t, err)
+		_, err = io.ReadAll(object)
+		assert.ErrorIs(t, err, osi.InvalidEncryption)
+		_ = object.Close()
+	}
+}
+
+func TestBucket_Plain(t *testing.T) {
+	readAll := reader(t)
+	bucket := newBucket(t)
+	assert.NoError(t, plain.PutObject(ctx, "test/plain.txt", strings.NewReader("some text")))
+	defer plain.DeleteObject(ctx, "test/plain.txt")
+	assert.Equal(t, []byte("some text"), readAll(bucket.GetObject(ctx, "test/plain.txt")))
+	assert.Equal(t, []byte("text"), readAll(bucket.GetObjectRange(ctx, "test/plain.txt", osi.RangeFrom(5))))
+	size, err := bucket.GetObjectSize(ctx, "test/plain.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), size.Size())
+}
+
+func TestBucket_Conditions(t *testing.T) {
+	readAll := reader(t)
+	bucket := newBucket(t)
+	assert.NoError(t, bucket.PutObject(ctx, "test/conditions.txt", strings.NewReader("some text")))
+	defer bucket.DeleteObject(ctx, "test/conditions.txt")
+	info, err := bucket.StatObject(ctx, "test/conditions.txt")
+	assert.NoError(t, err)
+	_, err = bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfNoneMatch: info.ETag}))
+	assert.ErrorIs(t, err, osi.NotModified)
+	_, err = bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfMatch: "other"}))
+	assert.ErrorIs(t, err, osi.PreconditionFailed)
+	assert.Equal(t, []byte("some text"), readAll(bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfMatch: info.ETag}))))
+}
+
+// replacingBucket replaces the object right after its metadata is read.
+type replacingBucket struct {
+	osi.Bucket
+	replace func() error
+}
+
+func (t *replacingBucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	info, err := t.Bucket.StatObject(ctx, path, opts...)
+	if err == nil && t.replace != nil {
+		err, t.replace = t.replace(), nil
+	}
+	return info, err
+}
+
+func TestBucket_Replaced(t *testing.T) {
+	readAll := reader(t)
+	bucket := newBucket(t, envelope.WithChunkSize(16))
+	assert.NoError(t, bucket.PutObject(ctx, "test/replaced.txt", strings.NewReader("some text")))
+	defer bucket.DeleteObject(ctx, "test/replaced.txt")
+
+	// the body read after the replacement is sealed under another data key
+	keys, err := envelope.NewStaticKey("master", master)
+	assert.NoError(t, err)
+	replacing := &replacingBucket{Bucket: plain}
+	replaced := envelope.NewBucket(replacing, keys, envelope.WithChunkSize(16))
+	replacing.replace = func() error {
+		return bucket.PutObject(ctx, "test/replaced.txt", strings.NewReader("other text"))
+	}
+	assert.Equal(t, []byte("other text"), readAll(replaced.GetObject(ctx, "test/replaced.txt")))
+	replacing.replace = func() error {
+		return bucket.PutObject(ctx, "test/replaced.txt", strings.NewReader("some text again"))
+	}
+	assert.Equal(t, []byte("text"), readAll(replaced.GetObjectRange(ctx, "test/replaced.txt", osi.NewRange(5, 4))))
+}
+
+func TestBucket_NotSupported(t *testing.T) {
+	bucket := newBucket(t)
+	_, err := bucket.InitiateMultipartUpload(ctx, "test/multipart.txt")
+	assert.ErrorIs(t, err, osi.NotSupported)
+	_, err = bucket.SignURL(ctx, "test/example.txt", http.MethodGet, time.Minute)
+	assert.ErrorIs(t, err, osi.NotSupported)
+	_, err = bucket.SignPostPolicy(ctx, osi.PostPolicy{Key: "test/example.txt"}, time.Minute)
+	assert.ErrorIs(t, err, osi.NotSupported)
+}
+
+func TestKeyring(t *testing.T) {
+	readAll := reader(t)
+	keyring := envelope.NewKeyring()
+	assert.NoError(t, keyring.Rotate("2023", master))
+	old := envelope.NewBucket(plain, keyring)
+	assert.NoError(t, old.PutObject(ctx, "test/rotated.txt", strings.NewReader("some text")))
+	defer plain.DeleteObject(ctx, "test/rotated.txt")
+
+	next := bytes.Repeat([]byte{8}, envelope.KeySize)
+	assert.NoError(t, keyring.Rotate("2024", next))
+	assert.Equal(t, "2024", keyring.Current())
+	assert.Equal(t, []byte("some text"), readAll(old.GetObject(ctx, "test/rotated.txt")))
+
+	// a ring without the retired key cannot open the object
+	retired, err := envelope.NewStaticKey("2024", next)
+	assert.NoError(t, err)
+	_, err = envelope.NewBucket(plain, retired).GetObject(ctx, "test/rotated.txt")
+	assert.ErrorIs(t, err, osi.InvalidEncryption)
+	// nor can a key of the same id with other bytes
+	forged, err := envelope.NewStaticKey("2023", next)
+	assert.NoError(t, err)
+	_, err = envelope.NewBucket(plain, forged).GetObject(ctx, "test/rotated.txt")
+	assert.ErrorIs(t, err, osi.InvalidEncryption)
+
+	assert.ErrorIs(t, keyring.Rotate("short", master[1:]), osi.InvalidEncryption)
+	assert.ErrorIs(t, keyring.Rotate("", master), osi.InvalidEncryption)
+}
+
+func TestNewKeyFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "osi-keys-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys")
+	content := fmt.Sprintf("# keys, current last\n2023 %s\n\n2024 %s\n",
+		base64.StdEncoding.EncodeToString(master), base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, envelope.KeySize)))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	keyring, err := envelope.NewKeyFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024", keyring.Current())
+
+	keyID, wrapped, err := keyring.WrapKey(ctx, master)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024", keyID)
+	dataKey, err := keyring.UnwrapKey(ctx, keyID, wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, master, dataKey)
+
+	assert.NoError(t, os.WriteFile(path, []byte("2023\n"), 0600))
+	_, err = envelope.NewKeyFile(path)
+	assert.Error(t, err)
+	assert.NoError(t, os.WriteFile(path, []byte("# empty\n"), 0600))
+	_, err = envelope.NewKeyFile(path)
+	assert.ErrorIs(t, err, osi.InvalidEncryption)
+}
@@ -0,0 +1,172 @@
+package envelope
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/burybell/osi"
+	"os"
+	"strings"
+	"sync"
+)
+
+// KeySize is the size of master keys and data keys, which are AES-256 keys.
+const KeySize = 32
+
+// KeyProvider wraps the data keys objects are encrypted with under master keys
+// it holds. The key id returned by WrapKey is stored with the object and
+// handed back to UnwrapKey, so that objects stay readable after the master
+// key used for new objects changes.
+type KeyProvider interface {
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Keyring is a KeyProvider holding master keys by id. Data keys are wrapped
+// under the current key with AES-GCM and unwrapped under whichever key wrapped
+// them, so rotating in a new current key leaves older objects readable as
+// long as their keys stay in the ring.
+type Keyring struct {
+	mu      sync.RWMutex
+	keys    map[string]cipher.AEAD
+	current string
+}
+
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string]cipher.AEAD)}
+}
+
+// NewStaticKey returns a Keyring holding key alone.
+func NewStaticKey(id string, key []byte) (*Keyring, error) {
+	keyring := NewKeyring()
+	if err := keyring.Rotate(id, key); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// NewKeyFile reads a Keyring from the file at path. Each line of the file
+// holds a key id and the base64 encoded key, separated by white space; the
+// key on the last line is the current one. Blank lines and lines starting
+// with # are skipped.
+func NewKeyFile(path string) (*Keyring, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	keyring := NewKeyring()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected a key id and a key", path, line)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		if err := keyring.Rotate(fields[0], key); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if keyring.current == "" {
+		return nil, fmt.Errorf("%w: no keys in %s", osi.InvalidEncryption, path)
+	}
+	return keyring, nil
+}
+
+// Add adds a key that unwraps data keys without becoming the current key.
+func (t *Keyring) Add(id string, key []byte) error {
+	aead, err := newKeyAEAD(id, key)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.keys[id] = aead
+	return nil
+}
+
+// Rotate adds key and makes it the current key.
+func (t *Keyring) Rotate(id string, key []byte) error {
+	aead, err := newKeyAEAD(id, key)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.keys[id] = aead
+	t.current = id
+	return nil
+}
+
+// Current returns the id of the key new data keys are wrapped under.
+func (t *Keyring) Current() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.current
+}
+
+// WrapKey returns the nonce followed by the sealed data key, with the key id
+// as additional data.
+func (t *Keyring) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	t.mu.RLock()
+	id, aead := t.current, t.keys[t.current]
+	t.mu.RUnlock()
+	if aead == nil {
+		return "", nil, fmt.Errorf("%w: keyring has no current key", osi.InvalidEncryption)
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return id, aead.Seal(nonce, nonce, dataKey, []byte(id)), nil
+}
+
+func (t *Keyring) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	t.mu.RLock()
+	aead := t.keys[keyID]
+	t.mu.RUnlock()
+	if aead == nil {
+		return nil, fmt.Errorf("%w: unknown key %q", osi.InvalidEncryption, keyID)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: wrapped key is too short", osi.InvalidEncryption)
+	}
+	dataKey, err := aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: data key does not unwrap under key %q", osi.InvalidEncryption, keyID)
+	}
+	return dataKey, nil
+}
+
+func newKeyAEAD(id string, key []byte) (cipher.AEAD, error) {
+	if id == "" || strings.ContainsAny(id, " \t\r\n") {
+		return nil, fmt.Errorf("%w: invalid key id %q", osi.InvalidEncryption, id)
+	}
+	return newAEAD(key)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: key has %d bytes, %d are required", osi.InvalidEncryption, len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
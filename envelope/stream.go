@@ -0,0 +1,131 @@
+package envelope
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"github.com/burybell/osi"
+	"io"
+)
+
+// Bodies are sealed in chunks of chunkSize plaintext bytes, each followed by
+// its GCM tag. The nonce of a chunk is its index, with a flag on the last
+// chunk, so chunks can neither be reordered nor dropped from the end. Data
+// keys are never reused, which keeps the nonces unique.
+
+const tagSize = 16
+
+func chunkNonce(index int64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce, uint64(index))
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// plainSize returns the plaintext size of a body of size sealed bytes. Every
+// chunk but the last is full, and even an empty body has a last chunk.
+func plainSize(size int64, chunkSize int64) (int64, error) {
+	chunks := (size + chunkSize + tagSize - 1) / (chunkSize + tagSize)
+	if chunks == 0 || size-chunks*tagSize < 0 {
+		return 0, fmt.Errorf("%w: sealed body of %d bytes is truncated", osi.InvalidEncryption, size)
+	}
+	return size - chunks*tagSize, nil
+}
+
+type sealer struct {
+	reader    *bufio.Reader
+	aead      cipher.AEAD
+	chunkSize int
+	index     int64
+	plain     []byte
+	buf       []byte
+	sealed    []byte
+	done      bool
+}
+
+func newSealer(reader io.Reader, aead cipher.AEAD, chunkSize int) *sealer {
+	return &sealer{reader: bufio.NewReader(reader), aead: aead, chunkSize: chunkSize, plain: make([]byte, chunkSize)}
+}
+
+func (t *sealer) Read(p []byte) (int, error) {
+	for len(t.sealed) == 0 {
+		if t.done {
+			return 0, io.EOF
+		}
+		n, err := io.ReadFull(t.reader, t.plain)
+		switch {
+		case err == io.EOF || err == io.ErrUnexpectedEOF:
+			t.done = true
+		case err != nil:
+			return 0, err
+		default:
+			// a full chunk is the last one when nothing follows it
+			_, err = t.reader.Peek(1)
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+			t.done = err == io.EOF
+		}
+		t.buf = t.aead.Seal(t.buf[:0], chunkNonce(t.index, t.done), t.plain[:n], nil)
+		t.sealed = t.buf
+		t.index++
+	}
+	n := copy(p, t.sealed)
+	t.sealed = t.sealed[n:]
+	return n, nil
+}
+
+// opener reads chunks from index on, dropping skip bytes of plaintext from the
+// front and anything past limit. last is the index of the last chunk of the
+// body, which a ranged read may stop short of.
+type opener struct {
+	reader io.Reader
+	aead   cipher.AEAD
+	index  int64
+	last   int64
+	skip   int64
+	limit  int64
+	sealed []byte
+	buf    []byte
+	plain  []byte
+}
+
+func (t *opener) Read(p []byte) (int, error) {
+	for len(t.plain) == 0 {
+		if t.limit <= 0 || t.index > t.last {
+			return 0, io.EOF
+		}
+		n, err := io.ReadFull(t.reader, t.sealed)
+		if err == io.EOF || (err == io.ErrUnexpectedEOF && t.index != t.last) {
+			return 0, fmt.Errorf("%w: sealed body is truncated", osi.InvalidEncryption)
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+		t.buf, err = t.aead.Open(t.buf[:0], chunkNonce(t.index, t.index == t.last), t.sealed[:n], nil)
+		if err != nil {
+			return 0, fmt.Errorf("%w: chunk %d fails authentication", osi.InvalidEncryption, t.index)
+		}
+		t.index++
+		t.plain = t.buf
+		if t.skip > int64(len(t.plain)) {
+			return 0, fmt.Errorf("%w: chunk %d is short", osi.InvalidEncryption, t.index-1)
+		}
+		t.plain, t.skip = t.plain[t.skip:], 0
+		if int64(len(t.plain)) > t.limit {
+			t.plain = t.plain[:t.limit]
+		}
+	}
+	n := copy(p, t.plain)
+	t.plain = t.plain[n:]
+	t.limit -= int64(n)
+	return n, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -1,7 +1,219 @@
 package osi
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
 
 var (
-	ObjectNotFound = errors.New("ObjectNotFound")
+	ObjectNotFound      = errors.New("ObjectNotFound")
+	BucketNotFound      = errors.New("BucketNotFound")
+	BucketAlreadyExists = errors.New("BucketAlreadyExists")
+	BucketNotEmpty      = errors.New("BucketNotEmpty")
+	UploadNotFound      = errors.New("UploadNotFound")
+	AccessDenied        = errors.New("AccessDenied")
+	PreconditionFailed  = errors.New("PreconditionFailed")
+	NotModified         = errors.New("NotModified")
+	InvalidRange        = errors.New("InvalidRange")
+	InvalidKey          = errors.New("InvalidKey")
+	InvalidTag          = errors.New("InvalidTag")
+	InvalidPolicy       = errors.New("InvalidPolicy")
+	InvalidEncryption   = errors.New("InvalidEncryption")
+	InvalidRestore      = errors.New("InvalidRestore")
+	QuotaExceeded       = errors.New("QuotaExceeded")
+	Throttled           = errors.New("Throttled")
+	Transient           = errors.New("Transient")
+	NotSupported        = errors.New("NotSupported")
+	// NotRestored fails reads of archived objects without a restored copy.
+	NotRestored       = errors.New("NotRestored")
+	RestoreInProgress = errors.New("RestoreInProgress")
 )
+
+// Error is an error returned by a provider. errors.Is matches it against Kind,
+// the sentinel it maps onto, and errors.As reaches the SDK error through Err.
+type Error struct {
+	Kind       error
+	Provider   string
+	Code       string
+	StatusCode int
+	RequestID  string
+	Message    string
+	Err        error
+}
+
+func (t *Error) Error() string {
+	var sb strings.Builder
+	sb.WriteString(t.Provider)
+	sb.WriteString(": ")
+	if t.Code != "" {
+		sb.WriteString(t.Code)
+	} else if t.Kind != nil {
+		sb.WriteString(t.Kind.Error())
+	}
+	if t.StatusCode != 0 {
+		fmt.Fprintf(&sb, " (status %d)", t.StatusCode)
+	}
+	if t.Message != "" {
+		sb.WriteString(": ")
+		sb.WriteString(t.Message)
+	} else if t.Err != nil {
+		sb.WriteString(": ")
+		sb.WriteString(t.Err.Error())
+	}
+	if t.RequestID != "" {
+		sb.WriteString(", request id ")
+		sb.WriteString(t.RequestID)
+	}
+	return sb.String()
+}
+
+func (t *Error) Is(target error) bool {
+	return t.Kind != nil && t.Kind == target
+}
+
+func (t *Error) Unwrap() error {
+	return t.Err
+}
+
+// kinds are the sentinels ErrorClass reports, most specific first.
+var kinds = []error{
+	ObjectNotFound, BucketNotFound, BucketAlreadyExists, BucketNotEmpty, UploadNotFound,
+	AccessDenied, PreconditionFailed, NotModified, InvalidRange, InvalidKey, InvalidTag,
+	InvalidPolicy, InvalidEncryption, InvalidRestore, NotRestored, RestoreInProgress,
+	QuotaExceeded, Throttled, Transient, NotSupported,
+}
+
+// ErrorClass names the kind of err for metrics and traces: the sentinel it
+// matches, Canceled or DeadlineExceeded for context errors, and Unknown
+// otherwise. It is empty for a nil err.
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind.Error()
+		}
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "Canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "DeadlineExceeded"
+	}
+	return "Unknown"
+}
+
+// errorCodes maps the error codes of the S3 compatible APIs onto the sentinels.
+var errorCodes = map[string]error{
+	"NoSuchKey":                       ObjectNotFound,
+	"NoSuchVersion":                   ObjectNotFound,
+	"NoSuchBucket":                    BucketNotFound,
+	"NoSuchUpload":                    UploadNotFound,
+	"BucketAlreadyExists":             BucketAlreadyExists,
+	"BucketAlreadyOwnedByYou":         BucketAlreadyExists,
+	"BucketNotEmpty":                  BucketNotEmpty,
+	"AccessDenied":                    AccessDenied,
+	"AccountProblem":                  AccessDenied,
+	"AllAccessDisabled":               AccessDenied,
+	"InvalidAccessKeyId":              AccessDenied,
+	"SignatureDoesNotMatch":           AccessDenied,
+	"RequestTimeTooSkewed":            AccessDenied,
+	"PreconditionFailed":              PreconditionFailed,
+	"InvalidRange":                    InvalidRange,
+	"KeyTooLong":                      InvalidKey,
+	"InvalidObjectName":               InvalidKey,
+	"InvalidKey":                      InvalidKey,
+	"InvalidTag":                      InvalidTag,
+	"InvalidPolicyDocument":           InvalidPolicy,
+	"InvalidEncryptionAlgorithmError": InvalidEncryption,
+	"InvalidObjectState":              NotRestored,
+	"RestoreAlreadyInProgress":        RestoreInProgress,
+	"QuotaExceeded":                   QuotaExceeded,
+	"InsufficientStorage":             QuotaExceeded,
+	"TooManyBuckets":                  QuotaExceeded,
+	"SlowDown":                        Throttled,
+	"Throttling":                      Throttled,
+	"ThrottlingException":             Throttled,
+	"RequestLimitExceeded":            Throttled,
+	"TooManyRequests":                 Throttled,
+	"InternalError":                   Transient,
+	"ServiceUnavailable":              Transient,
+	"RequestTimeout":                  Transient,
+	"OperationAborted":                Transient,
+	"NotImplemented":                  NotSupported,
+	"MethodNotAllowed":                NotSupported,
+	"ExpiredToken":                    AccessDenied,
+	"InvalidToken":                    AccessDenied,
+}
+
+// NewError maps a provider error onto the sentinels by its error code and,
+// when the code is unknown, by its HTTP status. A 404 without a code, as
+// returned for HEAD requests, is taken for a missing object.
+func NewError(provider string, code string, statusCode int, requestID string, message string, err error) *Error {
+	kind, ok := errorCodes[code]
+	if !ok {
+		kind = statusKind(statusCode)
+	}
+	return &Error{
+		Kind:       kind,
+		Provider:   provider,
+		Code:       code,
+		StatusCode: statusCode,
+		RequestID:  requestID,
+		Message:    message,
+		Err:        err,
+	}
+}
+
+func statusKind(statusCode int) error {
+	switch statusCode {
+	case http.StatusNotFound:
+		return ObjectNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return AccessDenied
+	case http.StatusPreconditionFailed:
+		return PreconditionFailed
+	case http.StatusNotModified:
+		return NotModified
+	case http.StatusRequestedRangeNotSatisfiable:
+		return InvalidRange
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return Throttled
+	case http.StatusNotImplemented, http.StatusMethodNotAllowed:
+		return NotSupported
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return Transient
+	}
+	return nil
+}
+
+// DeleteError reports the keys a batch delete failed to remove. It is
+// classified by code, which the store gave for the first of them, and its
+// message names every key.
+func DeleteError(provider string, code string, message string, keys []string) *Error {
+	return NewError(provider, code, 0, "", fmt.Sprintf("delete failed for %s: %s", strings.Join(keys, ", "), message), nil)
+}
+
+// TransportError marks errors raised below the provider API, such as broken
+// connections and timeouts, as Transient. Context errors and errors already
+// mapped are returned unchanged.
+func TransportError(provider string, err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	var osiErr *Error
+	if errors.As(err, &osiErr) {
+		return err
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return &Error{Kind: Transient, Provider: provider, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+package osi_test
+
+import (
+	"context"
+	"errors"
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestNewError(t *testing.T) {
+	sdkErr := errors.New("sdk error")
+	err := osi.NewError("s3", "NoSuchKey", http.StatusNotFound, "request-id", "The specified key does not exist.", sdkErr)
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	assert.ErrorIs(t, err, sdkErr)
+	assert.NotErrorIs(t, err, osi.BucketNotFound)
+	assert.Equal(t, "s3: NoSuchKey (status 404): The specified key does not exist., request id request-id", err.Error())
+
+	var osiErr *osi.Error
+	assert.True(t, errors.As(error(err), &osiErr))
+	assert.Equal(t, "request-id", osiErr.RequestID)
+
+	assert.ErrorIs(t, osi.NewError("s3", "NoSuchBucket", http.StatusNotFound, "", "", nil), osi.BucketNotFound)
+	assert.ErrorIs(t, osi.NewError("s3", "", http.StatusNotFound, "", "", nil), osi.ObjectNotFound)
+	assert.ErrorIs(t, osi.NewError("s3", "", http.StatusPreconditionFailed, "", "", nil), osi.PreconditionFailed)
+	assert.ErrorIs(t, osi.NewError("s3", "SlowDown", http.StatusServiceUnavailable, "", "", nil), osi.Throttled)
+	assert.ErrorIs(t, osi.NewError("s3", "", http.StatusBadGateway, "", "", nil), osi.Transient)
+	assert.Nil(t, osi.NewError("s3", "", http.StatusBadRequest, "", "", nil).Kind)
+}
+
+func TestDeleteError(t *testing.T) {
+	err := osi.DeleteError("cos", "AccessDenied", "Access Denied.", []string{"test/a.txt", "test/b.txt"})
+	assert.ErrorIs(t, err, osi.AccessDenied)
+	assert.Contains(t, err.Error(), "test/a.txt, test/b.txt")
+}
+
+func TestErrorClass(t *testing.T) {
+	assert.Equal(t, "", osi.ErrorClass(nil))
+	assert.Equal(t, "ObjectNotFound", osi.ErrorClass(osi.ObjectNotFound))
+	assert.Equal(t, "Throttled", osi.ErrorClass(osi.NewError("s3", "SlowDown", http.StatusServiceUnavailable, "", "", nil)))
+	assert.Equal(t, "InvalidTag", osi.ErrorClass(osi.ValidateTags(map[string]string{"": "value"})))
+	assert.Equal(t, "Canceled", osi.ErrorClass(context.Canceled))
+	assert.Equal(t, "Unknown", osi.ErrorClass(errors.New("sdk error")))
+}
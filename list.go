@@ -0,0 +1,158 @@
+package osi
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	DefaultPageSize = 1000
+	MaxPageSize     = 1000
+)
+
+type ListOptions struct {
+	PageSize          int
+	ContinuationToken string
+	StartAfter        string
+	Delimiter         string
+}
+
+type ListOption func(opts *ListOptions)
+
+func WithPageSize(pageSize int) ListOption {
+	return func(opts *ListOptions) {
+		opts.PageSize = pageSize
+	}
+}
+
+// WithContinuationToken resumes a listing from ObjectPage.NextContinuationToken.
+func WithContinuationToken(token string) ListOption {
+	return func(opts *ListOptions) {
+		opts.ContinuationToken = token
+	}
+}
+
+func WithStartAfter(key string) ListOption {
+	return func(opts *ListOptions) {
+		opts.StartAfter = key
+	}
+}
+
+// WithDelimiter groups keys sharing a prefix up to the delimiter into
+// ObjectPage.CommonPrefixes instead of returning them as objects.
+func WithDelimiter(delimiter string) ListOption {
+	return func(opts *ListOptions) {
+		opts.Delimiter = delimiter
+	}
+}
+
+func NewListOptions(opts ...ListOption) (*ListOptions, error) {
+	options := &ListOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.PageSize == 0 {
+		options.PageSize = DefaultPageSize
+	}
+	if options.PageSize < 0 || options.PageSize > MaxPageSize {
+		return nil, errors.New("page size must be between 1 and 1000")
+	}
+	return options, nil
+}
+
+type ObjectPage struct {
+	Objects               []*ObjectInfo
+	CommonPrefixes        []string
+	NextContinuationToken string
+	IsTruncated           bool
+}
+
+// ObjectPager walks a listing page by page.
+type ObjectPager struct {
+	objects BucketObjects
+	prefix  string
+	opts    []ListOption
+	token   string
+	page    *ObjectPage
+	done    bool
+	err     error
+}
+
+func NewObjectPager(objects BucketObjects, prefix string, opts ...ListOption) *ObjectPager {
+	return &ObjectPager{objects: objects, prefix: prefix, opts: opts}
+}
+
+func (t *ObjectPager) Next(ctx context.Context) bool {
+	if t.done || t.err != nil {
+		return false
+	}
+	opts := t.opts
+	if t.token != "" {
+		opts = append(opts[:len(opts):len(opts)], WithContinuationToken(t.token))
+	}
+	page, err := t.objects.ListObjectsPage(ctx, t.prefix, opts...)
+	if err != nil {
+		t.err = err
+		return false
+	}
+	t.page = page
+	t.token = page.NextContinuationToken
+	t.done = !page.IsTruncated || page.NextContinuationToken == ""
+	return true
+}
+
+func (t *ObjectPager) Page() *ObjectPage {
+	return t.page
+}
+
+func (t *ObjectPager) Err() error {
+	return t.err
+}
+
+// ListAll returns every object of the listing of prefix, fetched page by page.
+func ListAll(ctx context.Context, objects BucketObjects, prefix string, opts ...ListOption) ([]ObjectMeta, error) {
+	var oms = make([]ObjectMeta, 0)
+	iter := NewObjectIterator(objects, prefix, opts...)
+	for iter.Next(ctx) {
+		oms = append(oms, iter.Object())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return oms, nil
+}
+
+// ObjectIterator walks a listing object by object, fetching pages lazily.
+type ObjectIterator struct {
+	pager  *ObjectPager
+	index  int
+	object *ObjectInfo
+}
+
+func NewObjectIterator(objects BucketObjects, prefix string, opts ...ListOption) *ObjectIterator {
+	return &ObjectIterator{pager: NewObjectPager(objects, prefix, opts...)}
+}
+
+func (t *ObjectIterator) Next(ctx context.Context) bool {
+	for {
+		page := t.pager.Page()
+		if page != nil && t.index < len(page.Objects) {
+			t.object = page.Objects[t.index]
+			t.index++
+			return true
+		}
+		if !t.pager.Next(ctx) {
+			t.object = nil
+			return false
+		}
+		t.index = 0
+	}
+}
+
+func (t *ObjectIterator) Object() *ObjectInfo {
+	return t.object
+}
+
+func (t *ObjectIterator) Err() error {
+	return t.pager.Err()
+}
@@ -0,0 +1,75 @@
+package local
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/burybell/osi"
+	"net/http"
+)
+
+// Server-side encryption is emulated: files are kept in plain text, SSE-S3
+// is only recorded, and for SSE-C the MD5 of the customer key is recorded so
+// that reads have to present the key as they do on the providers. SSE-KMS,
+// having no key service to emulate, is rejected.
+
+const (
+	// encryptionHeader carries the EncryptionMode over HTTP.
+	encryptionHeader  = "x-osi-server-side-encryption"
+	customerKeyHeader = "x-osi-server-side-encryption-customer-key"
+)
+
+func checkEncryption(encryption osi.Encryption) error {
+	if err := encryption.Validate(); err != nil {
+		return err
+	}
+	if encryption.Mode == osi.EncryptionSSEKMS {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "SSE-KMS"}
+	}
+	return nil
+}
+
+func customerKeyMD5(key []byte) string {
+	if len(key) == 0 {
+		return ""
+	}
+	_, keyMD5 := osi.CustomerKeyHeaders(key)
+	return keyMD5
+}
+
+// checkCustomerKey requires key to be the customer key of SSE-C objects, and
+// to be absent for others.
+func (t *objectMeta) checkCustomerKey(key []byte) error {
+	if t.Encryption != osi.EncryptionSSEC {
+		if len(key) != 0 {
+			return fmt.Errorf("%w: object is not encrypted with a customer key", osi.InvalidEncryption)
+		}
+		return nil
+	}
+	if len(key) == 0 {
+		return fmt.Errorf("%w: object is encrypted with a customer key", osi.InvalidEncryption)
+	}
+	if customerKeyMD5(key) != t.CustomerKeyMD5 {
+		return fmt.Errorf("%w: customer key does not match", osi.AccessDenied)
+	}
+	return nil
+}
+
+// checkCustomerKey checks key against the object at path, or its version
+// versionID when set. Missing objects are left to the caller to report.
+func (t *bucket) checkCustomerKey(path string, versionID string, key []byte) error {
+	meta, err := t.versionMeta(path, versionID)
+	if err != nil {
+		return toError(err)
+	}
+	return meta.checkCustomerKey(key)
+}
+
+// requestCustomerKey returns the base64 encoded customer key of a request,
+// nil when it is missing or malformed.
+func requestCustomerKey(header http.Header) []byte {
+	key, err := base64.StdEncoding.DecodeString(header.Get(customerKeyHeader))
+	if err != nil || len(key) == 0 {
+		return nil
+	}
+	return key
+}
@@ -0,0 +1,75 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"github.com/burybell/osi"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// toError maps file system errors onto the osi error taxonomy. Missing files
+// are mapped where they are detected, since only the caller knows whether the
+// object or something around it is missing.
+func toError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case os.IsPermission(err):
+		return &osi.Error{Kind: osi.AccessDenied, Provider: Name, Err: err}
+	case errors.Is(err, syscall.ENOSPC):
+		return &osi.Error{Kind: osi.QuotaExceeded, Provider: Name, Err: err}
+	case errors.Is(err, syscall.ENAMETOOLONG):
+		return &osi.Error{Kind: osi.InvalidKey, Provider: Name, Err: err}
+	}
+	return err
+}
+
+// checkPrefix rejects keys and prefixes that would leave the bucket directory
+// or reach into the hidden metaDir.
+func checkPrefix(prefix string) error {
+	for _, segment := range strings.Split(prefix, "/") {
+		if segment == ".." || segment == metaDir {
+			return &osi.Error{Kind: osi.InvalidKey, Provider: Name, Message: "invalid key " + prefix}
+		}
+	}
+	return nil
+}
+
+// checkBucketName rejects names that are not a single visible directory
+// below BasePath.
+func checkBucketName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("invalid bucket name %q", name)
+	}
+	return nil
+}
+
+// check fails with BucketNotFound until the bucket directory is created and
+// with InvalidKey for paths that do not name an object.
+func (t *bucket) check(paths ...string) error {
+	if err := checkBucketName(t.bucket); err != nil {
+		return err
+	}
+	stat, err := os.Stat(filepath.Join(t.config.BasePath, t.bucket))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return osi.BucketNotFound
+		}
+		return toError(err)
+	}
+	if !stat.IsDir() {
+		return osi.BucketNotFound
+	}
+	for _, path := range paths {
+		if path == "" || strings.HasSuffix(path, "/") {
+			return &osi.Error{Kind: osi.InvalidKey, Provider: Name, Message: "invalid key " + path}
+		}
+		if err := checkPrefix(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,16 +1,29 @@
 package local
 
 import (
+	"crypto/hmac"
+	"crypto/md5"
 	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"github.com/burybell/osi"
+	"hash"
 	"io"
-	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	metaHeaderPrefix = "x-osi-meta-"
+	aclHeader        = "x-osi-acl"
+	// maxPostFieldSize bounds the form fields read before the file of a POST.
+	maxPostFieldSize = 1 << 20
 )
 
 type HttpHandler struct {
@@ -25,17 +38,57 @@ func (t *HttpHandler) GetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	object, err := t.store.Bucket(bkt).GetObject(r.Context(), path)
+	customerKey := osi.WithCustomerKey(requestCustomerKey(r.Header))
+	info, err := t.store.Bucket(bkt).StatObject(r.Context(), path, customerKey)
 	if err != nil {
-		if errors.Is(err, osi.ObjectNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusInternalServerError)
+		t.writeError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("Range") != "" {
+		t.getRangeHandler(w, r, info)
+		return
+	}
+
+	object, err := t.store.Bucket(bkt).GetObject(r.Context(), path, customerKey, osi.WithGetConditions(requestConditions(r.Header)))
+	if err != nil {
+		t.writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", mime.TypeByExtension(object.Extension()))
+	writeObjectHeader(w, info)
+	writeResponseOverrides(w, r.URL.Query())
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	defer object.Close()
+	_, _ = io.Copy(w, object)
+}
+
+func (t *HttpHandler) getRangeHandler(w http.ResponseWriter, r *http.Request, info *osi.ObjectInfo) {
+	rng, err := osi.ParseRange(r.Header.Get("Range"))
+	if err != nil {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", info.Size))
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+	offset, length, err := rng.Resolve(info.Size)
+	if err != nil {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", info.Size))
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+
+	object, err := t.store.Bucket(info.Bucket()).GetObjectRange(r.Context(), info.ObjectPath(), osi.NewRange(offset, length),
+		osi.WithCustomerKey(requestCustomerKey(r.Header)), osi.WithGetConditions(requestConditions(r.Header)))
+	if err != nil {
+		t.writeError(w, r, err)
+		return
+	}
+
+	writeObjectHeader(w, info)
+	writeResponseOverrides(w, r.URL.Query())
+	w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, info.Size))
+	w.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+	w.WriteHeader(http.StatusPartialContent)
 	defer object.Close()
 	_, _ = io.Copy(w, object)
 }
@@ -47,13 +100,94 @@ func (t *HttpHandler) PutHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = t.store.Bucket(bkt).PutObject(r.Context(), path, r.Body)
+	if name := unsignedHeader(r); name != "" {
+		osi.Log(r.Context(), t.store.config.Logger, osi.LogInfo, "request rejected", osi.Field("provider", Name), osi.Field("method", r.Method), osi.Field("url", r.URL.String()),
+			osi.Field("remote", r.RemoteAddr), osi.Field("unsigned_header", name))
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if contentMD5 := r.Header.Get("Content-MD5"); contentMD5 != "" {
+		body = &digestReader{reader: body, hash: md5.New(), contentMD5: contentMD5}
+	}
+	err = t.store.Bucket(bkt).PutObject(r.Context(), path, body, append(putOptions(r.Header), osi.WithPutConditions(requestConditions(r.Header)))...)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		t.writeError(w, r, err)
 		return
 	}
 }
 
+// PostHandler stores the file of a multipart/form-data upload to a bucket URL,
+// as signed by SignPostPolicy. The file must be the last field of the form.
+func (t *HttpHandler) PostHandler(w http.ResponseWriter, r *http.Request) {
+	bkt := strings.Trim(r.URL.Path, "/")
+	if bkt == "" || strings.Contains(bkt, "/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var fields = make(map[string]string)
+	var file *multipart.Part
+	for file == nil {
+		part, err := reader.NextPart()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		name := strings.ToLower(part.FormName())
+		if name == osi.PostFileField {
+			file = part
+			continue
+		}
+		value, err := io.ReadAll(io.LimitReader(part, maxPostFieldSize))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fields[name] = string(value)
+	}
+
+	if !hmac.Equal([]byte(SignPolicy(fields["policy"], t.Secret)), []byte(fields["signature"])) {
+		osi.Log(r.Context(), t.store.config.Logger, osi.LogInfo, "request rejected", osi.Field("provider", Name), osi.Field("method", r.Method), osi.Field("url", r.URL.String()),
+			osi.Field("remote", r.RemoteAddr))
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	policy, err := parsePostPolicy(fields["policy"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := policy.check(bkt, fields); err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var metadata = make(map[string]string)
+	for name, value := range fields {
+		if strings.HasPrefix(name, metaHeaderPrefix) {
+			metadata[strings.TrimPrefix(name, metaHeaderPrefix)] = value
+		}
+	}
+	var opts = []osi.PutOption{osi.WithContentType(fields["content-type"]), osi.WithMetadata(metadata)}
+	if fields["acl"] != "" {
+		opts = append(opts, osi.WithACL(fields["acl"]))
+	}
+	path := strings.ReplaceAll(fields["key"], osi.PostFilename, file.FileName())
+	err = t.store.Bucket(bkt).PutObject(r.Context(), path, policy.body(file), opts...)
+	if err != nil {
+		t.writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (t *HttpHandler) HeadHandler(w http.ResponseWriter, r *http.Request) {
 	bkt, path, err := t.GetBucketAndPath(r)
 	if err != nil {
@@ -63,7 +197,7 @@ func (t *HttpHandler) HeadHandler(w http.ResponseWriter, r *http.Request) {
 
 	exist, err := t.store.Bucket(bkt).HeadObject(r.Context(), path)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		t.writeError(w, r, err)
 		return
 	}
 
@@ -81,19 +215,161 @@ func (t *HttpHandler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = t.store.Bucket(bkt).DeleteObject(r.Context(), path)
+	err = t.store.Bucket(bkt).DeleteObject(r.Context(), path, osi.WithDeleteConditions(requestConditions(r.Header)))
 	if err != nil {
-		if errors.Is(err, osi.ObjectNotFound) {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusInternalServerError)
+		t.writeError(w, r, err)
 		return
 	}
 }
 
+// errBadDigest fails puts whose body does not match their Content-MD5 header.
+var errBadDigest = errors.New("BadDigest")
+
+// digestReader fails the last read from reader unless the body matches
+// contentMD5, so that the put is abandoned.
+type digestReader struct {
+	reader     io.Reader
+	hash       hash.Hash
+	contentMD5 string
+}
+
+func (t *digestReader) Read(p []byte) (int, error) {
+	n, err := t.reader.Read(p)
+	t.hash.Write(p[:n])
+	if err == io.EOF && base64.StdEncoding.EncodeToString(t.hash.Sum(nil)) != t.contentMD5 {
+		return n, errBadDigest
+	}
+	return n, err
+}
+
+// writeError answers r with the status err maps onto, logging err at LogWarn
+// when the server is at fault.
+func (t *HttpHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	level := osi.LogDebug
+	if status >= http.StatusInternalServerError {
+		level = osi.LogWarn
+	}
+	osi.Log(r.Context(), t.store.config.Logger, level, "request failed", osi.Field("provider", Name), osi.Field("method", r.Method), osi.Field("url", r.URL.String()), osi.Field("status", status), osi.Field("error", err))
+	w.WriteHeader(status)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, osi.ObjectNotFound), errors.Is(err, osi.BucketNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, osi.AccessDenied), errors.Is(err, osi.NotRestored):
+		return http.StatusForbidden
+	case errors.Is(err, osi.InvalidKey), errors.Is(err, osi.InvalidPolicy), errors.Is(err, osi.InvalidEncryption),
+		errors.Is(err, errEntityTooSmall), errors.Is(err, errEntityTooLarge), errors.Is(err, errBadDigest):
+		return http.StatusBadRequest
+	case errors.Is(err, osi.PreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, osi.NotModified):
+		return http.StatusNotModified
+	case errors.Is(err, osi.InvalidRange):
+		return http.StatusRequestedRangeNotSatisfiable
+	case errors.Is(err, osi.QuotaExceeded):
+		return http.StatusInsufficientStorage
+	}
+	return http.StatusInternalServerError
+}
+
+func writeObjectHeader(w http.ResponseWriter, info *osi.ObjectInfo) {
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("ETag", fmt.Sprintf("%q", info.ETag))
+	w.Header().Set("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+	if info.ContentType != "" {
+		w.Header().Set("Content-Type", info.ContentType)
+	}
+	if info.ContentEncoding != "" {
+		w.Header().Set("Content-Encoding", info.ContentEncoding)
+	}
+	if info.CacheControl != "" {
+		w.Header().Set("Cache-Control", info.CacheControl)
+	}
+	if info.ContentDisposition != "" {
+		w.Header().Set("Content-Disposition", info.ContentDisposition)
+	}
+	if !info.Expires.IsZero() {
+		w.Header().Set("Expires", info.Expires.UTC().Format(http.TimeFormat))
+	}
+	if info.Encryption != osi.EncryptionNone {
+		w.Header().Set(encryptionHeader, string(info.Encryption))
+	}
+	w.Header().Set(storageClassHeader, info.StorageClass)
+	for key, value := range info.Metadata {
+		w.Header().Set(metaHeaderPrefix+key, value)
+	}
+}
+
+// writeResponseOverrides applies the response-content-type and
+// response-content-disposition parameters of a signed URL.
+func writeResponseOverrides(w http.ResponseWriter, query url.Values) {
+	if contentType := query.Get("response-content-type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if contentDisposition := query.Get("response-content-disposition"); contentDisposition != "" {
+		w.Header().Set("Content-Disposition", contentDisposition)
+	}
+}
+
+func putOptions(header http.Header) []osi.PutOption {
+	var opts = []osi.PutOption{
+		osi.WithContentType(header.Get("Content-Type")),
+		osi.WithContentEncoding(header.Get("Content-Encoding")),
+		osi.WithCacheControl(header.Get("Cache-Control")),
+		osi.WithContentDisposition(header.Get("Content-Disposition")),
+		osi.WithMetadata(osi.MetadataFromHeader(header, metaHeaderPrefix)),
+	}
+	if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		opts = append(opts, osi.WithExpires(expires))
+	}
+	if acl := header.Get(aclHeader); acl != "" {
+		opts = append(opts, osi.WithACL(acl))
+	}
+	if mode := header.Get(encryptionHeader); mode != "" {
+		opts = append(opts, osi.WithEncryption(osi.Encryption{Mode: osi.EncryptionMode(mode), CustomerKey: requestCustomerKey(header)}))
+	}
+	if class := header.Get(storageClassHeader); class != "" {
+		opts = append(opts, osi.WithStorageClass(class))
+	}
+	return opts
+}
+
+// unsignedHeader returns an x-osi- header of r that the signature does not
+// cover, or "" when there is none. These headers set the ACL, encryption,
+// storage class and metadata of a put, which the signer has to agree to.
+func unsignedHeader(r *http.Request) string {
+	var signed = make(map[string]bool)
+	for _, name := range strings.Split(r.URL.Query().Get("headers"), ";") {
+		signed[strings.ToLower(name)] = true
+	}
+	for name := range r.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-osi-") && !signed[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+func requestConditions(header http.Header) osi.Conditions {
+	conditions := osi.Conditions{
+		IfMatch:     header.Get("If-Match"),
+		IfNoneMatch: header.Get("If-None-Match"),
+	}
+	if since, err := http.ParseTime(header.Get("If-Modified-Since")); err == nil {
+		conditions.IfModifiedSince = since
+	}
+	if since, err := http.ParseTime(header.Get("If-Unmodified-Since")); err == nil {
+		conditions.IfUnmodifiedSince = since
+	}
+	return conditions
+}
+
 func (t *HttpHandler) GetBucketAndPath(r *http.Request) (string, string, error) {
-	items := strings.Split(r.URL.Path, "/")
+	items := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
 	if len(items) <= 1 {
 		return "", "", fmt.Errorf("invalid path")
 	}
@@ -103,13 +379,28 @@ func (t *HttpHandler) GetBucketAndPath(r *http.Request) (string, string, error)
 func HandleHttp(store *ObjectStore, secret string) {
 	handler := HttpHandler{Secret: secret, store: store}
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if logger := store.config.Logger; logger != nil {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				osi.Log(r.Context(), logger, osi.LogDebug, "request served", osi.Field("provider", Name), osi.Field("method", r.Method), osi.Field("url", r.URL.String()),
+					osi.Field("remote", r.RemoteAddr), osi.Field("status", sw.status), osi.Field("duration", time.Since(start)))
+			}()
+			w = sw
+		}
+		if r.Method == http.MethodPost {
+			handler.PostHandler(w, r)
+			return
+		}
 		expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		signature := Sign(r.Method, strings.TrimPrefix(r.URL.Path, "/"), int(expires), secret)
-		if signature != r.URL.Query().Get("signature") {
+		signature := SignRequest(r.Method, strings.TrimPrefix(r.URL.Path, "/"), r.URL.Query(), r.Header, secret)
+		if !hmac.Equal([]byte(signature), []byte(r.URL.Query().Get("signature"))) || time.Now().Unix() > expires {
+			osi.Log(r.Context(), store.config.Logger, osi.LogInfo, "request rejected", osi.Field("provider", Name), osi.Field("method", r.Method), osi.Field("url", r.URL.String()),
+				osi.Field("remote", r.RemoteAddr), osi.Field("expired", time.Now().Unix() > expires))
 			w.WriteHeader(http.StatusForbidden)
 			return
 		}
@@ -127,14 +418,50 @@ func HandleHttp(store *ObjectStore, secret string) {
 	})
 }
 
+// statusWriter records the status of a response for the request log.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (t *statusWriter) WriteHeader(status int) {
+	t.status = status
+	t.ResponseWriter.WriteHeader(status)
+}
+
 func Sign(method string, path string, expires int, secret string) string {
+	return SignRequest(method, path, url.Values{"expires": {strconv.Itoa(expires)}}, nil, secret)
+}
+
+// SignRequest signs a request with method to path. Besides expires, it signs
+// the other query parameters and the values of the headers listed in the
+// headers parameter; Sign is SignRequest without them.
+func SignRequest(method string, path string, query url.Values, header http.Header, secret string) string {
 	var buf strings.Builder
 	buf.WriteString(method)
 	buf.WriteRune('\n')
 	buf.WriteString(path)
 	buf.WriteRune('\n')
-	buf.WriteString(fmt.Sprintf("%d", expires))
+	buf.WriteString(query.Get("expires"))
 	buf.WriteRune('\n')
 	buf.WriteString(secret)
+
+	var names []string
+	for name := range query {
+		if name != "expires" && name != "signature" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		buf.WriteRune('\n')
+		buf.WriteString(name + "=" + query.Get(name))
+	}
+	if headers := query.Get("headers"); headers != "" {
+		for _, name := range strings.Split(headers, ";") {
+			buf.WriteRune('\n')
+			buf.WriteString(name + ":" + header.Get(name))
+		}
+	}
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(base64.StdEncoding.EncodeToString([]byte(buf.String())))))
 }
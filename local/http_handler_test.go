@@ -1,13 +1,140 @@
 package local_test
 
 import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
 	"fmt"
+	"github.com/burybell/osi"
 	"github.com/burybell/osi/local"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSign(t *testing.T) {
 	signature := local.Sign(http.MethodPut, "path/to/file", 100, "example")
 	fmt.Println(signature)
 }
+
+func TestHttpHandler_SignURL(t *testing.T) {
+	server := httptest.NewServer(http.DefaultServeMux)
+	defer server.Close()
+	do := func(method string, rawURL string, header http.Header, content string) *http.Response {
+		u, err := url.Parse(rawURL)
+		assert.NoError(t, err)
+		req, err := http.NewRequest(method, server.URL+u.RequestURI(), strings.NewReader(content))
+		assert.NoError(t, err)
+		for name := range header {
+			req.Header.Set(name, header.Get(name))
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		assert.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	digest := md5.Sum([]byte("some text"))
+	contentMD5 := base64.StdEncoding.EncodeToString(digest[:])
+	signedURL, err := bucket.SignURL(ctx, "test/signed.txt", http.MethodPut, time.Minute,
+		osi.WithSignContentType("text/plain"), osi.WithSignContentMD5(contentMD5))
+	assert.NoError(t, err)
+	header := http.Header{"Content-Type": {"text/plain"}, "Content-Md5": {contentMD5}}
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPut, signedURL, http.Header{"Content-Type": {"text/html"}, "Content-Md5": {contentMD5}}, "some text").StatusCode)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPut, signedURL, nil, "some text").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, signedURL, header, "other text").StatusCode)
+	exist, err := bucket.HeadObject(ctx, "test/signed.txt")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPut, signedURL, http.Header{"Content-Type": {"text/plain"}, "Content-Md5": {contentMD5}, "X-Osi-Acl": {"0666"}}, "some text").StatusCode)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPut, signedURL, http.Header{"Content-Type": {"text/plain"}, "Content-Md5": {contentMD5}, "X-Osi-Meta-Extra": {"extra"}}, "some text").StatusCode)
+	exist, err = bucket.HeadObject(ctx, "test/signed.txt")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, signedURL, header, "some text").StatusCode)
+	signedURL, err = bucket.SignURL(ctx, "test/signed-acl.txt", http.MethodPut, time.Minute, osi.WithSignACL("0600"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, signedURL, http.Header{"X-Osi-Acl": {"0600"}}, "some text").StatusCode)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/signed-acl.txt"))
+
+	signedURL, err = bucket.SignURL(ctx, "test/signed.txt", http.MethodGet, time.Minute,
+		osi.WithResponseContentDisposition(`attachment; filename="example.txt"`))
+	assert.NoError(t, err)
+	resp := do(http.MethodGet, signedURL, nil, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="example.txt"`, resp.Header.Get("Content-Disposition"))
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, strings.Replace(signedURL, "attachment", "inline", 1), nil, "").StatusCode)
+	assert.Equal(t, http.StatusPartialContent, do(http.MethodGet, signedURL, http.Header{"Range": {"bytes=0-3"}}, "").StatusCode)
+	assert.Equal(t, http.StatusPreconditionFailed, do(http.MethodGet, signedURL, http.Header{"Range": {"bytes=0-3"}, "If-Match": {`"0123456789abcdef0123456789abcdef"`}}, "").StatusCode)
+
+	signedURL, err = bucket.SignURL(ctx, "test/signed.txt", http.MethodGet, -time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, signedURL, nil, "").StatusCode)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/signed.txt"))
+}
+
+func TestHttpHandler_PostHandler(t *testing.T) {
+	server := httptest.NewServer(http.DefaultServeMux)
+	defer server.Close()
+	post := func(form *osi.PostForm, filename string, content string) int {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for name, value := range form.Fields {
+			assert.NoError(t, writer.WriteField(name, value))
+		}
+		part, err := writer.CreateFormFile(osi.PostFileField, filename)
+		assert.NoError(t, err)
+		_, _ = part.Write([]byte(content))
+		assert.NoError(t, writer.Close())
+
+		u, err := url.Parse(form.URL)
+		assert.NoError(t, err)
+		resp, err := http.Post(server.URL+u.Path, writer.FormDataContentType(), &body)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	form, err := bucket.SignPostPolicy(ctx, osi.PostPolicy{KeyPrefix: "test/post/", ContentType: "text/plain", MaxContentLength: 16}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "test/post/${filename}", form.Fields["key"])
+	assert.Equal(t, http.StatusNoContent, post(form, "example.txt", "some text"))
+	info, err := bucket.StatObject(ctx, "test/post/example.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "text/plain", info.ContentType)
+	object, err := bucket.GetObject(ctx, "test/post/example.txt")
+	assert.NoError(t, err)
+	data, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.NoError(t, object.Close())
+	assert.Equal(t, "some text", string(data))
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/post/example.txt"))
+
+	assert.Equal(t, http.StatusBadRequest, post(form, "large.txt", "some text beyond the limit"))
+	exist, err := bucket.HeadObject(ctx, "test/post/large.txt")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	form.Fields["Content-Type"] = "text/html"
+	assert.Equal(t, http.StatusForbidden, post(form, "example.txt", "some text"))
+	form.Fields["Content-Type"] = "text/plain"
+	form.Fields["x-osi-meta-extra"] = "extra"
+	assert.Equal(t, http.StatusForbidden, post(form, "example.txt", "some text"))
+	delete(form.Fields, "x-osi-meta-extra")
+	form.Fields["signature"] = local.SignPolicy(form.Fields["policy"], "other")
+	assert.Equal(t, http.StatusForbidden, post(form, "example.txt", "some text"))
+
+	form, err = bucket.SignPostPolicy(ctx, osi.PostPolicy{Key: "test/post/example.txt"}, -time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, post(form, "example.txt", "some text"))
+	_, err = bucket.SignPostPolicy(ctx, osi.PostPolicy{}, time.Minute)
+	assert.ErrorIs(t, err, osi.InvalidPolicy)
+}
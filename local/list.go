@@ -0,0 +1,132 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// errStopWalk ends walkFiles early without failing it.
+var errStopWalk = errors.New("stop walk")
+
+type listedFile struct {
+	key  string
+	info os.FileInfo
+}
+
+// listFiles returns the files of all objects whose key starts with prefix, sorted by key.
+func (t *bucket) listFiles(prefix string) ([]listedFile, error) {
+	var marker = ""
+	var files = make([]listedFile, 0)
+	err := t.walkFiles(prefix, "", &marker, func(file listedFile) error {
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// walkFiles calls fn with the file of every object whose key starts with
+// prefix, in key order, until fn returns errStopWalk. Keys up to *marker are
+// passed over, and with a delimiter so are the keys grouped into a common
+// prefix up to *marker; fn may move the marker on. Directories are read one at
+// a time as the walk reaches them and those holding only keys passed over are
+// not read at all, so that a page costs about as much as the keys it returns.
+func (t *bucket) walkFiles(prefix string, delimiter string, marker *string, fn func(file listedFile) error) error {
+	root := filepath.Join(t.config.BasePath, t.bucket)
+	dir := ""
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir = prefix[:i+1]
+	}
+	err := t.walkDir(root, dir, prefix, delimiter, marker, fn)
+	if err == errStopWalk {
+		return nil
+	}
+	return err
+}
+
+type dirEntry struct {
+	name  string
+	entry os.DirEntry
+}
+
+func (t *bucket) walkDir(root string, dir string, prefix string, delimiter string, marker *string, fn func(file listedFile) error) error {
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(dir)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	// a directory sorts as its name and a slash, which places it among its
+	// siblings where the keys it holds belong
+	var sorted = make([]dirEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if entry.Name() == metaDir {
+				continue
+			}
+			sorted = append(sorted, dirEntry{name: entry.Name() + "/", entry: entry})
+		} else {
+			sorted = append(sorted, dirEntry{name: entry.Name(), entry: entry})
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+
+	for _, e := range sorted {
+		key := dir + e.name
+		if e.entry.IsDir() {
+			if !strings.HasPrefix(key, prefix) && !strings.HasPrefix(prefix, key) || walkedPast(prefix, delimiter, *marker, key, true) {
+				continue
+			}
+			if err := t.walkDir(root, key, prefix, delimiter, marker, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasPrefix(key, prefix) || walkedPast(prefix, delimiter, *marker, key, false) {
+			continue
+		}
+		info, err := e.entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if err := fn(listedFile{key: key, info: info}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commonPrefix returns the common prefix key is grouped into when listing
+// prefix with delimiter.
+func commonPrefix(prefix string, delimiter string, key string) (string, bool) {
+	if delimiter == "" || !strings.HasPrefix(key, prefix) {
+		return "", false
+	}
+	if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+		return key[:len(prefix)+i+len(delimiter)], true
+	}
+	return "", false
+}
+
+// walkedPast tells whether a listing resumed after marker is past key, or for
+// a directory past every key under it.
+func walkedPast(prefix string, delimiter string, marker string, key string, dir bool) bool {
+	if group, ok := commonPrefix(prefix, delimiter, key); ok && group <= marker {
+		return true
+	}
+	if dir {
+		return marker >= key && !strings.HasPrefix(marker, key)
+	}
+	return key <= marker
+}
@@ -2,12 +2,18 @@ package local
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/burybell/osi"
 	"io"
+	"io/fs"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +27,8 @@ type Config struct {
 	BasePath   string `yaml:"base_path" mapstructure:"base_path" json:"base_path"`
 	HttpAddr   string `yaml:"http_addr" mapstructure:"http_addr" json:"http_addr"`
 	HttpSecret string `yaml:"http_secret" mapstructure:"http_secret" json:"http_secret"`
+	// Logger receives the requests served over HttpAddr and the URLs presigned.
+	Logger osi.Logger `yaml:"-" mapstructure:"-" json:"-"`
 }
 
 type ObjectStore struct {
@@ -64,29 +72,86 @@ func (t *ObjectStore) Name() string {
 }
 
 func (t *ObjectStore) Bucket(name string) osi.Bucket {
-	bucketPath := fmt.Sprintf("%s/%s", t.config.BasePath, name)
-	var bucketErr error
-	stat, err := os.Stat(bucketPath)
+	return &bucket{
+		bucket: name,
+		config: t.config,
+	}
+}
+
+func (t *ObjectStore) CreateBucket(ctx context.Context, name string, opts ...osi.BucketOption) error {
+	if err := checkBucketName(name); err != nil {
+		return err
+	}
+	options := osi.NewBucketOptions("", opts...)
+	err := os.Mkdir(filepath.Join(t.config.BasePath, name), dirMode(options.ACL))
 	if err != nil {
-		if os.IsNotExist(err) {
-			err = os.Mkdir(bucketPath, os.ModePerm)
-			if err != nil {
-				bucketErr = err
+		if os.IsExist(err) {
+			return osi.BucketAlreadyExists
+		}
+		return toError(err)
+	}
+	return nil
+}
+
+func (t *ObjectStore) DeleteBucket(ctx context.Context, name string) error {
+	if err := (&bucket{config: t.config, bucket: name}).check(); err != nil {
+		return err
+	}
+	bucketPath := filepath.Join(t.config.BasePath, name)
+	// directories left behind by deleted objects do not count as content,
+	// prior versions and delete markers do
+	err := filepath.WalkDir(bucketPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == metaDir {
+				versions, err := os.ReadDir(filepath.Join(path, versionsDir))
+				if err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				if len(versions) > 0 {
+					return osi.BucketNotEmpty
+				}
+				return filepath.SkipDir
 			}
-		} else {
-			bucketErr = err
+			return nil
 		}
-	} else {
-		if !stat.IsDir() {
-			bucketErr = errors.New("bucket path is not a directory")
+		return osi.BucketNotEmpty
+	})
+	if err != nil {
+		return toError(err)
+	}
+	return toError(os.RemoveAll(bucketPath))
+}
+
+// ListBuckets lists the directories below BasePath. Directories the OS has no
+// creation time for report their modification time as CreationDate.
+func (t *ObjectStore) ListBuckets(ctx context.Context) ([]osi.BucketInfo, error) {
+	entries, err := os.ReadDir(t.config.BasePath)
+	if err != nil {
+		return nil, toError(err)
+	}
+	var buckets = make([]osi.BucketInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || checkBucketName(entry.Name()) != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, toError(err)
 		}
+		buckets = append(buckets, osi.BucketInfo{Name: entry.Name(), CreationDate: info.ModTime()})
 	}
+	return buckets, nil
+}
 
-	return &bucket{
-		bucket:    name,
-		config:    t.config,
-		bucketErr: bucketErr,
+func (t *ObjectStore) BucketExists(ctx context.Context, name string) (bool, error) {
+	err := (&bucket{config: t.config, bucket: name}).check()
+	if errors.Is(err, osi.BucketNotFound) {
+		return false, nil
 	}
+	return err == nil, err
 }
 
 func (t *ObjectStore) ACLEnum() osi.ACLEnum {
@@ -94,107 +159,403 @@ func (t *ObjectStore) ACLEnum() osi.ACLEnum {
 }
 
 type bucket struct {
-	config    Config
-	bucket    string
-	bucketErr error
+	config Config
+	bucket string
 }
 
 func (t *bucket) fullPath(path string) string {
 	return fmt.Sprintf("%s/%s/%s", t.config.BasePath, t.bucket, path)
 }
 
-func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error) {
-	if t.bucketErr != nil {
-		return nil, t.bucketErr
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	file, stat, err := t.open(ctx, path, osi.NewGetOptions(opts...))
+	if err != nil {
+		return nil, err
 	}
-	file, err := os.Open(t.fullPath(path))
+	return osi.NewObject(t.bucket, path, strconv.FormatInt(int64(stat.Mode()), 10), file), nil
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	file, stat, err := t.open(ctx, path, osi.NewGetOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+	offset, length, err := rng.Resolve(stat.Size())
+	if err != nil {
+		_ = file.Close()
+		return nil, toError(err)
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		_ = file.Close()
+		return nil, toError(err)
+	}
+	reader := &sectionReader{Reader: io.LimitReader(file, length), Closer: file}
+	return osi.NewObject(t.bucket, path, strconv.FormatInt(int64(stat.Mode()), 10), reader), nil
+}
+
+// open opens the object at path, or the version of options, for a get once
+// its conditions hold and it can be read.
+func (t *bucket) open(ctx context.Context, path string, options *osi.GetOptions) (*os.File, os.FileInfo, error) {
+	if err := t.check(path); err != nil {
+		return nil, nil, err
+	}
+	if !options.Conditions.IsZero() || options.VersionID != "" {
+		unlock, err := t.lock(path)
+		if err != nil {
+			return nil, nil, toError(err)
+		}
+		defer unlock()
+	}
+	if !options.Conditions.IsZero() {
+		err := t.checkConditions(ctx, path, options.VersionID, http.MethodGet, options.Conditions)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	name := t.fullPath(path)
+	if options.VersionID != "" {
+		var err error
+		name, _, err = t.statVersion(path, options.VersionID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	file, err := os.Open(name)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, osi.ObjectNotFound
+			return nil, nil, osi.ObjectNotFound
 		}
-		return nil, err
+		return nil, nil, toError(err)
 	}
 	stat, err := file.Stat()
 	if err != nil {
-		return nil, err
+		_ = file.Close()
+		return nil, nil, toError(err)
 	}
-	return osi.NewObject(t.bucket, path, strconv.FormatInt(int64(stat.Mode()), 10), file), nil
+	err = t.checkRead(path, options.VersionID, options.CustomerKey)
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	return file, stat, nil
 }
 
-func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader) error {
-	if t.bucketErr != nil {
-		return t.bucketErr
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	if err := t.check(path); err != nil {
+		return err
+	}
+
+	options := osi.NewPutOptions(path, opts...)
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return err
+	}
+	if err := checkEncryption(options.Encryption); err != nil {
+		return err
+	}
+	err := os.MkdirAll(filepath.Dir(t.metaPath(path)), os.ModePerm)
+	if err != nil {
+		return toError(err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(t.metaPath(path)), "put-*")
+	if err != nil {
+		return toError(err)
+	}
+	defer os.Remove(temp.Name())
+
+	hash := md5.New()
+	_, err = io.Copy(io.MultiWriter(temp, hash), reader)
+	if err != nil {
+		_ = temp.Close()
+		return toError(err)
+	}
+	err = temp.Close()
+	if err != nil {
+		return toError(err)
+	}
+	err = os.Chmod(temp.Name(), fileMode(options.ACL))
+	if err != nil {
+		return toError(err)
+	}
+
+	status, err := t.versioning()
+	if err != nil {
+		return toError(err)
+	}
+	if !options.Conditions.IsZero() || status != osi.VersioningOff {
+		unlock, err := t.lock(path)
+		if err != nil {
+			return toError(err)
+		}
+		defer unlock()
+	}
+	if !options.Conditions.IsZero() {
+		err = t.checkConditions(ctx, path, "", http.MethodPut, options.Conditions)
+		if err != nil {
+			return err
+		}
 	}
-	return t.PutObjectWithACL(ctx, path, reader, aclEnum{}.Default())
+
+	meta := newObjectMeta(options)
+	meta.ETag = hex.EncodeToString(hash.Sum(nil))
+	return toError(t.commit(path, temp.Name(), meta, status))
 }
 
 func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
-	if t.bucketErr != nil {
-		return t.bucketErr
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
+}
+
+func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
+	if err := t.check(path); err != nil {
+		return false, err
 	}
 
-	err := os.MkdirAll(filepath.Dir(t.fullPath(path)), os.ModePerm)
+	stat, err := os.Stat(t.fullPath(path))
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, toError(err)
 	}
+	return !stat.IsDir(), nil
+}
 
-	fileMode := os.FileMode(0644)
-	if acl == "0666" {
-		fileMode = os.FileMode(0666)
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	if err := t.check(path); err != nil {
+		return err
 	}
+	options := osi.NewDeleteOptions(opts...)
+	status, err := t.versioning()
+	if err != nil {
+		return toError(err)
+	}
+	if !options.Conditions.IsZero() || status != osi.VersioningOff || options.VersionID != "" {
+		unlock, err := t.lock(path)
+		if err != nil {
+			return toError(err)
+		}
+		defer unlock()
+	}
+	if !options.Conditions.IsZero() {
+		err = t.checkConditions(ctx, path, options.VersionID, http.MethodDelete, options.Conditions)
+		if err != nil {
+			return err
+		}
+	}
+	if options.VersionID != "" {
+		return toError(t.deleteVersion(path, options.VersionID))
+	}
+	if status != osi.VersioningOff {
+		return toError(t.deleteMarker(path, status))
+	}
+	err = os.Remove(t.fullPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return toError(err)
+	}
+	return toError(t.removeMeta(path))
+}
 
-	if acl == "0600" {
-		fileMode = os.FileMode(0600)
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	if err := t.check(src, dst); err != nil {
+		return err
+	}
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	source := &bucket{config: t.config, bucket: options.SourceBucket}
+	stat, err := os.Stat(source.fullPath(src))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return osi.ObjectNotFound
+		}
+		return toError(err)
+	}
+	if stat.IsDir() {
+		return osi.ObjectNotFound
+	}
+	if source.fullPath(src) == t.fullPath(dst) {
+		return nil
 	}
 
-	file, err := os.OpenFile(t.fullPath(path), os.O_CREATE|os.O_WRONLY, fileMode)
+	status, err := t.versioning()
 	if err != nil {
+		return toError(err)
+	}
+	if status != osi.VersioningOff {
+		unlock, err := t.lock(dst)
+		if err != nil {
+			return toError(err)
+		}
+		defer unlock()
+	}
+	meta, err := source.readMeta(src)
+	if err != nil {
+		return toError(err)
+	}
+	if err := meta.checkRestored(); err != nil {
 		return err
 	}
-	defer file.Close()
-	_, err = io.Copy(file, reader)
-	return err
+	// the copy is stored in the default class, as on the providers
+	meta.StorageClass, meta.RestoreExpiry = "", time.Time{}
+	err = os.MkdirAll(filepath.Dir(t.metaPath(dst)), os.ModePerm)
+	if err != nil {
+		return toError(err)
+	}
+	// the link needs a name no other copy takes, which a directory of its
+	// own gives
+	tempDir, err := os.MkdirTemp(filepath.Dir(t.metaPath(dst)), "copy-*")
+	if err != nil {
+		return toError(err)
+	}
+	defer os.RemoveAll(tempDir)
+	temp := filepath.Join(tempDir, "object")
+	err = os.Link(source.fullPath(src), temp)
+	if err != nil {
+		err = copyFile(source.fullPath(src), temp, stat.Mode())
+		if err != nil {
+			return toError(err)
+		}
+	}
+	return toError(t.commit(dst, temp, meta, status))
 }
 
-func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
-	if t.bucketErr != nil {
-		return false, t.bucketErr
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	if err := t.check(src, dst); err != nil {
+		return err
 	}
-
-	_, err := os.Stat(t.fullPath(path))
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	source := &bucket{config: t.config, bucket: options.SourceBucket}
+	stat, err := os.Stat(source.fullPath(src))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return false, osi.ObjectNotFound
+			return osi.ObjectNotFound
 		}
-		return false, err
+		return toError(err)
+	}
+	if stat.IsDir() {
+		return osi.ObjectNotFound
+	}
+	if source.fullPath(src) == t.fullPath(dst) {
+		return nil
+	}
+	// versioned buckets keep the history of both keys
+	versioned, err := t.versioned(source)
+	if err != nil {
+		return toError(err)
+	}
+	if versioned {
+		err = t.CopyObject(ctx, src, dst, opts...)
+		if err != nil {
+			return err
+		}
+		return source.DeleteObject(ctx, src)
 	}
-	return true, nil
-}
 
-func (t *bucket) DeleteObject(ctx context.Context, path string) error {
-	if t.bucketErr != nil {
-		return t.bucketErr
+	meta, err := source.readMeta(src)
+	if err != nil {
+		return toError(err)
+	}
+	if err := meta.checkRestored(); err != nil {
+		return err
 	}
-	return os.Remove(t.fullPath(path))
+	meta.StorageClass, meta.RestoreExpiry = "", time.Time{}
+	err = os.MkdirAll(filepath.Dir(t.metaPath(dst)), os.ModePerm)
+	if err != nil {
+		return toError(err)
+	}
+	err = t.writeMeta(dst, meta)
+	if err != nil {
+		return toError(err)
+	}
+	err = os.Rename(source.fullPath(src), t.fullPath(dst))
+	if err != nil {
+		return toError(err)
+	}
+	return toError(source.removeMeta(src))
 }
 
 func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
-	if t.bucketErr != nil {
-		return nil, t.bucketErr
+	return osi.ListAll(ctx, t, prefix)
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	if err := checkPrefix(prefix); err != nil {
+		return nil, err
+	}
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
 	}
-	var oms = make([]osi.ObjectMeta, 0)
-	err := filepath.Walk(t.fullPath(prefix), func(path string, info os.FileInfo, err error) error {
-		if !info.IsDir() {
-			oms = append(oms, osi.NewObjectMeta(t.bucket, strings.TrimPrefix(path, t.config.BasePath+"/"+t.bucket+"/")))
+
+	var marker = options.StartAfter
+	if options.ContinuationToken > marker {
+		marker = options.ContinuationToken
+	}
+	var page = &osi.ObjectPage{Objects: make([]*osi.ObjectInfo, 0)}
+	var count = 0
+	err = t.walkFiles(prefix, options.Delimiter, &marker, func(file listedFile) error {
+		entry, isPrefix := commonPrefix(prefix, options.Delimiter, file.key)
+		if !isPrefix {
+			entry = file.key
+		}
+		if count == options.PageSize {
+			page.IsTruncated = true
+			return errStopWalk
+		}
+		if isPrefix {
+			page.CommonPrefixes = append(page.CommonPrefixes, entry)
+		} else {
+			info, err := t.objectInfo(file.key, file.info)
+			if err != nil {
+				return err
+			}
+			page.Objects = append(page.Objects, info)
 		}
+		marker = entry
+		count++
 		return nil
 	})
-	return oms, err
+	if err != nil {
+		return nil, toError(err)
+	}
+	if page.IsTruncated {
+		page.NextContinuationToken = marker
+	}
+	return page, nil
 }
 
 func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
-	if t.bucketErr != nil {
-		return nil, t.bucketErr
+	info, err := t.StatObject(ctx, path)
+	if err != nil {
+		return nil, toError(err)
+	}
+	return osi.NewSize(info.Size), nil
+}
+
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	if err := t.check(path); err != nil {
+		return nil, err
+	}
+	options := osi.NewGetOptions(opts...)
+	info, err := t.stat(path, options.VersionID)
+	if err != nil {
+		return nil, err
+	}
+	err = t.checkCustomerKey(path, options.VersionID, options.CustomerKey)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// stat returns the info of the object at path, or of its version versionID
+// when set, without the customer key StatObject requires.
+func (t *bucket) stat(path string, versionID string) (*osi.ObjectInfo, error) {
+	if versionID != "" {
+		_, info, err := t.statVersion(path, versionID)
+		return info, err
 	}
 
 	stat, err := os.Stat(t.fullPath(path))
@@ -202,27 +563,147 @@ func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, erro
 		if os.IsNotExist(err) {
 			return nil, osi.ObjectNotFound
 		}
+		return nil, toError(err)
+	}
+	if stat.IsDir() {
+		return nil, osi.ObjectNotFound
+	}
+	return t.objectInfo(path, stat)
+}
+
+// checkConditions evaluates conditions against the object at path, or its
+// version versionID when set, for a request with method. The caller holds
+// the lock on path.
+func (t *bucket) checkConditions(ctx context.Context, path string, versionID string, method string, conditions osi.Conditions) error {
+	info, err := t.stat(path, versionID)
+	if errors.Is(err, osi.ObjectNotFound) && method == http.MethodPut {
+		return conditions.Check(method, nil)
+	}
+	if err != nil {
+		return err
+	}
+	return conditions.Check(method, info)
+}
+
+func (t *bucket) objectInfo(path string, stat os.FileInfo) (*osi.ObjectInfo, error) {
+	meta, err := t.readMeta(path)
+	if err != nil {
 		return nil, err
 	}
-	return osi.NewSize(stat.Size()), nil
+	return t.newObjectInfo(path, stat, meta)
+}
+
+func (t *bucket) newObjectInfo(path string, stat os.FileInfo, meta *objectMeta) (*osi.ObjectInfo, error) {
+	info := osi.NewObjectInfo(t.bucket, path)
+	info.Size = stat.Size()
+	info.ETag = meta.ETag
+	if info.ETag == "" {
+		info.ETag = fmt.Sprintf("%x-%x", stat.ModTime().UnixNano(), stat.Size())
+	}
+	info.VersionID = meta.VersionID
+	info.LastModified = stat.ModTime()
+	info.ContentType = meta.ContentType
+	if info.ContentType == "" {
+		info.ContentType = mime.TypeByExtension(filepath.Ext(path))
+	}
+	info.ContentEncoding = meta.ContentEncoding
+	info.CacheControl = meta.CacheControl
+	info.ContentDisposition = meta.ContentDisposition
+	info.Expires = meta.Expires
+	info.StorageClass = meta.storageClass()
+	if osi.IsArchived(info.StorageClass) && meta.restored() {
+		info.Restore = &osi.RestoreStatus{ExpiryDate: meta.RestoreExpiry}
+	}
+	info.Encryption = meta.Encryption
+	if meta.Metadata != nil {
+		info.Metadata = meta.Metadata
+	}
+	return info, nil
 }
 
-func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration) (string, error) {
-	expires := time.Now().Add(expiredInDur).Unix()
-	signature := Sign(method, fmt.Sprintf("%s/%s", t.bucket, path), int(expires), t.config.HttpSecret)
-	return fmt.Sprintf("%s/%s/%s?expires=%d&signature=%s", t.config.HttpAddr, t.bucket, path, expires, signature), nil
+// SignURL names the headers the request has to carry in the headers parameter,
+// and signs their values along with the query. Puts carrying x-osi- headers
+// that are not signed are refused.
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	signed, err := t.signURL(ctx, path, method, expiredInDur, opts...)
+	osi.LogSignURL(ctx, t.config.Logger, Name, t.bucket, path, method, expiredInDur, signed, err)
+	return signed, err
+}
+
+func (t *bucket) signURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	options := osi.NewSignOptions(opts...)
+	query := options.Query()
+	query.Set("expires", strconv.FormatInt(time.Now().Add(expiredInDur).Unix(), 10))
+	header := options.Header(aclHeader)
+	if len(header) > 0 {
+		var names []string
+		for name := range header {
+			names = append(names, strings.ToLower(name))
+		}
+		sort.Strings(names)
+		query.Set("headers", strings.Join(names, ";"))
+	}
+	query.Set("signature", SignRequest(method, fmt.Sprintf("%s/%s", t.bucket, path), query, header, t.config.HttpSecret))
+	return fmt.Sprintf("%s/%s/%s?%s", t.config.HttpAddr, t.bucket, path, query.Encode()), nil
 }
 
 func (t *bucket) DeleteObjects(ctx context.Context, paths []string) error {
 	for i := range paths {
-		err := os.Remove(t.fullPath(paths[i]))
+		err := t.DeleteObject(ctx, paths[i])
 		if err != nil {
-			return err
+			return toError(err)
 		}
 	}
 	return nil
 }
 
+// dirMode gives bucket directories the permissions of fileMode plus the
+// matching search bits.
+func dirMode(acl osi.ACL) os.FileMode {
+	switch acl {
+	case "0666":
+		return os.FileMode(0777)
+	case "0600":
+		return os.FileMode(0700)
+	default:
+		return os.FileMode(0755)
+	}
+}
+
+func fileMode(acl osi.ACL) os.FileMode {
+	switch acl {
+	case "0666":
+		return os.FileMode(0666)
+	case "0600":
+		return os.FileMode(0600)
+	default:
+		return os.FileMode(0644)
+	}
+}
+
+func copyFile(src string, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+type sectionReader struct {
+	io.Reader
+	io.Closer
+}
+
 type aclEnum struct {
 }
 
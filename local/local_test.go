@@ -3,6 +3,7 @@ package local_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/burybell/osi"
 	"github.com/burybell/osi/local"
@@ -12,6 +13,8 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -39,9 +42,46 @@ func init() {
 		panic(err)
 	}
 	objectStore = local.MustNewObjectStore(config.Local)
+	err = objectStore.CreateBucket(ctx, config.LocalBucketName)
+	if err != nil && !errors.Is(err, osi.BucketAlreadyExists) {
+		panic(err)
+	}
 	bucket = objectStore.Bucket(config.LocalBucketName)
 }
 
+func TestObjectStore_Buckets(t *testing.T) {
+	name := fmt.Sprintf("osi-test-%d", time.Now().UnixNano())
+	exist, err := objectStore.BucketExists(ctx, name)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	assert.ErrorIs(t, objectStore.Bucket(name).PutObject(ctx, "test/example.txt", strings.NewReader("some text")), osi.BucketNotFound)
+
+	err = objectStore.CreateBucket(ctx, name)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, objectStore.CreateBucket(ctx, name), osi.BucketAlreadyExists)
+	exist, err = objectStore.BucketExists(ctx, name)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	buckets, err := objectStore.ListBuckets(ctx)
+	assert.NoError(t, err)
+	var names []string
+	for _, info := range buckets {
+		names = append(names, info.Name)
+	}
+	assert.Contains(t, names, name)
+
+	err = objectStore.Bucket(name).PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, objectStore.DeleteBucket(ctx, name), osi.BucketNotEmpty)
+	err = objectStore.Bucket(name).DeleteObject(ctx, "test/example.txt")
+	assert.NoError(t, err)
+	err = objectStore.DeleteBucket(ctx, name)
+	assert.NoError(t, err)
+	exist, err = objectStore.BucketExists(ctx, name)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
 func TestBucket_PutObject(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -54,6 +94,24 @@ func TestBucket_PutObject(t *testing.T) {
 	assert.Equal(t, "some text", string(bs))
 }
 
+func TestBucket_PutObjectWithOptions(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/example.json", strings.NewReader("{}"),
+		osi.WithContentType("application/json"),
+		osi.WithCacheControl("no-cache"),
+		osi.WithContentDisposition("attachment; filename=example.json"),
+		osi.WithMetadata(map[string]string{"owner": "example"}),
+	)
+	assert.NoError(t, err)
+	info, err := bucket.StatObject(ctx, "test/example.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", info.ContentType)
+	assert.Equal(t, "no-cache", info.CacheControl)
+	assert.Equal(t, "attachment; filename=example.json", info.ContentDisposition)
+	assert.Equal(t, "example", info.Metadata["owner"])
+	err = bucket.DeleteObject(ctx, "test/example.json")
+	assert.NoError(t, err)
+}
+
 func TestBucket_DeleteObject(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -63,10 +121,384 @@ func TestBucket_DeleteObject(t *testing.T) {
 	assert.ErrorIs(t, err, osi.ObjectNotFound)
 }
 
+func TestBucket_CopyObject(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"), osi.WithMetadata(map[string]string{"owner": "example"}))
+	assert.NoError(t, err)
+	err = bucket.CopyObject(ctx, "test/example.txt", "test/copy/example.txt")
+	assert.NoError(t, err)
+	object, err := bucket.GetObject(ctx, "test/copy/example.txt")
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "some text", string(bs))
+	_ = object.Close()
+	info, err := bucket.StatObject(ctx, "test/copy/example.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "example", info.Metadata["owner"])
+
+	err = bucket.MoveObject(ctx, "test/copy/example.txt", "test/move/example.txt")
+	assert.NoError(t, err)
+	_, err = bucket.StatObject(ctx, "test/copy/example.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	_, err = bucket.StatObject(ctx, "test/move/example.txt")
+	assert.NoError(t, err)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/move/example.txt"))
+
+	err = bucket.CopyObject(ctx, "test/not-exist.txt", "test/copy/example.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+
+	// concurrent copies into one directory keep apart
+	var wg sync.WaitGroup
+	var paths []string
+	for i := 0; i < 10; i++ {
+		src, dst := fmt.Sprintf("test/concurrent/%d.txt", i), fmt.Sprintf("test/copy/%d.txt", i)
+		paths = append(paths, src, dst)
+		assert.NoError(t, bucket.PutObject(ctx, src, strings.NewReader(fmt.Sprintf("text %d", i))))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, bucket.CopyObject(ctx, src, dst))
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 10; i++ {
+		object, err := bucket.GetObject(ctx, fmt.Sprintf("test/copy/%d.txt", i))
+		assert.NoError(t, err)
+		bs, err := io.ReadAll(object)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("text %d", i), string(bs))
+		_ = object.Close()
+	}
+	assert.NoError(t, bucket.DeleteObjects(ctx, paths))
+}
+
+func TestBucket_MultipartUpload(t *testing.T) {
+	first := strings.Repeat("a", osi.MinPartSize)
+	uploadID, err := bucket.InitiateMultipartUpload(ctx, "test/multipart.txt", osi.WithContentType("text/plain"))
+	assert.NoError(t, err)
+	part1, err := bucket.UploadPart(ctx, "test/multipart.txt", uploadID, 1, strings.NewReader(first), int64(len(first)))
+	assert.NoError(t, err)
+	part2, err := bucket.UploadPart(ctx, "test/multipart.txt", uploadID, 2, strings.NewReader("some text"), 9)
+	assert.NoError(t, err)
+	parts, err := bucket.ListParts(ctx, "test/multipart.txt", uploadID)
+	assert.NoError(t, err)
+	assert.Len(t, parts, 2)
+	err = bucket.CompleteMultipartUpload(ctx, "test/multipart.txt", uploadID, []osi.Part{part2, part1})
+	assert.NoError(t, err)
+	object, err := bucket.GetObject(ctx, "test/multipart.txt")
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, first+"some text", string(bs))
+	_ = object.Close()
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/multipart.txt"))
+
+	uploadID, err = bucket.InitiateMultipartUpload(ctx, "test/multipart.txt")
+	assert.NoError(t, err)
+	err = bucket.AbortMultipartUpload(ctx, "test/multipart.txt", uploadID)
+	assert.NoError(t, err)
+	_, err = bucket.ListParts(ctx, "test/multipart.txt", uploadID)
+	assert.ErrorIs(t, err, osi.UploadNotFound)
+}
+
 func TestBucket_GetObject(t *testing.T) {
 	TestBucket_PutObject(t)
 }
 
+func TestBucket_GetObjectConditions(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/conditions.txt", strings.NewReader("some text"))
+	assert.NoError(t, err)
+	info, err := bucket.StatObject(ctx, "test/conditions.txt")
+	assert.NoError(t, err)
+
+	object, err := bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfMatch: info.ETag}))
+	assert.NoError(t, err)
+	assert.NoError(t, object.Close())
+	_, err = bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfMatch: "0123456789abcdef0123456789abcdef"}))
+	assert.ErrorIs(t, err, osi.PreconditionFailed)
+	_, err = bucket.GetObjectRange(ctx, "test/conditions.txt", osi.NewRange(0, 4), osi.WithGetConditions(osi.Conditions{IfMatch: "0123456789abcdef0123456789abcdef"}))
+	assert.ErrorIs(t, err, osi.PreconditionFailed)
+	_, err = bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfNoneMatch: info.ETag}))
+	assert.ErrorIs(t, err, osi.NotModified)
+	_, err = bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfModifiedSince: info.LastModified.Add(time.Minute)}))
+	assert.ErrorIs(t, err, osi.NotModified)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/conditions.txt"))
+}
+
+func TestBucket_ConditionalWrites(t *testing.T) {
+	_ = bucket.DeleteObject(ctx, "test/create-only.txt")
+	var wg sync.WaitGroup
+	var created int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := bucket.PutObject(ctx, "test/create-only.txt", strings.NewReader(fmt.Sprintf("writer %d", i)),
+				osi.WithPutConditions(osi.Conditions{IfNoneMatch: "*"}))
+			if err == nil {
+				atomic.AddInt32(&created, 1)
+				return
+			}
+			assert.ErrorIs(t, err, osi.PreconditionFailed)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), created)
+
+	info, err := bucket.StatObject(ctx, "test/create-only.txt")
+	assert.NoError(t, err)
+	err = bucket.PutObject(ctx, "test/create-only.txt", strings.NewReader("updated"), osi.WithPutConditions(osi.Conditions{IfMatch: info.ETag}))
+	assert.NoError(t, err)
+	err = bucket.PutObject(ctx, "test/create-only.txt", strings.NewReader("lost update"), osi.WithPutConditions(osi.Conditions{IfMatch: info.ETag}))
+	assert.ErrorIs(t, err, osi.PreconditionFailed)
+
+	err = bucket.DeleteObject(ctx, "test/create-only.txt", osi.WithDeleteConditions(osi.Conditions{IfMatch: info.ETag}))
+	assert.ErrorIs(t, err, osi.PreconditionFailed)
+	info, err = bucket.StatObject(ctx, "test/create-only.txt")
+	assert.NoError(t, err)
+	err = bucket.DeleteObject(ctx, "test/create-only.txt", osi.WithDeleteConditions(osi.Conditions{IfMatch: info.ETag}))
+	assert.NoError(t, err)
+	exist, err := bucket.HeadObject(ctx, "test/create-only.txt")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func TestBucket_Versioning(t *testing.T) {
+	name := fmt.Sprintf("osi-test-%d", time.Now().UnixNano())
+	assert.NoError(t, objectStore.CreateBucket(ctx, name))
+	versioned := objectStore.Bucket(name)
+	status, err := versioned.GetVersioning(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, osi.VersioningOff, status)
+	assert.NoError(t, versioned.SetVersioning(ctx, osi.VersioningEnabled))
+
+	for _, content := range []string{"first", "second", "third"} {
+		assert.NoError(t, versioned.PutObject(ctx, "test/example.txt", strings.NewReader(content)))
+	}
+	page, err := versioned.ListObjectVersions(ctx, "test/")
+	assert.NoError(t, err)
+	assert.Len(t, page.Versions, 3)
+	assert.True(t, page.Versions[0].IsLatest)
+	assert.False(t, page.Versions[1].IsLatest)
+	first := page.Versions[2].VersionID
+
+	object, err := versioned.GetObject(ctx, "test/example.txt", osi.WithGetVersion(first))
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(bs))
+	_ = object.Close()
+	info, err := versioned.StatObject(ctx, "test/example.txt", osi.WithGetVersion(first))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), info.Size)
+
+	// a plain delete hides the object behind a delete marker
+	assert.NoError(t, versioned.DeleteObject(ctx, "test/example.txt"))
+	_, err = versioned.StatObject(ctx, "test/example.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	page, err = versioned.ListObjectVersions(ctx, "test/")
+	assert.NoError(t, err)
+	assert.Len(t, page.Versions, 4)
+	assert.True(t, page.Versions[0].IsLatest)
+	assert.True(t, page.Versions[0].IsDeleteMarker)
+	assert.NoError(t, versioned.DeleteObject(ctx, "test/example.txt"))
+	assert.ErrorIs(t, objectStore.DeleteBucket(ctx, name), osi.BucketNotEmpty)
+
+	// removing the delete markers restores the latest version
+	page, err = versioned.ListObjectVersions(ctx, "test/")
+	assert.NoError(t, err)
+	for _, version := range page.Versions {
+		if version.IsDeleteMarker {
+			assert.NoError(t, versioned.DeleteObject(ctx, "test/example.txt", osi.WithDeleteVersion(version.VersionID)))
+		}
+	}
+	object, err = versioned.GetObject(ctx, "test/example.txt")
+	assert.NoError(t, err)
+	bs, err = io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "third", string(bs))
+	_ = object.Close()
+
+	// restoring an old version copies it over the latest one
+	older, err := versioned.GetObject(ctx, "test/example.txt", osi.WithGetVersion(first))
+	assert.NoError(t, err)
+	assert.NoError(t, versioned.PutObject(ctx, "test/example.txt", older))
+	_ = older.Close()
+	info, err = versioned.StatObject(ctx, "test/example.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), info.Size)
+
+	// pages resume after the last version returned
+	var versions []*osi.ObjectVersion
+	var token = ""
+	for {
+		page, err = versioned.ListObjectVersions(ctx, "test/", osi.WithPageSize(2), osi.WithContinuationToken(token))
+		assert.NoError(t, err)
+		versions = append(versions, page.Versions...)
+		if !page.IsTruncated {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+	assert.Len(t, versions, 4)
+
+	// while suspended, new writes replace the null version
+	assert.NoError(t, versioned.SetVersioning(ctx, osi.VersioningSuspended))
+	assert.NoError(t, versioned.PutObject(ctx, "test/example.txt", strings.NewReader("null")))
+	assert.NoError(t, versioned.PutObject(ctx, "test/example.txt", strings.NewReader("null again")))
+	info, err = versioned.StatObject(ctx, "test/example.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, osi.NullVersionID, info.VersionID)
+	page, err = versioned.ListObjectVersions(ctx, "test/")
+	assert.NoError(t, err)
+	assert.Len(t, page.Versions, 5)
+
+	for _, version := range page.Versions {
+		assert.NoError(t, versioned.DeleteObject(ctx, "test/example.txt", osi.WithDeleteVersion(version.VersionID)))
+	}
+	page, err = versioned.ListObjectVersions(ctx, "test/")
+	assert.NoError(t, err)
+	assert.Empty(t, page.Versions)
+	assert.NoError(t, objectStore.DeleteBucket(ctx, name))
+}
+
+func TestBucket_ObjectTags(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/tags.txt", strings.NewReader("some text"), osi.WithTags(map[string]string{"team": "storage"}))
+	assert.NoError(t, err)
+	tags, err := bucket.GetObjectTags(ctx, "test/tags.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "storage"}, tags)
+
+	err = bucket.PutObjectTags(ctx, "test/tags.txt", map[string]string{"team": "billing", "tier": "cold"})
+	assert.NoError(t, err)
+	tags, err = bucket.GetObjectTags(ctx, "test/tags.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "billing", "tier": "cold"}, tags)
+	assert.ErrorIs(t, bucket.PutObjectTags(ctx, "test/tags.txt", map[string]string{"": "empty"}), osi.InvalidTag)
+
+	assert.NoError(t, bucket.DeleteObjectTags(ctx, "test/tags.txt"))
+	tags, err = bucket.GetObjectTags(ctx, "test/tags.txt")
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/tags.txt"))
+}
+
+func TestBucket_Encryption(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	other := []byte("fedcba9876543210fedcba9876543210")
+	defer func() {
+		_ = bucket.DeleteObjects(ctx, []string{"test/sse-c.txt", "test/sse-s3.txt", "test/sse-c-multipart.txt"})
+	}()
+	err := bucket.PutObject(ctx, "test/sse-c.txt", strings.NewReader("some text"), osi.WithEncryption(osi.SSEC(key)))
+	assert.NoError(t, err)
+	info, err := bucket.StatObject(ctx, "test/sse-c.txt", osi.WithCustomerKey(key))
+	assert.NoError(t, err)
+	assert.Equal(t, osi.EncryptionSSEC, info.Encryption)
+	_, err = bucket.StatObject(ctx, "test/sse-c.txt")
+	assert.ErrorIs(t, err, osi.InvalidEncryption)
+	_, err = bucket.GetObject(ctx, "test/sse-c.txt", osi.WithCustomerKey(other))
+	assert.ErrorIs(t, err, osi.AccessDenied)
+	_, err = bucket.GetObjectRange(ctx, "test/sse-c.txt", osi.NewRange(0, 4))
+	assert.ErrorIs(t, err, osi.InvalidEncryption)
+	object, err := bucket.GetObjectRange(ctx, "test/sse-c.txt", osi.NewRange(0, 4), osi.WithCustomerKey(key))
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "some", string(bs))
+	_ = object.Close()
+
+	err = bucket.PutObject(ctx, "test/sse-s3.txt", strings.NewReader("some text"), osi.WithEncryption(osi.SSES3()))
+	assert.NoError(t, err)
+	info, err = bucket.StatObject(ctx, "test/sse-s3.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, osi.EncryptionSSES3, info.Encryption)
+	_, err = bucket.GetObject(ctx, "test/sse-s3.txt", osi.WithCustomerKey(key))
+	assert.ErrorIs(t, err, osi.InvalidEncryption)
+
+	err = bucket.PutObject(ctx, "test/sse-kms.txt", strings.NewReader("some text"), osi.WithEncryption(osi.SSEKMS("")))
+	assert.ErrorIs(t, err, osi.NotSupported)
+	err = bucket.PutObject(ctx, "test/sse-c.txt", strings.NewReader("some text"), osi.WithEncryption(osi.SSEC(key[1:])))
+	assert.ErrorIs(t, err, osi.InvalidEncryption)
+
+	uploadID, err := bucket.InitiateMultipartUpload(ctx, "test/sse-c-multipart.txt", osi.WithEncryption(osi.SSEC(key)))
+	assert.NoError(t, err)
+	_, err = bucket.UploadPart(ctx, "test/sse-c-multipart.txt", uploadID, 1, strings.NewReader("some text"), 9)
+	assert.ErrorIs(t, err, osi.InvalidEncryption)
+	part, err := bucket.UploadPart(ctx, "test/sse-c-multipart.txt", uploadID, 1, strings.NewReader("some text"), 9, osi.WithEncryption(osi.SSEC(key)))
+	assert.NoError(t, err)
+	assert.NoError(t, bucket.CompleteMultipartUpload(ctx, "test/sse-c-multipart.txt", uploadID, []osi.Part{part}))
+	info, err = bucket.StatObject(ctx, "test/sse-c-multipart.txt", osi.WithCustomerKey(key))
+	assert.NoError(t, err)
+	assert.Equal(t, osi.EncryptionSSEC, info.Encryption)
+}
+
+func TestBucket_StorageClass(t *testing.T) {
+	defer func() {
+		_ = bucket.DeleteObjects(ctx, []string{"test/archive.txt", "test/archive-copy.txt"})
+	}()
+	err := bucket.PutObject(ctx, "test/archive.txt", strings.NewReader("some text"), osi.WithStorageClass(osi.StorageClassArchive))
+	assert.NoError(t, err)
+	info, err := bucket.StatObject(ctx, "test/archive.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, osi.StorageClassArchive, info.StorageClass)
+	assert.Nil(t, info.Restore)
+	assert.False(t, info.Readable())
+	_, err = bucket.GetObject(ctx, "test/archive.txt")
+	assert.ErrorIs(t, err, osi.NotRestored)
+	_, err = bucket.GetObjectRange(ctx, "test/archive.txt", osi.NewRange(0, 4))
+	assert.ErrorIs(t, err, osi.NotRestored)
+	assert.ErrorIs(t, bucket.CopyObject(ctx, "test/archive.txt", "test/archive-copy.txt"), osi.NotRestored)
+	assert.ErrorIs(t, bucket.SetStorageClass(ctx, "test/archive.txt", osi.StorageClassStandard), osi.NotRestored)
+
+	assert.ErrorIs(t, bucket.RestoreObject(ctx, "test/archive.txt", 0, osi.RestoreStandard), osi.InvalidRestore)
+	assert.NoError(t, bucket.RestoreObject(ctx, "test/archive.txt", 1, osi.RestoreExpedited))
+	info, err = bucket.StatObject(ctx, "test/archive.txt")
+	assert.NoError(t, err)
+	assert.True(t, info.Readable())
+	assert.False(t, info.Restore.Ongoing)
+	object, err := bucket.GetObject(ctx, "test/archive.txt")
+	assert.NoError(t, err)
+	_ = object.Close()
+
+	assert.NoError(t, bucket.CopyObject(ctx, "test/archive.txt", "test/archive-copy.txt"))
+	info, err = bucket.StatObject(ctx, "test/archive-copy.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, osi.StorageClassStandard, info.StorageClass)
+	assert.ErrorIs(t, bucket.RestoreObject(ctx, "test/archive-copy.txt", 1, ""), osi.InvalidRestore)
+
+	assert.NoError(t, bucket.SetStorageClass(ctx, "test/archive.txt", osi.StorageClassIA))
+	info, err = bucket.StatObject(ctx, "test/archive.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, osi.StorageClassIA, info.StorageClass)
+	assert.Nil(t, info.Restore)
+	assert.ErrorIs(t, bucket.SetStorageClass(ctx, "test/missing.txt", osi.StorageClassIA), osi.ObjectNotFound)
+}
+
+func TestBucket_GetObjectRange(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
+	assert.NoError(t, err)
+	object, err := bucket.GetObjectRange(ctx, "test/example.txt", osi.NewRange(2, 4))
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "me t", string(bs))
+	_ = object.Close()
+
+	object, err = bucket.GetObjectRange(ctx, "test/example.txt", osi.RangeFrom(5))
+	assert.NoError(t, err)
+	bs, err = io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "text", string(bs))
+	_ = object.Close()
+
+	object, err = bucket.GetObjectRange(ctx, "test/example.txt", osi.RangeLast(3))
+	assert.NoError(t, err)
+	bs, err = io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "ext", string(bs))
+	_ = object.Close()
+}
+
 func TestBucket_GetObjectSize(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -75,6 +507,31 @@ func TestBucket_GetObjectSize(t *testing.T) {
 	assert.Equal(t, int64(9), size.Size())
 }
 
+func TestBucket_StatObject(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
+	assert.NoError(t, err)
+	info, err := bucket.StatObject(ctx, "test/example.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "test/example.txt", info.ObjectPath())
+	assert.Equal(t, int64(9), info.Size)
+	assert.NotEmpty(t, info.ETag)
+	assert.False(t, info.LastModified.IsZero())
+	assert.Equal(t, "text/plain; charset=utf-8", info.ContentType)
+	_, err = bucket.StatObject(ctx, "test/not-exist.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+}
+
+func TestBucket_ObjectNotFound(t *testing.T) {
+	exist, err := bucket.HeadObject(ctx, "test/not-exist.txt")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	_, err = bucket.StatObject(ctx, "test/not-exist.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	_, err = bucket.GetObject(ctx, "test/not-exist.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/not-exist.txt"))
+}
+
 func TestBucket_HeadObject(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -91,6 +548,82 @@ func TestBucket_ListObject(t *testing.T) {
 	assert.Equal(t, 1, len(objects))
 }
 
+func TestBucket_ListObjectsPage(t *testing.T) {
+	paths := []string{"test/list/a.txt", "test/list/b.txt", "test/list/sub/c.txt"}
+	for _, path := range paths {
+		err := bucket.PutObject(ctx, path, strings.NewReader("some text"))
+		assert.NoError(t, err)
+	}
+	defer func() {
+		assert.NoError(t, bucket.DeleteObjects(ctx, paths))
+	}()
+
+	page, err := bucket.ListObjectsPage(ctx, "test/list/", osi.WithDelimiter("/"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(page.Objects))
+	assert.Equal(t, []string{"test/list/sub/"}, page.CommonPrefixes)
+	assert.False(t, page.IsTruncated)
+	for _, object := range page.Objects {
+		assert.Equal(t, int64(9), object.Size)
+		assert.NotEmpty(t, object.ETag)
+		assert.NotEmpty(t, object.StorageClass)
+		assert.False(t, object.LastModified.IsZero())
+	}
+
+	var keys = make([]string, 0)
+	iterator := osi.NewObjectIterator(bucket, "test/list/", osi.WithPageSize(1))
+	for iterator.Next(ctx) {
+		keys = append(keys, iterator.Object().ObjectPath())
+	}
+	assert.NoError(t, iterator.Err())
+	assert.Equal(t, paths, keys)
+
+	page, err = bucket.ListObjectsPage(ctx, "test/list/", osi.WithStartAfter("test/list/a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(page.Objects))
+}
+
+func TestBucket_ListObjectsOrder(t *testing.T) {
+	// "-" and "." sort before the "/" that follows a directory name
+	paths := []string{"test/order/a-b.txt", "test/order/a.txt", "test/order/a/b.txt", "test/order/a/c/d.txt", "test/order/a0.txt", "test/order/b/e.txt"}
+	for _, path := range paths {
+		err := bucket.PutObject(ctx, path, strings.NewReader("some text"))
+		assert.NoError(t, err)
+	}
+	defer func() {
+		assert.NoError(t, bucket.DeleteObjects(ctx, paths))
+	}()
+
+	objects, err := bucket.ListObjects(ctx, "test/order/")
+	assert.NoError(t, err)
+	var keys = make([]string, 0)
+	for _, object := range objects {
+		keys = append(keys, object.ObjectPath())
+	}
+	assert.Equal(t, paths, keys)
+
+	keys = keys[:0]
+	iterator := osi.NewObjectIterator(bucket, "test/order/a", osi.WithPageSize(2))
+	for iterator.Next(ctx) {
+		keys = append(keys, iterator.Object().ObjectPath())
+	}
+	assert.NoError(t, iterator.Err())
+	assert.Equal(t, paths[:5], keys)
+
+	var entries = make([]string, 0)
+	pager := osi.NewObjectPager(bucket, "test/order/", osi.WithDelimiter("/"), osi.WithPageSize(1))
+	for pager.Next(ctx) {
+		page := pager.Page()
+		assert.Equal(t, 1, len(page.Objects)+len(page.CommonPrefixes))
+		for _, object := range page.Objects {
+			entries = append(entries, object.ObjectPath())
+		}
+		entries = append(entries, page.CommonPrefixes...)
+	}
+	assert.NoError(t, pager.Err())
+	assert.Equal(t, []string{"test/order/a-b.txt", "test/order/a.txt", "test/order/a/", "test/order/a0.txt", "test/order/b/"}, entries)
+}
+
 func Test_bucket_SignURL(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -125,3 +658,32 @@ func Test_bucket_DeleteObjects(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, exist)
 }
+
+type logger struct {
+	fields []map[string]interface{}
+}
+
+func (t *logger) Log(ctx context.Context, level osi.LogLevel, msg string, fields ...osi.LogField) {
+	event := map[string]interface{}{"level": level, "msg": msg}
+	for _, field := range fields {
+		event[field.Key] = field.Value
+	}
+	t.fields = append(t.fields, event)
+}
+
+func TestConfig_Logger(t *testing.T) {
+	recorder := &logger{}
+	store := local.MustNewObjectStore(local.Config{BasePath: t.TempDir(), Logger: recorder})
+	assert.NoError(t, store.CreateBucket(ctx, "example"))
+	_, err := store.Bucket("example").SignURL(ctx, "test/logged.txt", http.MethodGet, time.Minute)
+	assert.NoError(t, err)
+	_, err = store.Bucket("example").SignPostPolicy(ctx, osi.PostPolicy{KeyPrefix: "test/"}, time.Minute)
+	assert.NoError(t, err)
+
+	assert.Len(t, recorder.fields, 2)
+	assert.Equal(t, "presigned url", recorder.fields[0]["msg"])
+	assert.Equal(t, "test/logged.txt", recorder.fields[0]["key"])
+	assert.Contains(t, recorder.fields[0]["url"], "signature="+osi.Redacted)
+	assert.Equal(t, "presigned post policy", recorder.fields[1]["msg"])
+	assert.Equal(t, "test/${filename}", recorder.fields[1]["key"])
+}
@@ -0,0 +1,136 @@
+package local
+
+import (
+	"encoding/json"
+	"github.com/burybell/osi"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// metaDir is a hidden directory next to each stored file that holds the
+// sidecar state osi keeps for it. It is never reported as an object.
+const metaDir = ".osi"
+
+type objectMeta struct {
+	ETag               string             `json:"etag,omitempty"`
+	ContentType        string             `json:"content_type,omitempty"`
+	ContentEncoding    string             `json:"content_encoding,omitempty"`
+	CacheControl       string             `json:"cache_control,omitempty"`
+	ContentDisposition string             `json:"content_disposition,omitempty"`
+	Expires            time.Time          `json:"expires,omitempty"`
+	Metadata           map[string]string  `json:"metadata,omitempty"`
+	Tags               map[string]string  `json:"tags,omitempty"`
+	VersionID          string             `json:"version_id,omitempty"`
+	Encryption         osi.EncryptionMode `json:"encryption,omitempty"`
+	// CustomerKeyMD5 is the base64 encoded MD5 of the SSE-C key.
+	CustomerKeyMD5 string    `json:"customer_key_md5,omitempty"`
+	StorageClass   string    `json:"storage_class,omitempty"`
+	RestoreExpiry  time.Time `json:"restore_expiry,omitempty"`
+	// LastModified and DeleteMarker are kept for prior versions only.
+	LastModified time.Time `json:"last_modified,omitempty"`
+	DeleteMarker bool      `json:"delete_marker,omitempty"`
+}
+
+func newObjectMeta(options *osi.PutOptions) *objectMeta {
+	return &objectMeta{
+		ContentType:        options.ContentType,
+		ContentEncoding:    options.ContentEncoding,
+		CacheControl:       options.CacheControl,
+		ContentDisposition: options.ContentDisposition,
+		Expires:            options.Expires,
+		Metadata:           osi.NormalizeMetadata(options.Metadata),
+		Tags:               options.Tags,
+		Encryption:         options.Encryption.Mode,
+		CustomerKeyMD5:     customerKeyMD5(options.Encryption.CustomerKey),
+		StorageClass:       options.StorageClass,
+	}
+}
+
+func (t *bucket) metaPath(path string) string {
+	dir, name := filepath.Split(t.fullPath(path))
+	return filepath.Join(dir, metaDir, name+".json")
+}
+
+func (t *bucket) readMeta(path string) (*objectMeta, error) {
+	meta := &objectMeta{}
+	bs, err := os.ReadFile(t.metaPath(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return meta, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(bs, meta)
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+func (t *bucket) writeMeta(path string, meta *objectMeta) error {
+	metaPath := t.metaPath(path)
+	err := os.MkdirAll(filepath.Dir(metaPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	bs, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metaPath, bs, 0644)
+}
+
+// updateMeta applies update to the metadata of the existing object at path
+// under its lock, and writes it back unless update fails.
+func (t *bucket) updateMeta(path string, update func(meta *objectMeta) error) error {
+	unlock, err := t.lock(path)
+	if err != nil {
+		return toError(err)
+	}
+	defer unlock()
+	if err := t.checkExists(path); err != nil {
+		return err
+	}
+	meta, err := t.readMeta(path)
+	if err != nil {
+		return toError(err)
+	}
+	if err := update(meta); err != nil {
+		return err
+	}
+	return toError(t.writeMeta(path, meta))
+}
+
+func (t *bucket) removeMeta(path string) error {
+	err := os.Remove(t.metaPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// lock takes an exclusive lock on the object at path, held in a lock file
+// beside its metadata, so that conditional requests on it from any process
+// check and change the object atomically.
+func (t *bucket) lock(path string) (func(), error) {
+	lockPath := strings.TrimSuffix(t.metaPath(path), ".json") + ".lock"
+	err := os.MkdirAll(filepath.Dir(lockPath), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	err = lockFile(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return func() {
+		_ = unlockFile(file)
+		_ = file.Close()
+	}, nil
+}
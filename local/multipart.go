@@ -0,0 +1,291 @@
+package local
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/burybell/osi"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// uploadsDir holds one staging directory per pending multipart upload,
+// below the hidden metaDir of the bucket root.
+const uploadsDir = "uploads"
+
+type upload struct {
+	Path string      `json:"path"`
+	ACL  osi.ACL     `json:"acl,omitempty"`
+	Meta *objectMeta `json:"meta"`
+}
+
+func (t *bucket) uploadPath(uploadID string) (string, error) {
+	if id, err := hex.DecodeString(uploadID); err != nil || len(id) != 16 {
+		return "", osi.UploadNotFound
+	}
+	return filepath.Join(t.config.BasePath, t.bucket, metaDir, uploadsDir, uploadID), nil
+}
+
+func (t *bucket) readUpload(path string, uploadID string) (string, *upload, error) {
+	dir, err := t.uploadPath(uploadID)
+	if err != nil {
+		return "", nil, err
+	}
+	bs, err := os.ReadFile(filepath.Join(dir, "upload.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, osi.UploadNotFound
+		}
+		return "", nil, err
+	}
+	var u upload
+	err = json.Unmarshal(bs, &u)
+	if err != nil {
+		return "", nil, err
+	}
+	if u.Path != path {
+		return "", nil, osi.UploadNotFound
+	}
+	return dir, &u, nil
+}
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	if err := t.check(path); err != nil {
+		return "", err
+	}
+	options := osi.NewPutOptions(path, opts...)
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return "", err
+	}
+	if err := checkEncryption(options.Encryption); err != nil {
+		return "", err
+	}
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", toError(err)
+	}
+	uploadID := hex.EncodeToString(id)
+	dir, err := t.uploadPath(uploadID)
+	if err != nil {
+		return "", toError(err)
+	}
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", toError(err)
+	}
+	bs, err := json.Marshal(&upload{Path: path, ACL: options.ACL, Meta: newObjectMeta(options)})
+	if err != nil {
+		return "", toError(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "upload.json"), bs, 0644)
+	if err != nil {
+		return "", toError(err)
+	}
+	return uploadID, nil
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	if err := t.check(path); err != nil {
+		return osi.Part{}, err
+	}
+	if err := osi.ValidatePartNumber(partNumber); err != nil {
+		return osi.Part{}, err
+	}
+	dir, u, err := t.readUpload(path, uploadID)
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	err = u.Meta.checkCustomerKey(osi.NewPutOptions(path, opts...).Encryption.CustomerKey)
+	if err != nil {
+		return osi.Part{}, err
+	}
+
+	temp, err := os.CreateTemp(dir, "part-*")
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	defer os.Remove(temp.Name())
+
+	hash := md5.New()
+	written, err := io.Copy(io.MultiWriter(temp, hash), io.LimitReader(reader, size))
+	if err != nil {
+		_ = temp.Close()
+		return osi.Part{}, toError(err)
+	}
+	err = temp.Close()
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	if written != size {
+		return osi.Part{}, fmt.Errorf("part %d: read %d bytes, expected %d", partNumber, written, size)
+	}
+
+	// a part uploaded again under the same number replaces the previous one
+	olds, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf("%05d-*", partNumber)))
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	for _, old := range olds {
+		err = os.Remove(old)
+		if err != nil {
+			return osi.Part{}, toError(err)
+		}
+	}
+	etag := hex.EncodeToString(hash.Sum(nil))
+	err = os.Rename(temp.Name(), filepath.Join(dir, fmt.Sprintf("%05d-%s", partNumber, etag)))
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	return osi.Part{PartNumber: partNumber, ETag: etag, Size: size}, nil
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	if err := t.check(path); err != nil {
+		return nil, err
+	}
+	dir, _, err := t.readUpload(path, uploadID)
+	if err != nil {
+		return nil, toError(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, toError(err)
+	}
+	var parts = make([]osi.Part, 0, len(entries))
+	for _, entry := range entries {
+		name := strings.SplitN(entry.Name(), "-", 2)
+		if len(name) != 2 {
+			continue
+		}
+		partNumber, err := strconv.Atoi(name[0])
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, toError(err)
+		}
+		parts = append(parts, osi.Part{
+			PartNumber:   partNumber,
+			ETag:         name[1],
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	}
+	return parts, nil
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	if err := t.check(path); err != nil {
+		return err
+	}
+	dir, u, err := t.readUpload(path, uploadID)
+	if err != nil {
+		return toError(err)
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("upload %s: no parts to complete", uploadID)
+	}
+	parts = append([]osi.Part(nil), parts...)
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	err = os.MkdirAll(filepath.Dir(t.metaPath(path)), os.ModePerm)
+	if err != nil {
+		return toError(err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(t.metaPath(path)), "put-*")
+	if err != nil {
+		return toError(err)
+	}
+	defer os.Remove(temp.Name())
+
+	hash := md5.New()
+	for i, part := range parts {
+		if i > 0 && part.PartNumber == parts[i-1].PartNumber {
+			_ = temp.Close()
+			return fmt.Errorf("part %d: listed more than once", part.PartNumber)
+		}
+		etag := osi.TrimETag(part.ETag)
+		sum, err := hex.DecodeString(etag)
+		if err != nil {
+			_ = temp.Close()
+			return fmt.Errorf("part %d: no part with etag %s", part.PartNumber, part.ETag)
+		}
+		err = appendPart(temp, filepath.Join(dir, fmt.Sprintf("%05d-%s", part.PartNumber, etag)))
+		if err != nil {
+			_ = temp.Close()
+			if os.IsNotExist(err) {
+				return fmt.Errorf("part %d: no part with etag %s", part.PartNumber, part.ETag)
+			}
+			return toError(err)
+		}
+		hash.Write(sum)
+	}
+	err = temp.Close()
+	if err != nil {
+		return toError(err)
+	}
+	err = os.Chmod(temp.Name(), fileMode(u.ACL))
+	if err != nil {
+		return toError(err)
+	}
+
+	options := osi.NewPutOptions(path, opts...)
+	status, err := t.versioning()
+	if err != nil {
+		return toError(err)
+	}
+	if !options.Conditions.IsZero() || status != osi.VersioningOff {
+		unlock, err := t.lock(path)
+		if err != nil {
+			return toError(err)
+		}
+		defer unlock()
+	}
+	if !options.Conditions.IsZero() {
+		err = t.checkConditions(ctx, path, "", http.MethodPut, options.Conditions)
+		if err != nil {
+			return err
+		}
+	}
+
+	meta := u.Meta
+	meta.ETag = fmt.Sprintf("%s-%d", hex.EncodeToString(hash.Sum(nil)), len(parts))
+	err = t.commit(path, temp.Name(), meta, status)
+	if err != nil {
+		return toError(err)
+	}
+	return toError(os.RemoveAll(dir))
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	if err := t.check(path); err != nil {
+		return err
+	}
+	dir, _, err := t.readUpload(path, uploadID)
+	if err != nil {
+		return toError(err)
+	}
+	return toError(os.RemoveAll(dir))
+}
+
+func appendPart(dst io.Writer, name string) error {
+	part, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+	_, err = io.Copy(dst, part)
+	return err
+}
@@ -0,0 +1,170 @@
+package local
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/burybell/osi"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	errEntityTooSmall = errors.New("EntityTooSmall")
+	errEntityTooLarge = errors.New("EntityTooLarge")
+)
+
+// SignPostPolicy signs the policy for PostHandler, which posts the ACL in the
+// acl field.
+func (t *bucket) SignPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	form, err := t.signPostPolicy(ctx, policy, expiredInDur)
+	osi.LogSignPostPolicy(ctx, t.config.Logger, Name, t.bucket, policy, expiredInDur, form, err)
+	return form, err
+}
+
+func (t *bucket) signPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	fields := policy.FormFields("acl")
+	document, err := policy.Document(t.bucket, time.Now().Add(expiredInDur), fields)
+	if err != nil {
+		return nil, err
+	}
+	fields["policy"] = base64.StdEncoding.EncodeToString(document)
+	fields["signature"] = SignPolicy(fields["policy"], t.config.HttpSecret)
+	return &osi.PostForm{URL: fmt.Sprintf("%s/%s", t.config.HttpAddr, t.bucket), Fields: fields}, nil
+}
+
+// SignPolicy signs a base64 encoded policy document.
+func SignPolicy(policy string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(policy))
+	return fmt.Sprintf("%x", mac.Sum(nil))
+}
+
+type postCondition struct {
+	op    string
+	field string
+	value string
+}
+
+// postPolicy is a decoded policy document.
+type postPolicy struct {
+	expiration       time.Time
+	conditions       []postCondition
+	minContentLength int64
+	maxContentLength int64
+}
+
+func parsePostPolicy(encoded string) (*postPolicy, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", osi.InvalidPolicy, err)
+	}
+	var document struct {
+		Expiration time.Time         `json:"expiration"`
+		Conditions []json.RawMessage `json:"conditions"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %v", osi.InvalidPolicy, err)
+	}
+
+	policy := &postPolicy{expiration: document.Expiration, maxContentLength: -1}
+	for _, raw := range document.Conditions {
+		var exact map[string]string
+		if json.Unmarshal(raw, &exact) == nil {
+			for field, value := range exact {
+				policy.conditions = append(policy.conditions, postCondition{op: "eq", field: strings.ToLower(field), value: value})
+			}
+			continue
+		}
+		var condition []interface{}
+		if err := json.Unmarshal(raw, &condition); err != nil || len(condition) != 3 {
+			return nil, fmt.Errorf("%w: invalid condition %s", osi.InvalidPolicy, raw)
+		}
+		op, _ := condition[0].(string)
+		switch op = strings.ToLower(op); op {
+		case "eq", "starts-with":
+			field, ok := condition[1].(string)
+			value, ok2 := condition[2].(string)
+			if !ok || !ok2 || !strings.HasPrefix(field, "$") {
+				return nil, fmt.Errorf("%w: invalid condition %s", osi.InvalidPolicy, raw)
+			}
+			policy.conditions = append(policy.conditions, postCondition{op: op, field: strings.ToLower(field[1:]), value: value})
+		case "content-length-range":
+			min, ok := condition[1].(float64)
+			max, ok2 := condition[2].(float64)
+			if !ok || !ok2 {
+				return nil, fmt.Errorf("%w: invalid condition %s", osi.InvalidPolicy, raw)
+			}
+			policy.minContentLength, policy.maxContentLength = int64(min), int64(max)
+		default:
+			return nil, fmt.Errorf("%w: invalid condition %s", osi.InvalidPolicy, raw)
+		}
+	}
+	return policy, nil
+}
+
+// check verifies the form fields, keyed by their lower case names, of an
+// upload to bkt. Like S3, it requires every field but the policy and the
+// signature to be covered by a condition.
+func (t *postPolicy) check(bkt string, fields map[string]string) error {
+	if time.Now().After(t.expiration) {
+		return fmt.Errorf("%w: policy expired", osi.AccessDenied)
+	}
+	var covered = make(map[string]bool)
+	for _, condition := range t.conditions {
+		value, ok := fields[condition.field]
+		if condition.field == "bucket" {
+			value, ok = bkt, true
+		}
+		if !ok {
+			return fmt.Errorf("%w: missing field %s", osi.AccessDenied, condition.field)
+		}
+		matched := value == condition.value
+		if condition.op == "starts-with" {
+			matched = strings.HasPrefix(value, condition.value)
+		}
+		if !matched {
+			return fmt.Errorf("%w: field %s does not match the policy", osi.AccessDenied, condition.field)
+		}
+		covered[condition.field] = true
+	}
+	for field := range fields {
+		if field != "policy" && field != "signature" && !covered[field] {
+			return fmt.Errorf("%w: field %s is not covered by the policy", osi.AccessDenied, field)
+		}
+	}
+	return nil
+}
+
+// body limits the object read from reader to the content length range of the
+// policy.
+func (t *postPolicy) body(reader io.Reader) io.Reader {
+	return &lengthReader{reader: reader, min: t.minContentLength, max: t.maxContentLength}
+}
+
+type lengthReader struct {
+	reader io.Reader
+	n      int64
+	min    int64
+	max    int64
+}
+
+func (t *lengthReader) Read(p []byte) (int, error) {
+	n, err := t.reader.Read(p)
+	t.n += int64(n)
+	if t.max >= 0 && t.n > t.max {
+		return n, errEntityTooLarge
+	}
+	if err == io.EOF && t.n < t.min {
+		return n, errEntityTooSmall
+	}
+	return n, err
+}
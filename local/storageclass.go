@@ -0,0 +1,77 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"github.com/burybell/osi"
+	"time"
+)
+
+// Storage classes are emulated: the class is recorded in the sidecar
+// metadata, archived objects refuse reads until restored, and restores
+// complete at once.
+
+// storageClassHeader carries the storage class over HTTP.
+const storageClassHeader = "x-osi-storage-class"
+
+func (t *objectMeta) storageClass() string {
+	if t.StorageClass == "" {
+		return osi.StorageClassStandard
+	}
+	return t.StorageClass
+}
+
+func (t *objectMeta) restored() bool {
+	return time.Now().Before(t.RestoreExpiry)
+}
+
+func (t *objectMeta) checkRestored() error {
+	if osi.IsArchived(t.storageClass()) && !t.restored() {
+		return fmt.Errorf("%w: object is in storage class %s", osi.NotRestored, t.storageClass())
+	}
+	return nil
+}
+
+// checkRead checks that the object at path, or its version versionID when
+// set, can be read with the customer key key.
+func (t *bucket) checkRead(path string, versionID string, key []byte) error {
+	meta, err := t.versionMeta(path, versionID)
+	if err != nil {
+		return toError(err)
+	}
+	if err := meta.checkCustomerKey(key); err != nil {
+		return err
+	}
+	return meta.checkRestored()
+}
+
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	if err := t.check(path); err != nil {
+		return err
+	}
+	return t.updateMeta(path, func(meta *objectMeta) error {
+		if err := meta.checkRestored(); err != nil {
+			return err
+		}
+		meta.StorageClass, meta.RestoreExpiry = class, time.Time{}
+		return nil
+	})
+}
+
+// RestoreObject extends the expiry of restored objects, as the providers do,
+// rather than failing with RestoreInProgress.
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	if err := t.check(path); err != nil {
+		return err
+	}
+	if err := osi.ValidateRestore(days, tier); err != nil {
+		return err
+	}
+	return t.updateMeta(path, func(meta *objectMeta) error {
+		if !osi.IsArchived(meta.storageClass()) {
+			return fmt.Errorf("%w: object is in storage class %s", osi.InvalidRestore, meta.storageClass())
+		}
+		meta.RestoreExpiry = time.Now().Add(time.Duration(days) * 24 * time.Hour)
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package local
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"os"
+)
+
+// GetObjectTags reads the tags kept in the sidecar metadata of the object,
+// which follow it through copies and into its prior versions.
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	if err := t.check(path); err != nil {
+		return nil, err
+	}
+	if err := t.checkExists(path); err != nil {
+		return nil, err
+	}
+	meta, err := t.readMeta(path)
+	if err != nil {
+		return nil, toError(err)
+	}
+	var tags = make(map[string]string, len(meta.Tags))
+	for key, value := range meta.Tags {
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tags map[string]string) error {
+	if err := t.check(path); err != nil {
+		return err
+	}
+	if err := osi.ValidateTags(tags); err != nil {
+		return err
+	}
+	return t.updateTags(path, tags)
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	if err := t.check(path); err != nil {
+		return err
+	}
+	return t.updateTags(path, nil)
+}
+
+func (t *bucket) updateTags(path string, tags map[string]string) error {
+	return t.updateMeta(path, func(meta *objectMeta) error {
+		meta.Tags = tags
+		return nil
+	})
+}
+
+func (t *bucket) checkExists(path string) error {
+	stat, err := os.Stat(t.fullPath(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return osi.ObjectNotFound
+		}
+		return toError(err)
+	}
+	if stat.IsDir() {
+		return osi.ObjectNotFound
+	}
+	return nil
+}
@@ -0,0 +1,521 @@
+package local
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/burybell/osi"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// versionsDir keeps the prior versions of each object of a versioned bucket,
+// below the metaDir next to it: one directory per object name holding a data
+// file and a json sidecar per version. Delete markers have the sidecar only.
+const versionsDir = "versions"
+
+// versioningFile holds the versioning status, below the metaDir of the bucket root.
+const versioningFile = "versioning"
+
+type version struct {
+	id   string
+	meta *objectMeta
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	if err := t.check(); err != nil {
+		return osi.VersioningOff, err
+	}
+	status, err := t.versioning()
+	return status, toError(err)
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	if status != osi.VersioningEnabled && status != osi.VersioningSuspended {
+		return fmt.Errorf("invalid versioning status %q", status)
+	}
+	name := filepath.Join(t.config.BasePath, t.bucket, metaDir, versioningFile)
+	err := os.MkdirAll(filepath.Dir(name), os.ModePerm)
+	if err != nil {
+		return toError(err)
+	}
+	return toError(os.WriteFile(name, []byte(status), 0644))
+}
+
+func (t *bucket) versioning() (osi.VersioningStatus, error) {
+	bs, err := os.ReadFile(filepath.Join(t.config.BasePath, t.bucket, metaDir, versioningFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return osi.VersioningOff, nil
+		}
+		return osi.VersioningOff, err
+	}
+	return osi.VersioningStatus(strings.TrimSpace(string(bs))), nil
+}
+
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	if err := checkPrefix(prefix); err != nil {
+		return nil, err
+	}
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	keyMarker, versionIDMarker, err := osi.ParseVersionToken(options.ContinuationToken)
+	if err != nil {
+		return nil, err
+	}
+	if keyMarker == "" {
+		keyMarker = options.StartAfter
+	}
+	versions, err := t.walkVersions(prefix)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	// resume after the marker version, or after the whole marker key when
+	// that version is gone
+	start := sort.Search(len(versions), func(i int) bool {
+		return versions[i].ObjectPath() > keyMarker
+	})
+	for i := start - 1; i >= 0 && versions[i].ObjectPath() == keyMarker; i-- {
+		if versions[i].VersionID == versionIDMarker {
+			start = i + 1
+			break
+		}
+	}
+
+	var page = &osi.VersionPage{Versions: make([]*osi.ObjectVersion, 0)}
+	var count = 0
+	for _, version := range versions[start:] {
+		key := version.ObjectPath()
+		entry, isPrefix := key, false
+		if options.Delimiter != "" {
+			if i := strings.Index(key[len(prefix):], options.Delimiter); i >= 0 {
+				entry, isPrefix = key[:len(prefix)+i+len(options.Delimiter)], true
+			}
+		}
+		if isPrefix && entry <= keyMarker {
+			continue
+		}
+		if count == options.PageSize {
+			page.IsTruncated = true
+			break
+		}
+		if isPrefix {
+			page.CommonPrefixes = append(page.CommonPrefixes, entry)
+			keyMarker, versionIDMarker = entry, ""
+		} else {
+			page.Versions = append(page.Versions, version)
+			keyMarker, versionIDMarker = key, version.VersionID
+		}
+		count++
+	}
+	if page.IsTruncated {
+		page.NextContinuationToken = osi.VersionToken(keyMarker, versionIDMarker)
+	}
+	return page, nil
+}
+
+// walkVersions returns every version of the objects whose key starts with
+// prefix, ordered by key and, for each key, from the latest version back.
+func (t *bucket) walkVersions(prefix string) ([]*osi.ObjectVersion, error) {
+	files, err := t.listFiles(prefix)
+	if err != nil {
+		return nil, err
+	}
+	var current = make(map[string]os.FileInfo, len(files))
+	var keys = make([]string, 0, len(files))
+	for _, file := range files {
+		current[file.key] = file.info
+		keys = append(keys, file.key)
+	}
+	history, err := t.walkHistory(prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range history {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var versions = make([]*osi.ObjectVersion, 0, len(keys))
+	for _, key := range keys {
+		if stat, ok := current[key]; ok {
+			info, err := t.objectInfo(key, stat)
+			if err != nil {
+				return nil, err
+			}
+			if info.VersionID == "" {
+				info.VersionID = osi.NullVersionID
+			}
+			versions = append(versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: true})
+		}
+		prior, err := t.readVersions(key)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range prior {
+			info, err := t.versionInfo(key, v)
+			if err != nil {
+				return nil, err
+			}
+			_, hasCurrent := current[key]
+			versions = append(versions, &osi.ObjectVersion{
+				ObjectInfo:     info,
+				IsLatest:       i == 0 && !hasCurrent,
+				IsDeleteMarker: v.meta.DeleteMarker,
+			})
+		}
+	}
+	return versions, nil
+}
+
+// walkHistory returns the keys below prefix that have prior versions.
+func (t *bucket) walkHistory(prefix string) ([]string, error) {
+	root := filepath.Join(t.config.BasePath, t.bucket)
+	walkRoot := root
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		walkRoot = filepath.Join(root, filepath.FromSlash(prefix[:i]))
+	}
+
+	var keys = make([]string, 0)
+	err := filepath.Walk(walkRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() || info.Name() != metaDir {
+			return nil
+		}
+		entries, err := os.ReadDir(filepath.Join(path, versionsDir))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			key := filepath.ToSlash(filepath.Join(rel, entry.Name()))
+			if entry.IsDir() && strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (t *bucket) versionsPath(path string) string {
+	dir, name := filepath.Split(t.fullPath(path))
+	return filepath.Join(dir, metaDir, versionsDir, name)
+}
+
+// readVersions returns the prior versions of path, latest first.
+func (t *bucket) readVersions(path string) ([]*version, error) {
+	dir := t.versionsPath(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var versions = make([]*version, 0, len(entries)/2)
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		bs, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		meta := &objectMeta{}
+		err = json.Unmarshal(bs, meta)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, &version{id: strings.TrimSuffix(entry.Name(), ".json"), meta: meta})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if !versions[i].meta.LastModified.Equal(versions[j].meta.LastModified) {
+			return versions[i].meta.LastModified.After(versions[j].meta.LastModified)
+		}
+		return versions[i].id > versions[j].id
+	})
+	return versions, nil
+}
+
+// versionMeta returns the metadata of the object at path, or of its version
+// versionID when set. Missing versions are left to the caller to report.
+func (t *bucket) versionMeta(path string, versionID string) (*objectMeta, error) {
+	meta, err := t.readMeta(path)
+	if err != nil {
+		return nil, err
+	}
+	if versionID != "" && meta.VersionID != versionID && !(meta.VersionID == "" && versionID == osi.NullVersionID) {
+		versions, err := t.readVersions(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			if v.id == versionID {
+				meta = v.meta
+			}
+		}
+	}
+	return meta, nil
+}
+
+func (t *bucket) versionInfo(path string, v *version) (*osi.ObjectInfo, error) {
+	if v.meta.DeleteMarker {
+		info := osi.NewObjectInfo(t.bucket, path)
+		info.VersionID = v.id
+		info.LastModified = v.meta.LastModified
+		return info, nil
+	}
+	stat, err := os.Stat(filepath.Join(t.versionsPath(path), v.id))
+	if err != nil {
+		return nil, err
+	}
+	info, err := t.newObjectInfo(path, stat, v.meta)
+	if err != nil {
+		return nil, err
+	}
+	info.VersionID = v.id
+	info.LastModified = v.meta.LastModified
+	return info, nil
+}
+
+// statVersion returns the name of the file holding version versionID of path
+// and its info. Delete markers are reported as ObjectNotFound.
+func (t *bucket) statVersion(path string, versionID string) (string, *osi.ObjectInfo, error) {
+	stat, err := os.Stat(t.fullPath(path))
+	if err == nil && !stat.IsDir() {
+		info, err := t.objectInfo(path, stat)
+		if err != nil {
+			return "", nil, toError(err)
+		}
+		if info.VersionID == versionID || (info.VersionID == "" && versionID == osi.NullVersionID) {
+			return t.fullPath(path), info, nil
+		}
+	} else if err != nil && !os.IsNotExist(err) {
+		return "", nil, toError(err)
+	}
+
+	versions, err := t.readVersions(path)
+	if err != nil {
+		return "", nil, toError(err)
+	}
+	for _, v := range versions {
+		if v.id != versionID {
+			continue
+		}
+		if v.meta.DeleteMarker {
+			return "", nil, osi.ObjectNotFound
+		}
+		info, err := t.versionInfo(path, v)
+		if err != nil {
+			return "", nil, toError(err)
+		}
+		return filepath.Join(t.versionsPath(path), v.id), info, nil
+	}
+	return "", nil, osi.ObjectNotFound
+}
+
+// commit moves the file temp into place as the current version of path. In a
+// versioned bucket the version it replaces is kept in the history; while
+// versioning is suspended the new version replaces the null version instead.
+// The caller holds the lock on path unless versioning is off.
+func (t *bucket) commit(path string, temp string, meta *objectMeta, status osi.VersioningStatus) error {
+	switch status {
+	case osi.VersioningEnabled:
+		meta.VersionID = newVersionID()
+	case osi.VersioningSuspended:
+		meta.VersionID = osi.NullVersionID
+	default:
+		meta.VersionID = ""
+	}
+	if status != osi.VersioningOff {
+		err := t.archive(path)
+		if err != nil {
+			return err
+		}
+	}
+	if status == osi.VersioningSuspended {
+		err := t.removeVersion(path, osi.NullVersionID)
+		if err != nil {
+			return err
+		}
+	}
+	err := t.writeMeta(path, meta)
+	if err != nil {
+		return err
+	}
+	return os.Rename(temp, t.fullPath(path))
+}
+
+// archive moves the current version of path, if any, into its history.
+func (t *bucket) archive(path string) error {
+	stat, err := os.Stat(t.fullPath(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if stat.IsDir() {
+		return nil
+	}
+	meta, err := t.readMeta(path)
+	if err != nil {
+		return err
+	}
+	if meta.VersionID == "" {
+		meta.VersionID = osi.NullVersionID
+	}
+	if meta.ETag == "" {
+		meta.ETag = fmt.Sprintf("%x-%x", stat.ModTime().UnixNano(), stat.Size())
+	}
+	meta.LastModified = stat.ModTime()
+	err = t.writeVersion(path, meta)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(t.fullPath(path), filepath.Join(t.versionsPath(path), meta.VersionID))
+	if err != nil {
+		return err
+	}
+	return t.removeMeta(path)
+}
+
+// deleteMarker hides path behind a new delete marker.
+func (t *bucket) deleteMarker(path string, status osi.VersioningStatus) error {
+	err := t.archive(path)
+	if err != nil {
+		return err
+	}
+	meta := &objectMeta{VersionID: newVersionID(), LastModified: time.Now(), DeleteMarker: true}
+	if status == osi.VersioningSuspended {
+		meta.VersionID = osi.NullVersionID
+		err = t.removeVersion(path, osi.NullVersionID)
+		if err != nil {
+			return err
+		}
+	}
+	return t.writeVersion(path, meta)
+}
+
+// deleteVersion removes version versionID of path for good. When that leaves
+// path without a current version, the latest prior version takes its place
+// unless it is a delete marker.
+func (t *bucket) deleteVersion(path string, versionID string) error {
+	stat, err := os.Stat(t.fullPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	meta, err := t.readMeta(path)
+	if err != nil {
+		return err
+	}
+	currentID := meta.VersionID
+	if currentID == "" {
+		currentID = osi.NullVersionID
+	}
+	if stat != nil && !stat.IsDir() && currentID == versionID {
+		err = os.Remove(t.fullPath(path))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		err = t.removeMeta(path)
+	} else {
+		err = t.removeVersion(path, versionID)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(t.fullPath(path))
+	if !os.IsNotExist(err) {
+		return err
+	}
+	versions, err := t.readVersions(path)
+	if err != nil || len(versions) == 0 || versions[0].meta.DeleteMarker {
+		return err
+	}
+	latest := versions[0]
+	latest.meta.LastModified = time.Time{}
+	err = t.writeMeta(path, latest.meta)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(filepath.Join(t.versionsPath(path), latest.id), t.fullPath(path))
+	if err != nil {
+		return err
+	}
+	return t.removeVersion(path, latest.id)
+}
+
+func (t *bucket) writeVersion(path string, meta *objectMeta) error {
+	dir := t.versionsPath(path)
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	bs, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, meta.VersionID+".json"), bs, 0644)
+}
+
+// removeVersion removes the version versionID from the history of path, and
+// the history itself once it is empty.
+func (t *bucket) removeVersion(path string, versionID string) error {
+	dir := t.versionsPath(path)
+	for _, name := range []string{versionID, versionID + ".json"} {
+		err := os.Remove(filepath.Join(dir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	_ = os.Remove(dir)
+	return nil
+}
+
+// newVersionID returns a version id that sorts after the ones made before it.
+func newVersionID() string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%016x%s", time.Now().UnixNano(), hex.EncodeToString(suffix))
+}
+
+// versioned reports whether t or source keeps versions.
+func (t *bucket) versioned(source *bucket) (bool, error) {
+	for _, b := range []*bucket{t, source} {
+		status, err := b.versioning()
+		if err != nil || status != osi.VersioningOff {
+			return err == nil, err
+		}
+	}
+	return false, nil
+}
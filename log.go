@@ -0,0 +1,230 @@
+package osi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (t LogLevel) String() string {
+	switch t {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	}
+	return strconv.Itoa(int(t))
+}
+
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+func Field(key string, value interface{}) LogField {
+	return LogField{Key: key, Value: value}
+}
+
+// Logger receives the events of the backends, such as the requests they send
+// and the URLs they presign. Implementations adapt it to the logging library
+// in use and drop the levels they do not want; the fields reach them
+// redacted.
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, fields ...LogField)
+}
+
+// Redacted replaces secrets in logged fields.
+const Redacted = "REDACTED"
+
+var (
+	sensitiveNames = []string{"secret", "password", "token", "signature", "authorization", "customer-key"}
+	// sensitiveParams matches the values of the query parameters that sign a
+	// URL, such as X-Amz-Signature, q-signature and Signature.
+	sensitiveParams = regexp.MustCompile(`(?i)([?&][^=&\s"]*(?:signature|token|secret)[^=&\s"]*=)[^&\s"]*`)
+)
+
+func sensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact removes the signatures and security tokens from the URLs in s.
+func Redact(s string) string {
+	return sensitiveParams.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// RedactField redacts the value of field: all of it when its key names a
+// secret, the secret headers of an http.Header and the signatures of the URLs
+// in strings and errors.
+func RedactField(field LogField) LogField {
+	if sensitive(field.Key) {
+		return LogField{Key: field.Key, Value: Redacted}
+	}
+	switch v := field.Value.(type) {
+	case string:
+		field.Value = Redact(v)
+	case error:
+		field.Value = Redact(v.Error())
+	case time.Duration, time.Time:
+	case fmt.Stringer:
+		field.Value = Redact(v.String())
+	case http.Header:
+		header := make(http.Header, len(v))
+		for name, values := range v {
+			if sensitive(name) {
+				values = []string{Redacted}
+			}
+			header[name] = values
+		}
+		field.Value = header
+	}
+	return field
+}
+
+// Log redacts fields and passes the event to logger, which may be nil.
+func Log(ctx context.Context, logger Logger, level LogLevel, msg string, fields ...LogField) {
+	if logger == nil {
+		return
+	}
+	redacted := make([]LogField, len(fields))
+	for i, field := range fields {
+		redacted[i] = RedactField(field)
+	}
+	logger.Log(ctx, level, msg, redacted...)
+}
+
+// LogSignURL logs the outcome of a SignURL call.
+func LogSignURL(ctx context.Context, logger Logger, provider string, bucket string, path string, method string, expiredInDur time.Duration, signed string, err error) {
+	fields := []LogField{Field("provider", provider), Field("bucket", bucket), Field("key", path), Field("method", method), Field("expires", expiredInDur)}
+	if err != nil {
+		Log(ctx, logger, LogWarn, "presigning failed", append(fields, Field("error", err))...)
+		return
+	}
+	Log(ctx, logger, LogDebug, "presigned url", append(fields, Field("url", signed))...)
+}
+
+// LogSignPostPolicy logs the outcome of a SignPostPolicy call. The fields of
+// the form, which carry its signature, are left out.
+func LogSignPostPolicy(ctx context.Context, logger Logger, provider string, bucket string, policy PostPolicy, expiredInDur time.Duration, form *PostForm, err error) {
+	fields := []LogField{Field("provider", provider), Field("bucket", bucket), Field("key", policy.FormKey()), Field("expires", expiredInDur)}
+	if err != nil {
+		Log(ctx, logger, LogWarn, "presigning failed", append(fields, Field("error", err))...)
+		return
+	}
+	Log(ctx, logger, LogDebug, "presigned post policy", append(fields, Field("url", form.URL))...)
+}
+
+type loggingTransport struct {
+	provider string
+	logger   Logger
+	base     http.RoundTripper
+}
+
+// NewLoggingTransport logs every request sent through base: at LogDebug when
+// answered, at LogWarn when answered with throttling or a server error, and
+// at LogError when no answer arrived. Retries of the SDKs show as repeated
+// requests. A nil base stands for http.DefaultTransport.
+func NewLoggingTransport(provider string, logger Logger, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &loggingTransport{provider: provider, logger: logger, base: base}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields := []LogField{Field("provider", t.provider), Field("method", req.Method), Field("url", req.URL.String()), Field("duration", time.Since(start))}
+	switch {
+	case err != nil:
+		Log(req.Context(), t.logger, LogError, "request failed", append(fields, Field("error", err))...)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		Log(req.Context(), t.logger, LogWarn, "request failed", append(fields, Field("status", resp.StatusCode))...)
+	default:
+		Log(req.Context(), t.logger, LogDebug, "request", append(fields, Field("status", resp.StatusCode))...)
+	}
+	return resp, err
+}
+
+type textLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level LogLevel
+}
+
+// NewTextLogger writes the events of level and above to w, one line each of
+// the time, the level, the message and the fields as key=value pairs.
+func NewTextLogger(w io.Writer, level LogLevel) Logger {
+	return &textLogger{w: w, level: level}
+}
+
+func (t *textLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	if level < t.level {
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(time.Now().Format(time.RFC3339))
+	sb.WriteString(" level=")
+	sb.WriteString(level.String())
+	sb.WriteString(" msg=")
+	sb.WriteString(logValue(msg))
+	for _, field := range fields {
+		sb.WriteByte(' ')
+		sb.WriteString(field.Key)
+		sb.WriteByte('=')
+		sb.WriteString(logValue(formatLogValue(field.Value)))
+	}
+	sb.WriteByte('\n')
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = io.WriteString(t.w, sb.String())
+}
+
+func formatLogValue(value interface{}) string {
+	if header, ok := value.(http.Header); ok {
+		names := make([]string, 0, len(header))
+		for name := range header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts := make([]string, len(names))
+		for i, name := range names {
+			parts[i] = name + ": " + strings.Join(header[name], ", ")
+		}
+		return strings.Join(parts, "; ")
+	}
+	return fmt.Sprint(value)
+}
+
+// logValue quotes s when it would not read as a single value.
+func logValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+package osi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder keeps the events logged.
+type recorder struct {
+	mu     sync.Mutex
+	events []event
+}
+
+type event struct {
+	level  osi.LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+func (t *recorder) Log(ctx context.Context, level osi.LogLevel, msg string, fields ...osi.LogField) {
+	e := event{level: level, msg: msg, fields: map[string]interface{}{}}
+	for _, field := range fields {
+		e.fields[field.Key] = field.Value
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, e)
+}
+
+func TestRedact(t *testing.T) {
+	for raw, redacted := range map[string]string{
+		"https://example.s3.amazonaws.com/a.txt?X-Amz-Credential=AKID%2F20231017&X-Amz-Security-Token=tok&X-Amz-Signature=abc": "https://example.s3.amazonaws.com/a.txt?X-Amz-Credential=AKID%2F20231017&X-Amz-Security-Token=REDACTED&X-Amz-Signature=REDACTED",
+		"https://example.cos.ap-guangzhou.myqcloud.com/a.txt?q-ak=AKID&q-signature=abc":                                        "https://example.cos.ap-guangzhou.myqcloud.com/a.txt?q-ak=AKID&q-signature=REDACTED",
+		`Get "http://localhost/example/a.txt?expires=1&signature=abc": EOF`:                                                    `Get "http://localhost/example/a.txt?expires=1&signature=REDACTED": EOF`,
+		"test/signature.txt": "test/signature.txt",
+	} {
+		assert.Equal(t, redacted, osi.Redact(raw))
+	}
+
+	assert.Equal(t, osi.Redacted, osi.RedactField(osi.Field("secret", "abc")).Value)
+	assert.Equal(t, time.Second, osi.RedactField(osi.Field("delay", time.Second)).Value)
+	assert.Equal(t, "http://localhost/a?signature=REDACTED", osi.RedactField(osi.Field("error", errors.New("http://localhost/a?signature=abc"))).Value)
+	header := osi.RedactField(osi.Field("header", http.Header{
+		"Authorization": {"AWS4-HMAC-SHA256 Credential=AKID"},
+		"X-Amz-Server-Side-Encryption-Customer-Key": {"a2V5"},
+		"Content-Type": {"text/plain"},
+	})).Value.(http.Header)
+	assert.Equal(t, osi.Redacted, header.Get("Authorization"))
+	assert.Equal(t, osi.Redacted, header.Get("X-Amz-Server-Side-Encryption-Customer-Key"))
+	assert.Equal(t, "text/plain", header.Get("Content-Type"))
+}
+
+func TestLog(t *testing.T) {
+	ctx := context.Background()
+	// a nil logger logs nothing
+	osi.Log(ctx, nil, osi.LogError, "request failed")
+
+	logger := &recorder{}
+	osi.LogSignURL(ctx, logger, "local", "example", "test/a.txt", http.MethodGet, time.Minute, "http://localhost/example/test/a.txt?expires=1&signature=abc", nil)
+	osi.LogSignURL(ctx, logger, "local", "example", "test/a.txt", "PATCH", time.Minute, "", osi.NotSupported)
+	assert.Len(t, logger.events, 2)
+	assert.Equal(t, osi.LogDebug, logger.events[0].level)
+	assert.Equal(t, "http://localhost/example/test/a.txt?expires=1&signature=REDACTED", logger.events[0].fields["url"])
+	assert.Equal(t, osi.LogWarn, logger.events[1].level)
+	assert.Equal(t, "NotSupported", logger.events[1].fields["error"])
+
+	var buf bytes.Buffer
+	text := osi.NewTextLogger(&buf, osi.LogInfo)
+	osi.Log(ctx, text, osi.LogDebug, "request")
+	osi.Log(ctx, text, osi.LogWarn, "request failed", osi.Field("method", "GET"), osi.Field("url", "http://localhost/a b?signature=abc"), osi.Field("status", 503))
+	line := buf.String()
+	assert.Equal(t, 1, strings.Count(line, "\n"))
+	assert.Contains(t, line, ` level=warn msg="request failed" method=GET url="http://localhost/a b?signature=REDACTED" status=503`)
+}
+
+func TestNewLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/busy" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	logger := &recorder{}
+	client := &http.Client{Transport: osi.NewLoggingTransport("test", logger, nil)}
+	for _, path := range []string{"/ok?signature=abc", "/busy"} {
+		resp, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	server.Close()
+	_, err := client.Get(server.URL + "/ok")
+	assert.Error(t, err)
+
+	assert.Len(t, logger.events, 3)
+	assert.Equal(t, osi.LogDebug, logger.events[0].level)
+	assert.Equal(t, server.URL+"/ok?signature=REDACTED", logger.events[0].fields["url"])
+	assert.Equal(t, http.StatusOK, logger.events[0].fields["status"])
+	assert.Equal(t, osi.LogWarn, logger.events[1].level)
+	assert.Equal(t, http.StatusServiceUnavailable, logger.events[1].fields["status"])
+	assert.Equal(t, osi.LogError, logger.events[2].level)
+	assert.NotNil(t, logger.events[2].fields["error"])
+}
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"io"
+	"time"
+)
+
+// bucket reports the calls on the objects of the bucket it wraps, their
+// listing and presigning to an Observer. Multipart uploads, versioning,
+// tagging and archiving are passed through unobserved.
+type bucket struct {
+	osi.Bucket
+	store    string
+	bucket   string
+	observer Observer
+}
+
+// NewBucket wraps bkt, the bucket bucketName of the store named store, so that
+// its calls are reported to observer.
+func NewBucket(bkt osi.Bucket, store string, bucketName string, observer Observer) osi.Bucket {
+	return &bucket{Bucket: bkt, store: store, bucket: bucketName, observer: observer}
+}
+
+// observe starts the operation name and returns a func ending it.
+func (t *bucket) observe(name string) (Operation, func(err error)) {
+	op := Operation{Store: t.store, Bucket: t.bucket, Name: name}
+	t.observer.Start(op)
+	start := time.Now()
+	return op, func(err error) {
+		t.observer.End(op, time.Since(start), err)
+	}
+}
+
+func (t *bucket) open(name string, get func() (osi.Object, error)) (osi.Object, error) {
+	op, end := t.observe(name)
+	object, err := get()
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+	return newCountingObject(object, func(n int64) {
+		t.observer.Bytes(op, Received, n)
+	}), nil
+}
+
+func (t *bucket) put(name string, reader io.Reader, put func(reader io.Reader) error) error {
+	op, end := t.observe(name)
+	counted, counter := countReader(reader)
+	err := put(counted)
+	end(err)
+	t.observer.Bytes(op, Sent, counter.count())
+	return err
+}
+
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	return t.open("GetObject", func() (osi.Object, error) {
+		return t.Bucket.GetObject(ctx, path, opts...)
+	})
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	return t.open("GetObjectRange", func() (osi.Object, error) {
+		return t.Bucket.GetObjectRange(ctx, path, rng, opts...)
+	})
+}
+
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	return t.put("PutObject", reader, func(reader io.Reader) error {
+		return t.Bucket.PutObject(ctx, path, reader, opts...)
+	})
+}
+
+func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
+	return t.put("PutObjectWithACL", reader, func(reader io.Reader) error {
+		return t.Bucket.PutObjectWithACL(ctx, path, reader, acl)
+	})
+}
+
+func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
+	_, end := t.observe("HeadObject")
+	exists, err := t.Bucket.HeadObject(ctx, path)
+	end(err)
+	return exists, err
+}
+
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	_, end := t.observe("StatObject")
+	info, err := t.Bucket.StatObject(ctx, path, opts...)
+	end(err)
+	return info, err
+}
+
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	_, end := t.observe("DeleteObject")
+	err := t.Bucket.DeleteObject(ctx, path, opts...)
+	end(err)
+	return err
+}
+
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	_, end := t.observe("CopyObject")
+	err := t.Bucket.CopyObject(ctx, src, dst, opts...)
+	end(err)
+	return err
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	_, end := t.observe("MoveObject")
+	err := t.Bucket.MoveObject(ctx, src, dst, opts...)
+	end(err)
+	return err
+}
+
+func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
+	_, end := t.observe("GetObjectSize")
+	size, err := t.Bucket.GetObjectSize(ctx, path)
+	end(err)
+	return size, err
+}
+
+func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
+	_, end := t.observe("ListObjects")
+	objects, err := t.Bucket.ListObjects(ctx, prefix)
+	end(err)
+	return objects, err
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	_, end := t.observe("ListObjectsPage")
+	page, err := t.Bucket.ListObjectsPage(ctx, prefix, opts...)
+	end(err)
+	return page, err
+}
+
+func (t *bucket) DeleteObjects(ctx context.Context, paths []string) error {
+	_, end := t.observe("DeleteObjects")
+	err := t.Bucket.DeleteObjects(ctx, paths)
+	end(err)
+	return err
+}
+
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	_, end := t.observe("SignURL")
+	url, err := t.Bucket.SignURL(ctx, path, method, expiredInDur, opts...)
+	end(err)
+	return url, err
+}
+
+func (t *bucket) SignPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	_, end := t.observe("SignPostPolicy")
+	form, err := t.Bucket.SignPostPolicy(ctx, policy, expiredInDur)
+	end(err)
+	return form, err
+}
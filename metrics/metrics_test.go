@@ -0,0 +1,207 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/local"
+	"github.com/burybell/osi/metrics"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	ctx   = context.Background()
+	store osi.ObjectStore
+)
+
+func init() {
+	dir, err := os.MkdirTemp("", "osi-metrics-")
+	if err != nil {
+		panic(err)
+	}
+	store = local.MustNewObjectStore(local.Config{BasePath: dir})
+	if err := store.CreateBucket(ctx, "example"); err != nil {
+		panic(err)
+	}
+}
+
+type call struct {
+	op        metrics.Operation
+	event     string
+	err       error
+	direction metrics.Direction
+	n         int64
+}
+
+type recorder struct {
+	mu    sync.Mutex
+	calls []call
+}
+
+func (t *recorder) Start(op metrics.Operation) {
+	t.record(call{op: op, event: "start"})
+}
+
+func (t *recorder) End(op metrics.Operation, duration time.Duration, err error) {
+	t.record(call{op: op, event: "end", err: err})
+}
+
+func (t *recorder) Bytes(op metrics.Operation, direction metrics.Direction, n int64) {
+	t.record(call{op: op, event: "bytes", direction: direction, n: n})
+}
+
+func (t *recorder) record(c call) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls = append(t.calls, c)
+}
+
+func (t *recorder) reset() []call {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	calls := t.calls
+	t.calls = nil
+	return calls
+}
+
+func op(name string) metrics.Operation {
+	return metrics.Operation{Store: local.Name, Bucket: "example", Name: name}
+}
+
+func TestBucket_Objects(t *testing.T) {
+	observer := &recorder{}
+	bucket := metrics.NewObjectStore(store, observer).Bucket("example")
+
+	assert.NoError(t, bucket.PutObject(ctx, "test/metrics.txt", strings.NewReader("some text")))
+	defer bucket.DeleteObject(ctx, "test/metrics.txt")
+	assert.Equal(t, []call{
+		{op: op("PutObject"), event: "start"},
+		{op: op("PutObject"), event: "end"},
+		{op: op("PutObject"), event: "bytes", direction: metrics.Sent, n: 9},
+	}, observer.reset())
+
+	// the bytes of a get are reported once its body is closed
+	object, err := bucket.GetObject(ctx, "test/metrics.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, []call{
+		{op: op("GetObject"), event: "start"},
+		{op: op("GetObject"), event: "end"},
+	}, observer.reset())
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "some text", string(bs))
+	assert.NoError(t, object.Close())
+	_ = object.Close()
+	assert.Equal(t, []call{
+		{op: op("GetObject"), event: "bytes", direction: metrics.Received, n: 9},
+	}, observer.reset())
+
+	_, err = bucket.StatObject(ctx, "test/missing.txt")
+	calls := observer.reset()
+	assert.Len(t, calls, 2)
+	assert.Equal(t, op("StatObject"), calls[1].op)
+	assert.ErrorIs(t, calls[1].err, osi.ObjectNotFound)
+	assert.Equal(t, "ObjectNotFound", osi.ErrorClass(calls[1].err))
+
+	_, err = bucket.ListObjectsPage(ctx, "test/")
+	assert.NoError(t, err)
+	_, err = bucket.SignURL(ctx, "test/metrics.txt", "GET", time.Minute)
+	calls = observer.reset()
+	assert.Equal(t, op("ListObjectsPage"), calls[0].op)
+	assert.Equal(t, op("SignURL"), calls[len(calls)-1].op)
+
+	// calls outside the observed interfaces are passed through
+	_, err = bucket.GetObjectTags(ctx, "test/metrics.txt")
+	assert.NoError(t, err)
+	assert.Empty(t, observer.reset())
+}
+
+// sizeBucket records the size osi.ReaderSize tells of the bodies put.
+type sizeBucket struct {
+	osi.Bucket
+	size  int64
+	known bool
+}
+
+func (t *sizeBucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	t.size, t.known = osi.ReaderSize(reader)
+	return t.Bucket.PutObject(ctx, path, reader, opts...)
+}
+
+func TestBucket_PutObjectSize(t *testing.T) {
+	observer := &recorder{}
+	sized := &sizeBucket{Bucket: store.Bucket("example")}
+	bucket := metrics.NewBucket(sized, local.Name, "example", observer)
+
+	// the body keeps the methods its size is told by
+	content := bytes.Repeat([]byte("a"), 1024)
+	for _, body := range []struct {
+		reader  io.Reader
+		sizable bool
+	}{
+		{bytes.NewReader(content), true},
+		{bytes.NewBuffer(content), true},
+		{io.NopCloser(bytes.NewReader(content)), false},
+	} {
+		assert.NoError(t, bucket.PutObject(ctx, "test/size.txt", body.reader))
+		assert.Equal(t, body.sizable, sized.known)
+		if body.sizable {
+			assert.Equal(t, int64(len(content)), sized.size)
+		}
+		calls := observer.reset()
+		assert.Equal(t, int64(len(content)), calls[len(calls)-1].n)
+	}
+	defer bucket.DeleteObject(ctx, "test/size.txt")
+
+	object, err := bucket.GetObjectRange(ctx, "test/size.txt", osi.NewRange(0, 100))
+	assert.NoError(t, err)
+	_, _ = io.Copy(io.Discard, object)
+	_ = object.Close()
+	calls := observer.reset()
+	assert.Equal(t, call{op: op("GetObjectRange"), event: "bytes", direction: metrics.Received, n: 100}, calls[len(calls)-1])
+}
+
+// rewindingBucket reads part of the bodies put and rewinds them, as a retried
+// put does.
+type rewindingBucket struct {
+	osi.Bucket
+}
+
+func (t *rewindingBucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	seeker := reader.(io.ReadSeeker)
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(io.Discard, seeker, 4); err != nil {
+		return err
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	return t.Bucket.PutObject(ctx, path, reader, opts...)
+}
+
+func TestBucket_PutObjectRewound(t *testing.T) {
+	observer := &recorder{}
+	bucket := metrics.NewBucket(&rewindingBucket{Bucket: store.Bucket("example")}, local.Name, "example", observer)
+	defer bucket.DeleteObject(ctx, "test/rewound.txt")
+
+	// the bytes read again after a rewind are counted once
+	assert.NoError(t, bucket.PutObject(ctx, "test/rewound.txt", strings.NewReader("some text")))
+	calls := observer.reset()
+	assert.Equal(t, int64(9), calls[len(calls)-1].n)
+
+	// the bytes before the start of the body are not counted
+	body := strings.NewReader("some text")
+	_, _ = body.Seek(5, io.SeekStart)
+	assert.NoError(t, bucket.PutObject(ctx, "test/rewound.txt", body))
+	calls = observer.reset()
+	assert.Equal(t, int64(4), calls[len(calls)-1].n)
+}
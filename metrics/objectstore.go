@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"github.com/burybell/osi"
+)
+
+type objectStore struct {
+	osi.ObjectStore
+	observer Observer
+}
+
+// NewObjectStore wraps store so that the calls on its buckets are reported to
+// observer, labelled with the store's Name.
+func NewObjectStore(store osi.ObjectStore, observer Observer) osi.ObjectStore {
+	return &objectStore{ObjectStore: store, observer: observer}
+}
+
+func (t *objectStore) Bucket(name string) osi.Bucket {
+	return NewBucket(t.ObjectStore.Bucket(name), t.Name(), name, t.observer)
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"time"
+)
+
+// Operation names a call on a bucket: the method called, the bucket and the
+// name of the store, as ObjectStore.Name reports it.
+type Operation struct {
+	Store  string
+	Bucket string
+	Name   string
+}
+
+type Direction string
+
+const (
+	Sent     Direction = "sent"
+	Received Direction = "received"
+)
+
+// Observer receives the measurements of instrumented buckets. Its methods
+// are called concurrently.
+type Observer interface {
+	// Start is called as op starts.
+	Start(op Operation)
+	// End is called as op returns, duration after it started, with the error
+	// it returned. Gets end when the object is returned, before its body is
+	// read.
+	End(op Operation, duration time.Duration, err error)
+	// Bytes is called with the number of body bytes op sent or received:
+	// for puts once they return, for gets once the object is closed.
+	Bytes(op Operation, direction Direction, n int64)
+}
@@ -0,0 +1,118 @@
+package prometheus
+
+import (
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+const DefaultNamespace = "osi"
+
+type Options struct {
+	Namespace string
+	// DurationBuckets are the upper bounds of the duration histogram, in
+	// seconds.
+	DurationBuckets []float64
+}
+
+type Option func(opts *Options)
+
+func WithNamespace(namespace string) Option {
+	return func(opts *Options) {
+		opts.Namespace = namespace
+	}
+}
+
+func WithDurationBuckets(buckets ...float64) Option {
+	return func(opts *Options) {
+		opts.DurationBuckets = buckets
+	}
+}
+
+func NewOptions(opts ...Option) *Options {
+	options := &Options{
+		Namespace:       DefaultNamespace,
+		DurationBuckets: prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// Collector is a metrics.Observer exporting what it observes as Prometheus
+// metrics, labelled by store, bucket and operation:
+//
+//	osi_operations_in_flight              calls started but not returned
+//	osi_operation_duration_seconds        histogram of the call durations
+//	osi_operation_errors_total            failed calls, by the error class
+//	                                      osi.ErrorClass reports
+//	osi_operation_bytes_total             body bytes, by direction
+//
+// Register it with a prometheus.Registerer and pass it to metrics.NewBucket
+// or metrics.NewObjectStore.
+type Collector struct {
+	inFlight *prometheus.GaugeVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	bytes    *prometheus.CounterVec
+}
+
+func NewCollector(opts ...Option) *Collector {
+	options := NewOptions(opts...)
+	labels := []string{"store", "bucket", "operation"}
+	return &Collector{
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: options.Namespace,
+			Name:      "operations_in_flight",
+			Help:      "Number of bucket operations started but not returned.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: options.Namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of bucket operations until they returned.",
+			Buckets:   options.DurationBuckets,
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: options.Namespace,
+			Name:      "operation_errors_total",
+			Help:      "Number of bucket operations that failed, by error class.",
+		}, append(labels, "error")),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: options.Namespace,
+			Name:      "operation_bytes_total",
+			Help:      "Number of object body bytes sent and received.",
+		}, append(labels, "direction")),
+	}
+}
+
+func (t *Collector) Start(op metrics.Operation) {
+	t.inFlight.WithLabelValues(op.Store, op.Bucket, op.Name).Inc()
+}
+
+func (t *Collector) End(op metrics.Operation, duration time.Duration, err error) {
+	t.inFlight.WithLabelValues(op.Store, op.Bucket, op.Name).Dec()
+	t.duration.WithLabelValues(op.Store, op.Bucket, op.Name).Observe(duration.Seconds())
+	if err != nil {
+		t.errors.WithLabelValues(op.Store, op.Bucket, op.Name, osi.ErrorClass(err)).Inc()
+	}
+}
+
+func (t *Collector) Bytes(op metrics.Operation, direction metrics.Direction, n int64) {
+	t.bytes.WithLabelValues(op.Store, op.Bucket, op.Name, string(direction)).Add(float64(n))
+}
+
+func (t *Collector) Describe(ch chan<- *prometheus.Desc) {
+	t.inFlight.Describe(ch)
+	t.duration.Describe(ch)
+	t.errors.Describe(ch)
+	t.bytes.Describe(ch)
+}
+
+func (t *Collector) Collect(ch chan<- prometheus.Metric) {
+	t.inFlight.Collect(ch)
+	t.duration.Collect(ch)
+	t.errors.Collect(ch)
+	t.bytes.Collect(ch)
+}
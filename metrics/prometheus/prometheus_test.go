@@ -0,0 +1,54 @@
+package prometheus_test
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/local"
+	"github.com/burybell/osi/metrics"
+	"github.com/burybell/osi/metrics/prometheus"
+	client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	ctx := context.Background()
+	dir, err := os.MkdirTemp("", "osi-prometheus-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store := local.MustNewObjectStore(local.Config{BasePath: dir})
+	assert.NoError(t, store.CreateBucket(ctx, "example"))
+
+	collector := prometheus.NewCollector()
+	registry := client.NewRegistry()
+	assert.NoError(t, registry.Register(collector))
+	bucket := metrics.NewObjectStore(store, collector).Bucket("example")
+
+	assert.NoError(t, bucket.PutObject(ctx, "test/prometheus.txt", strings.NewReader("some text")))
+	object, err := bucket.GetObject(ctx, "test/prometheus.txt")
+	assert.NoError(t, err)
+	_, _ = io.Copy(io.Discard, object)
+	_ = object.Close()
+	_, err = bucket.StatObject(ctx, "test/missing.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP osi_operation_bytes_total Number of object body bytes sent and received.
+# TYPE osi_operation_bytes_total counter
+osi_operation_bytes_total{bucket="example",direction="received",operation="GetObject",store="local"} 9
+osi_operation_bytes_total{bucket="example",direction="sent",operation="PutObject",store="local"} 9
+# HELP osi_operation_errors_total Number of bucket operations that failed, by error class.
+# TYPE osi_operation_errors_total counter
+osi_operation_errors_total{bucket="example",error="ObjectNotFound",operation="StatObject",store="local"} 1
+# HELP osi_operations_in_flight Number of bucket operations started but not returned.
+# TYPE osi_operations_in_flight gauge
+osi_operations_in_flight{bucket="example",operation="GetObject",store="local"} 0
+osi_operations_in_flight{bucket="example",operation="PutObject",store="local"} 0
+osi_operations_in_flight{bucket="example",operation="StatObject",store="local"} 0
+`), "osi_operation_bytes_total", "osi_operation_errors_total", "osi_operations_in_flight"))
+	assert.Equal(t, 3, testutil.CollectAndCount(collector, "osi_operation_duration_seconds"))
+}
@@ -0,0 +1,31 @@
+package minio
+
+import (
+	"github.com/burybell/osi"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+// serverSide returns the encryption of a put, which must have passed
+// Encryption.Validate.
+func serverSide(encryption osi.Encryption) encrypt.ServerSide {
+	var sse encrypt.ServerSide
+	switch encryption.Mode {
+	case osi.EncryptionSSES3:
+		sse = encrypt.NewSSE()
+	case osi.EncryptionSSEKMS:
+		sse, _ = encrypt.NewSSEKMS(encryption.KMSKeyID, nil)
+	case osi.EncryptionSSEC:
+		sse, _ = encrypt.NewSSEC(encryption.CustomerKey)
+	}
+	return sse
+}
+
+func customerKey(key []byte) (encrypt.ServerSide, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	if err := osi.ValidateCustomerKey(key); err != nil {
+		return nil, err
+	}
+	return encrypt.NewSSEC(key)
+}
@@ -0,0 +1,19 @@
+package minio
+
+import (
+	"errors"
+	"github.com/burybell/osi"
+	"github.com/minio/minio-go/v7"
+)
+
+// toError maps a minio-go error onto the osi error taxonomy.
+func toError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var minioErr minio.ErrorResponse
+	if errors.As(err, &minioErr) {
+		return osi.NewError(Name, minioErr.Code, minioErr.StatusCode, minioErr.RequestID, minioErr.Message, err)
+	}
+	return osi.TransportError(Name, err)
+}
@@ -2,13 +2,16 @@ package minio
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
+	"fmt"
 	"github.com/burybell/osi"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 	"io"
-	"mime"
-	"path/filepath"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -23,23 +26,35 @@ type Config struct {
 	Secret   string `yaml:"secret" mapstructure:"secret" json:"secret"`
 	Endpoint string `yaml:"endpoint" mapstructure:"endpoint" json:"endpoint"`
 	UseSSL   bool   `yaml:"use_ssl" mapstructure:"use_ssl" json:"use_ssl"`
+	// Logger receives the requests sent and the URLs presigned.
+	Logger osi.Logger `yaml:"-" mapstructure:"-" json:"-"`
 }
 
 type ObjectStore struct {
-	config Config
-	client *minio.Client
+	config     Config
+	client     *minio.Client
+	httpClient *http.Client
 }
 
 func NewObjectStore(config Config) (osi.ObjectStore, error) {
+	var transport http.RoundTripper
+	if config.Logger != nil {
+		base, err := minio.DefaultTransport(config.UseSSL)
+		if err != nil {
+			return nil, err
+		}
+		transport = osi.NewLoggingTransport(Name, config.Logger, base)
+	}
 	client, err := minio.New(config.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(config.KeyID, config.Secret, ""),
-		Secure: config.UseSSL,
-		Region: config.Region,
+		Creds:     credentials.NewStaticV4(config.KeyID, config.Secret, ""),
+		Secure:    config.UseSSL,
+		Region:    config.Region,
+		Transport: transport,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &ObjectStore{config: config, client: client}, nil
+	return &ObjectStore{config: config, client: client, httpClient: &http.Client{Transport: transport}}, nil
 }
 
 func MustNewObjectStore(config Config) osi.ObjectStore {
@@ -56,10 +71,53 @@ func (t *ObjectStore) Name() string {
 
 func (t *ObjectStore) Bucket(name string) osi.Bucket {
 	return &bucket{
-		config: t.config,
-		client: t.client,
-		bucket: name,
+		config:     t.config,
+		client:     t.client,
+		httpClient: t.httpClient,
+		bucket:     name,
+	}
+}
+
+// CreateBucket grants public access through a bucket policy, since MinIO
+// ignores canned ACLs on buckets.
+func (t *ObjectStore) CreateBucket(ctx context.Context, name string, opts ...osi.BucketOption) error {
+	options := osi.NewBucketOptions(t.config.Region, opts...)
+	err := t.client.MakeBucket(ctx, name, minio.MakeBucketOptions{Region: options.Region})
+	if err != nil {
+		return toError(err)
+	}
+	var actions string
+	switch options.ACL {
+	case aclEnum{}.PublicRead():
+		actions = `"s3:GetObject"`
+	case aclEnum{}.PublicReadWrite():
+		actions = `"s3:GetObject","s3:PutObject","s3:DeleteObject"`
+	default:
+		return nil
+	}
+	policy := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":[%s],"Resource":["arn:aws:s3:::%s/*"]}]}`, actions, name)
+	return toError(t.client.SetBucketPolicy(ctx, name, policy))
+}
+
+func (t *ObjectStore) DeleteBucket(ctx context.Context, name string) error {
+	return toError(t.client.RemoveBucket(ctx, name))
+}
+
+func (t *ObjectStore) ListBuckets(ctx context.Context) ([]osi.BucketInfo, error) {
+	infos, err := t.client.ListBuckets(ctx)
+	if err != nil {
+		return nil, toError(err)
 	}
+	var buckets = make([]osi.BucketInfo, 0, len(infos))
+	for _, info := range infos {
+		buckets = append(buckets, osi.BucketInfo{Name: info.Name, CreationDate: info.CreationDate})
+	}
+	return buckets, nil
+}
+
+func (t *ObjectStore) BucketExists(ctx context.Context, name string) (bool, error) {
+	exist, err := t.client.BucketExists(ctx, name)
+	return exist, toError(err)
 }
 
 func (t *ObjectStore) ACLEnum() osi.ACLEnum {
@@ -67,24 +125,63 @@ func (t *ObjectStore) ACLEnum() osi.ACLEnum {
 }
 
 type bucket struct {
-	config Config
-	client *minio.Client
-	bucket string
+	config     Config
+	client     *minio.Client
+	httpClient *http.Client
+	bucket     string
 }
 
-func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error) {
-	acl, err := t.client.GetObjectACL(ctx, t.bucket, path)
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	getOpts, err := getObjectOptions(osi.NewGetOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+	return t.getObject(ctx, path, getOpts)
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	if err := rng.Validate(); err != nil {
+		return nil, err
+	}
+	getOpts, err := getObjectOptions(osi.NewGetOptions(opts...))
 	if err != nil {
-		var minioErr minio.ErrorResponse
-		if errors.As(err, &minioErr) && minioErr.Code == "NoSuchKey" {
-			return nil, osi.ObjectNotFound
-		}
 		return nil, err
 	}
+	getOpts.Set("Range", rng.HeaderValue())
+	return t.getObject(ctx, path, getOpts)
+}
+
+func getObjectOptions(options *osi.GetOptions) (minio.GetObjectOptions, error) {
+	sse, err := customerKey(options.CustomerKey)
+	if err != nil {
+		return minio.GetObjectOptions{}, err
+	}
+	conditions := options.Conditions
+	getOpts := minio.GetObjectOptions{VersionID: options.VersionID, ServerSideEncryption: sse}
+	if conditions.IfMatch != "" {
+		_ = getOpts.SetMatchETag(osi.TrimETag(conditions.IfMatch))
+	}
+	if conditions.IfNoneMatch != "" {
+		_ = getOpts.SetMatchETagExcept(osi.TrimETag(conditions.IfNoneMatch))
+	}
+	if !conditions.IfModifiedSince.IsZero() {
+		_ = getOpts.SetModified(conditions.IfModifiedSince)
+	}
+	if !conditions.IfUnmodifiedSince.IsZero() {
+		_ = getOpts.SetUnmodified(conditions.IfUnmodifiedSince)
+	}
+	return getOpts, nil
+}
+
+func (t *bucket) getObject(ctx context.Context, path string, opts minio.GetObjectOptions) (osi.Object, error) {
+	permissions, err := t.objectPermissions(ctx, path, opts.VersionID)
+	if err != nil {
+		return nil, toError(err)
+	}
 
 	var publicACL = make(map[string]int)
-	for _, grant := range acl.Grant {
-		publicACL[grant.Permission] = 1
+	for _, permission := range permissions {
+		publicACL[permission] = 1
 	}
 
 	var ACL = ""
@@ -96,66 +193,217 @@ func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error)
 		ACL = "private"
 	}
 
-	object, err := t.client.GetObject(ctx, t.bucket, path, minio.GetObjectOptions{})
+	object, err := t.client.GetObject(ctx, t.bucket, path, opts)
 	if err != nil {
-		return nil, err
+		return nil, toError(err)
+	}
+	// the SDK sends the request on first use; Stat sends it now so that a
+	// missing object or a failed condition surfaces here
+	if _, err := object.Stat(); err != nil {
+		_ = object.Close()
+		return nil, toError(err)
 	}
 	return osi.NewObject(t.bucket, path, ACL, object), nil
 }
 
-func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader) error {
-	return t.PutObjectWithACL(ctx, path, reader, aclEnum{}.Default())
+type accessControlPolicy struct {
+	AccessControlList struct {
+		Grant []struct {
+			Permission string
+		}
+	}
+}
+
+// objectPermissions returns the permissions granted on the object. The ACL
+// call of the SDK cannot name a version, so the ACL of a version is read
+// through a presigned request.
+func (t *bucket) objectPermissions(ctx context.Context, path string, versionID string) ([]string, error) {
+	var permissions []string
+	if versionID == "" {
+		acl, err := t.client.GetObjectACL(ctx, t.bucket, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range acl.Grant {
+			permissions = append(permissions, grant.Permission)
+		}
+		return permissions, nil
+	}
+
+	query := url.Values{"acl": {""}, "versionId": {versionID}}
+	signed, err := t.client.Presign(ctx, http.MethodGet, t.bucket, path, time.Minute, query)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errResp := minio.ErrorResponse{StatusCode: resp.StatusCode, BucketName: t.bucket, Key: path, RequestID: resp.Header.Get("X-Amz-Request-Id")}
+		_ = xml.NewDecoder(resp.Body).Decode(&errResp)
+		return nil, errResp
+	}
+	var policy accessControlPolicy
+	if err := xml.NewDecoder(resp.Body).Decode(&policy); err != nil {
+		return nil, err
+	}
+	for _, grant := range policy.AccessControlList.Grant {
+		permissions = append(permissions, grant.Permission)
+	}
+	return permissions, nil
+}
+
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	size, ok := osi.ReaderSize(reader)
+	if !ok {
+		size = -1
+	}
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return err
+	}
+	if err := options.Encryption.Validate(); err != nil {
+		return err
+	}
+	if err := checkStorageClass(options.StorageClass); err != nil {
+		return err
+	}
+	putOpts := putObjectOptions(options)
+	if err := setWriteConditions(&putOpts, options.Conditions); err != nil {
+		return err
+	}
+	putOpts.PartSize = uint64(options.PartSize)
+	putOpts.NumThreads = uint(options.Concurrency)
+	putOpts.ConcurrentStreamParts = true
+	_, err := t.client.PutObject(ctx, t.bucket, path, reader, size, putOpts)
+	return toError(err)
 }
 
 func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
-	opts := minio.PutObjectOptions{}
-	opts.Header().Set("x-amz-acl", acl)
-	opts.ContentType = mime.TypeByExtension(filepath.Ext(path))
-	_, err := t.client.PutObject(ctx, t.bucket, path, reader, -1, opts)
-	return err
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
 }
 
 func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
 	_, err := t.client.StatObject(ctx, t.bucket, path, minio.StatObjectOptions{})
 	if err != nil {
+		err = toError(err)
+		if errors.Is(err, osi.ObjectNotFound) {
+			return false, nil
+		}
 		return false, err
 	}
 	return true, nil
 }
 
-func (t *bucket) DeleteObject(ctx context.Context, path string) error {
-	return t.client.RemoveObject(ctx, t.bucket, path, minio.RemoveObjectOptions{})
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	options := osi.NewDeleteOptions(opts...)
+	if !options.Conditions.IsZero() {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional delete"}
+	}
+	return toError(t.client.RemoveObject(ctx, t.bucket, path, minio.RemoveObjectOptions{VersionID: options.VersionID}))
+}
+
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	_, err := t.client.ComposeObject(ctx,
+		minio.CopyDestOptions{Bucket: t.bucket, Object: dst},
+		minio.CopySrcOptions{Bucket: options.SourceBucket, Object: src},
+	)
+	return toError(err)
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	err := t.CopyObject(ctx, src, dst, opts...)
+	if err != nil {
+		return err
+	}
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	return toError(t.client.RemoveObject(ctx, options.SourceBucket, src, minio.RemoveObjectOptions{}))
 }
 
 func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
-	var oms = make([]osi.ObjectMeta, 0)
-	objects := t.client.ListObjects(ctx, t.bucket, minio.ListObjectsOptions{
-		Prefix:  prefix,
-		MaxKeys: 200,
-	})
+	return osi.ListAll(ctx, t, prefix)
+}
 
-	for object := range objects {
-		if object.Err != nil {
-			return nil, object.Err
-		}
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	core := minio.Core{Client: t.client}
+	objects, err := core.ListObjectsV2(t.bucket, prefix, options.StartAfter, options.ContinuationToken, options.Delimiter, options.PageSize)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.ObjectPage{
+		Objects:               make([]*osi.ObjectInfo, 0, len(objects.Contents)),
+		IsTruncated:           objects.IsTruncated,
+		NextContinuationToken: objects.NextContinuationToken,
+	}
+	for _, object := range objects.Contents {
 		if strings.HasSuffix(object.Key, "/") {
 			continue
 		}
-		oms = append(oms, osi.NewObjectMeta(t.bucket, object.Key))
+		page.Objects = append(page.Objects, listedObject(t.bucket, object))
+	}
+	for _, commonPrefix := range objects.CommonPrefixes {
+		page.CommonPrefixes = append(page.CommonPrefixes, commonPrefix.Prefix)
 	}
-	return oms, nil
+	return page, nil
 }
 
 func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
-	stat, err := t.client.StatObject(ctx, t.bucket, path, minio.StatObjectOptions{})
+	info, err := t.StatObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return osi.NewSize(info.Size), nil
+}
+
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	options := osi.NewGetOptions(opts...)
+	sse, err := customerKey(options.CustomerKey)
 	if err != nil {
 		return nil, err
 	}
-	return osi.NewSize(stat.Size), nil
+	stat, err := t.client.StatObject(ctx, t.bucket, path, minio.StatObjectOptions{VersionID: options.VersionID, ServerSideEncryption: sse})
+	if err != nil {
+		return nil, toError(err)
+	}
+	info := osi.NewObjectInfo(t.bucket, path)
+	info.Size = stat.Size
+	info.ETag = osi.TrimETag(stat.ETag)
+	info.VersionID = stat.VersionID
+	info.LastModified = stat.LastModified
+	info.ContentType = stat.ContentType
+	info.ContentEncoding = stat.Metadata.Get("Content-Encoding")
+	info.CacheControl = stat.Metadata.Get("Cache-Control")
+	info.ContentDisposition = stat.Metadata.Get("Content-Disposition")
+	info.Expires = stat.Expires
+	info.StorageClass = storageClass(stat.StorageClass)
+	info.Restore = restoreStatus(stat.Restore)
+	info.Encryption = osi.ParseEncryptionMode(stat.Metadata.Get(encrypt.SseGenericHeader), stat.Metadata.Get(encrypt.SseCustomerAlgorithm))
+	info.KMSKeyID = stat.Metadata.Get(encrypt.SseKmsKeyID)
+	info.Metadata = osi.NormalizeMetadata(stat.UserMetadata)
+	return info, nil
 }
 
-func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration) (string, error) {
-	url, err := t.client.Presign(ctx, method, t.bucket, path, expiredInDur, nil)
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	signed, err := t.signURL(ctx, path, method, expiredInDur, opts...)
+	osi.LogSignURL(ctx, t.config.Logger, Name, t.bucket, path, method, expiredInDur, signed, err)
+	return signed, err
+}
+
+func (t *bucket) signURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	options := osi.NewSignOptions(opts...)
+	url, err := t.client.PresignHeader(ctx, method, t.bucket, path, expiredInDur, options.Query(), options.Header("x-amz-acl"))
 	if err != nil {
 		return "", err
 	}
@@ -183,6 +431,7 @@ func (t *bucket) DeleteObjects(ctx context.Context, paths []string) error {
 func (t *bucket) deleteFiles(ctx context.Context, paths []string) error {
 	objects := make(chan minio.ObjectInfo)
 	go func() {
+		defer close(objects)
 		for i := range paths {
 			objects <- minio.ObjectInfo{Key: paths[i]}
 		}
@@ -190,12 +439,59 @@ func (t *bucket) deleteFiles(ctx context.Context, paths []string) error {
 	errCh := t.client.RemoveObjects(ctx, t.bucket, objects, minio.RemoveObjectsOptions{})
 	for ch := range errCh {
 		if ch.Err != nil {
-			return ch.Err
+			return toError(ch.Err)
 		}
 	}
 	return nil
 }
 
+func listedObject(bucket string, object minio.ObjectInfo) *osi.ObjectInfo {
+	info := osi.NewObjectInfo(bucket, object.Key)
+	info.Size = object.Size
+	info.ETag = osi.TrimETag(object.ETag)
+	info.LastModified = object.LastModified
+	info.StorageClass = storageClass(object.StorageClass)
+	return info
+}
+
+// setWriteConditions sets the ETag conditions MinIO evaluates on writes and
+// rejects the others.
+func setWriteConditions(opts *minio.PutObjectOptions, conditions osi.Conditions) error {
+	if !conditions.IfModifiedSince.IsZero() || !conditions.IfUnmodifiedSince.IsZero() {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional put supports IfMatch and IfNoneMatch only"}
+	}
+	if conditions.IfMatch != "" {
+		opts.SetMatchETag(osi.TrimETag(conditions.IfMatch))
+	}
+	if conditions.IfNoneMatch != "" {
+		opts.SetMatchETagExcept(osi.TrimETag(conditions.IfNoneMatch))
+	}
+	return nil
+}
+
+func putObjectOptions(options *osi.PutOptions) minio.PutObjectOptions {
+	opts := minio.PutObjectOptions{
+		UserMetadata:         make(map[string]string, len(options.Metadata)),
+		ContentType:          options.ContentType,
+		ContentEncoding:      options.ContentEncoding,
+		CacheControl:         options.CacheControl,
+		ContentDisposition:   options.ContentDisposition,
+		UserTags:             options.Tags,
+		ServerSideEncryption: serverSide(options.Encryption),
+		StorageClass:         options.StorageClass,
+	}
+	for key, value := range options.Metadata {
+		opts.UserMetadata[key] = value
+	}
+	if options.ACL != "" {
+		opts.UserMetadata["x-amz-acl"] = options.ACL
+	}
+	if !options.Expires.IsZero() {
+		opts.UserMetadata["Expires"] = options.Expires.UTC().Format(http.TimeFormat)
+	}
+	return opts
+}
+
 type aclEnum struct {
 }
 
@@ -41,6 +41,39 @@ func init() {
 	bucket = objectStore.Bucket(config.MinioBucketName)
 }
 
+func TestObjectStore_Buckets(t *testing.T) {
+	name := fmt.Sprintf("osi-test-%d", time.Now().UnixNano())
+	exist, err := objectStore.BucketExists(ctx, name)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	assert.ErrorIs(t, objectStore.Bucket(name).PutObject(ctx, "test/example.txt", strings.NewReader("some text")), osi.BucketNotFound)
+
+	err = objectStore.CreateBucket(ctx, name)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, objectStore.CreateBucket(ctx, name), osi.BucketAlreadyExists)
+	exist, err = objectStore.BucketExists(ctx, name)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	buckets, err := objectStore.ListBuckets(ctx)
+	assert.NoError(t, err)
+	var names []string
+	for _, info := range buckets {
+		names = append(names, info.Name)
+	}
+	assert.Contains(t, names, name)
+
+	err = objectStore.Bucket(name).PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, objectStore.DeleteBucket(ctx, name), osi.BucketNotEmpty)
+	err = objectStore.Bucket(name).DeleteObject(ctx, "test/example.txt")
+	assert.NoError(t, err)
+	err = objectStore.DeleteBucket(ctx, name)
+	assert.NoError(t, err)
+	exist, err = objectStore.BucketExists(ctx, name)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
 func TestBucket_PutObject(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -53,6 +86,24 @@ func TestBucket_PutObject(t *testing.T) {
 	assert.Equal(t, "some text", string(bs))
 }
 
+func TestBucket_PutObjectWithOptions(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/example.json", strings.NewReader("{}"),
+		osi.WithContentType("application/json"),
+		osi.WithCacheControl("no-cache"),
+		osi.WithContentDisposition("attachment; filename=example.json"),
+		osi.WithMetadata(map[string]string{"owner": "example"}),
+	)
+	assert.NoError(t, err)
+	info, err := bucket.StatObject(ctx, "test/example.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", info.ContentType)
+	assert.Equal(t, "no-cache", info.CacheControl)
+	assert.Equal(t, "attachment; filename=example.json", info.ContentDisposition)
+	assert.Equal(t, "example", info.Metadata["owner"])
+	err = bucket.DeleteObject(ctx, "test/example.json")
+	assert.NoError(t, err)
+}
+
 func TestBucket_DeleteObject(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -62,10 +113,132 @@ func TestBucket_DeleteObject(t *testing.T) {
 	assert.ErrorIs(t, err, osi.ObjectNotFound)
 }
 
+func TestBucket_CopyObject(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"), osi.WithMetadata(map[string]string{"owner": "example"}))
+	assert.NoError(t, err)
+	err = bucket.CopyObject(ctx, "test/example.txt", "test/copy/example.txt")
+	assert.NoError(t, err)
+	object, err := bucket.GetObject(ctx, "test/copy/example.txt")
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "some text", string(bs))
+	_ = object.Close()
+	info, err := bucket.StatObject(ctx, "test/copy/example.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "example", info.Metadata["owner"])
+
+	err = bucket.MoveObject(ctx, "test/copy/example.txt", "test/move/example.txt")
+	assert.NoError(t, err)
+	_, err = bucket.StatObject(ctx, "test/copy/example.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	_, err = bucket.StatObject(ctx, "test/move/example.txt")
+	assert.NoError(t, err)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/move/example.txt"))
+
+	err = bucket.CopyObject(ctx, "test/not-exist.txt", "test/copy/example.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+}
+
+func TestBucket_MultipartUpload(t *testing.T) {
+	first := strings.Repeat("a", osi.MinPartSize)
+	uploadID, err := bucket.InitiateMultipartUpload(ctx, "test/multipart.txt", osi.WithContentType("text/plain"))
+	assert.NoError(t, err)
+	part1, err := bucket.UploadPart(ctx, "test/multipart.txt", uploadID, 1, strings.NewReader(first), int64(len(first)))
+	assert.NoError(t, err)
+	part2, err := bucket.UploadPart(ctx, "test/multipart.txt", uploadID, 2, strings.NewReader("some text"), 9)
+	assert.NoError(t, err)
+	parts, err := bucket.ListParts(ctx, "test/multipart.txt", uploadID)
+	assert.NoError(t, err)
+	assert.Len(t, parts, 2)
+	err = bucket.CompleteMultipartUpload(ctx, "test/multipart.txt", uploadID, []osi.Part{part2, part1})
+	assert.NoError(t, err)
+	object, err := bucket.GetObject(ctx, "test/multipart.txt")
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, first+"some text", string(bs))
+	_ = object.Close()
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/multipart.txt"))
+
+	uploadID, err = bucket.InitiateMultipartUpload(ctx, "test/multipart.txt")
+	assert.NoError(t, err)
+	err = bucket.AbortMultipartUpload(ctx, "test/multipart.txt", uploadID)
+	assert.NoError(t, err)
+	_, err = bucket.ListParts(ctx, "test/multipart.txt", uploadID)
+	assert.ErrorIs(t, err, osi.UploadNotFound)
+}
+
 func TestBucket_GetObject(t *testing.T) {
 	TestBucket_PutObject(t)
 }
 
+func TestBucket_GetObjectConditions(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/conditions.txt", strings.NewReader("some text"))
+	assert.NoError(t, err)
+	info, err := bucket.StatObject(ctx, "test/conditions.txt")
+	assert.NoError(t, err)
+
+	object, err := bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfMatch: info.ETag}))
+	assert.NoError(t, err)
+	assert.NoError(t, object.Close())
+	_, err = bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfMatch: "0123456789abcdef0123456789abcdef"}))
+	assert.ErrorIs(t, err, osi.PreconditionFailed)
+	_, err = bucket.GetObjectRange(ctx, "test/conditions.txt", osi.NewRange(0, 4), osi.WithGetConditions(osi.Conditions{IfMatch: "0123456789abcdef0123456789abcdef"}))
+	assert.ErrorIs(t, err, osi.PreconditionFailed)
+	_, err = bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfNoneMatch: info.ETag}))
+	assert.ErrorIs(t, err, osi.NotModified)
+	_, err = bucket.GetObject(ctx, "test/conditions.txt", osi.WithGetConditions(osi.Conditions{IfModifiedSince: info.LastModified.Add(time.Minute)}))
+	assert.ErrorIs(t, err, osi.NotModified)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/conditions.txt"))
+}
+
+func TestBucket_ObjectTags(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/tags.txt", strings.NewReader("some text"), osi.WithTags(map[string]string{"team": "storage"}))
+	assert.NoError(t, err)
+	tags, err := bucket.GetObjectTags(ctx, "test/tags.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "storage"}, tags)
+
+	err = bucket.PutObjectTags(ctx, "test/tags.txt", map[string]string{"team": "billing", "tier": "cold"})
+	assert.NoError(t, err)
+	tags, err = bucket.GetObjectTags(ctx, "test/tags.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "billing", "tier": "cold"}, tags)
+	assert.ErrorIs(t, bucket.PutObjectTags(ctx, "test/tags.txt", map[string]string{"": "empty"}), osi.InvalidTag)
+
+	assert.NoError(t, bucket.DeleteObjectTags(ctx, "test/tags.txt"))
+	tags, err = bucket.GetObjectTags(ctx, "test/tags.txt")
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/tags.txt"))
+}
+
+func TestBucket_GetObjectRange(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
+	assert.NoError(t, err)
+	object, err := bucket.GetObjectRange(ctx, "test/example.txt", osi.NewRange(2, 4))
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "me t", string(bs))
+	_ = object.Close()
+
+	object, err = bucket.GetObjectRange(ctx, "test/example.txt", osi.RangeFrom(5))
+	assert.NoError(t, err)
+	bs, err = io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "text", string(bs))
+	_ = object.Close()
+
+	object, err = bucket.GetObjectRange(ctx, "test/example.txt", osi.RangeLast(3))
+	assert.NoError(t, err)
+	bs, err = io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "ext", string(bs))
+	_ = object.Close()
+}
+
 func TestBucket_GetObjectSize(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -74,6 +247,33 @@ func TestBucket_GetObjectSize(t *testing.T) {
 	assert.Equal(t, int64(9), size.Size())
 }
 
+func TestBucket_StatObject(t *testing.T) {
+	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
+	assert.NoError(t, err)
+	info, err := bucket.StatObject(ctx, "test/example.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "test/example.txt", info.ObjectPath())
+	assert.Equal(t, int64(9), info.Size)
+	assert.NotEmpty(t, info.ETag)
+	assert.False(t, info.LastModified.IsZero())
+	assert.Equal(t, "text/plain; charset=utf-8", info.ContentType)
+	_, err = bucket.StatObject(ctx, "test/not-exist.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+}
+
+func TestBucket_ObjectNotFound(t *testing.T) {
+	exist, err := bucket.HeadObject(ctx, "test/not-exist.txt")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	_, err = bucket.StatObject(ctx, "test/not-exist.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	_, err = bucket.GetObject(ctx, "test/not-exist.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	_, err = bucket.GetObjectRange(ctx, "test/not-exist.txt", osi.NewRange(0, 4))
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	assert.NoError(t, bucket.DeleteObject(ctx, "test/not-exist.txt"))
+}
+
 func TestBucket_HeadObject(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
@@ -90,6 +290,41 @@ func TestBucket_ListObject(t *testing.T) {
 	assert.Equal(t, 1, len(objects))
 }
 
+func TestBucket_ListObjectsPage(t *testing.T) {
+	paths := []string{"test/list/a.txt", "test/list/b.txt", "test/list/sub/c.txt"}
+	for _, path := range paths {
+		err := bucket.PutObject(ctx, path, strings.NewReader("some text"))
+		assert.NoError(t, err)
+	}
+	defer func() {
+		assert.NoError(t, bucket.DeleteObjects(ctx, paths))
+	}()
+
+	page, err := bucket.ListObjectsPage(ctx, "test/list/", osi.WithDelimiter("/"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(page.Objects))
+	assert.Equal(t, []string{"test/list/sub/"}, page.CommonPrefixes)
+	assert.False(t, page.IsTruncated)
+	for _, object := range page.Objects {
+		assert.Equal(t, int64(9), object.Size)
+		assert.NotEmpty(t, object.ETag)
+		assert.NotEmpty(t, object.StorageClass)
+		assert.False(t, object.LastModified.IsZero())
+	}
+
+	var keys = make([]string, 0)
+	iterator := osi.NewObjectIterator(bucket, "test/list/", osi.WithPageSize(1))
+	for iterator.Next(ctx) {
+		keys = append(keys, iterator.Object().ObjectPath())
+	}
+	assert.NoError(t, iterator.Err())
+	assert.Equal(t, paths, keys)
+
+	page, err = bucket.ListObjectsPage(ctx, "test/list/", osi.WithStartAfter("test/list/a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(page.Objects))
+}
+
 func Test_bucket_SignURL(t *testing.T) {
 	err := bucket.PutObject(ctx, "test/example.txt", strings.NewReader("some text"))
 	assert.NoError(t, err)
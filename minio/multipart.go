@@ -0,0 +1,88 @@
+package minio
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/minio/minio-go/v7"
+	"io"
+	"sort"
+)
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	options := osi.NewPutOptions(path, opts...)
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return "", err
+	}
+	if err := options.Encryption.Validate(); err != nil {
+		return "", err
+	}
+	if err := checkStorageClass(options.StorageClass); err != nil {
+		return "", err
+	}
+	core := minio.Core{Client: t.client}
+	uploadID, err := core.NewMultipartUpload(ctx, t.bucket, path, putObjectOptions(options))
+	return uploadID, toError(err)
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	if err := osi.ValidatePartNumber(partNumber); err != nil {
+		return osi.Part{}, err
+	}
+	sse, err := customerKey(osi.NewPutOptions(path, opts...).Encryption.CustomerKey)
+	if err != nil {
+		return osi.Part{}, err
+	}
+	core := minio.Core{Client: t.client}
+	part, err := core.PutObjectPart(ctx, t.bucket, path, uploadID, partNumber, reader, size, minio.PutObjectPartOptions{SSE: sse})
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	return osi.Part{PartNumber: partNumber, ETag: osi.TrimETag(part.ETag), Size: size}, nil
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	core := minio.Core{Client: t.client}
+	var parts = make([]osi.Part, 0)
+	var marker = 0
+	for {
+		result, err := core.ListObjectParts(ctx, t.bucket, path, uploadID, marker, osi.MaxPageSize)
+		if err != nil {
+			return nil, toError(err)
+		}
+		for _, part := range result.ObjectParts {
+			parts = append(parts, osi.Part{
+				PartNumber:   part.PartNumber,
+				ETag:         osi.TrimETag(part.ETag),
+				Size:         part.Size,
+				LastModified: part.LastModified,
+			})
+		}
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	putOpts := minio.PutObjectOptions{}
+	if err := setWriteConditions(&putOpts, options.Conditions); err != nil {
+		return err
+	}
+	var completed = make([]minio.CompletePart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].PartNumber < completed[j].PartNumber
+	})
+	core := minio.Core{Client: t.client}
+	_, err := core.CompleteMultipartUpload(ctx, t.bucket, path, uploadID, completed, putOpts)
+	return toError(err)
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	core := minio.Core{Client: t.client}
+	return toError(core.AbortMultipartUpload(ctx, t.bucket, path, uploadID))
+}
@@ -0,0 +1,54 @@
+package minio
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/minio/minio-go/v7"
+	"time"
+)
+
+// SignPostPolicy rejects ACLs, which MinIO ignores on objects.
+func (t *bucket) SignPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	form, err := t.signPostPolicy(ctx, policy, expiredInDur)
+	osi.LogSignPostPolicy(ctx, t.config.Logger, Name, t.bucket, policy, expiredInDur, form, err)
+	return form, err
+}
+
+func (t *bucket) signPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if policy.ACL != "" {
+		return nil, &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "post policy with ACL"}
+	}
+
+	post := minio.NewPostPolicy()
+	var err error
+	if policy.Key != "" {
+		err = post.SetKey(policy.Key)
+	} else {
+		err = post.SetKeyStartsWith(policy.KeyPrefix)
+	}
+	if err == nil {
+		err = post.SetBucket(t.bucket)
+	}
+	if err == nil {
+		err = post.SetExpires(time.Now().Add(expiredInDur))
+	}
+	if err == nil && policy.ContentType != "" {
+		err = post.SetContentType(policy.ContentType)
+	}
+	if err == nil && policy.MaxContentLength > 0 {
+		err = post.SetContentLengthRange(policy.MinContentLength, policy.MaxContentLength)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	url, fields, err := t.client.PresignedPostPolicy(ctx, post)
+	if err != nil {
+		return nil, toError(err)
+	}
+	fields["key"] = policy.FormKey()
+	return &osi.PostForm{URL: url.String(), Fields: fields}, nil
+}
@@ -0,0 +1,48 @@
+package minio
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/minio/minio-go/v7"
+)
+
+// checkStorageClass refuses the shared classes MinIO has no counterpart of.
+// Its own classes, STANDARD and REDUCED_REDUNDANCY, select the parity of
+// the erasure coding; objects move to remote tiers by lifecycle rules only.
+func checkStorageClass(class string) error {
+	if class == osi.StorageClassIA || osi.IsArchived(class) {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "storage class " + class}
+	}
+	return nil
+}
+
+func storageClass(class string) string {
+	if class == "" {
+		return osi.StorageClassStandard
+	}
+	return class
+}
+
+func restoreStatus(restore *minio.RestoreInfo) *osi.RestoreStatus {
+	if restore == nil {
+		return nil
+	}
+	return &osi.RestoreStatus{Ongoing: restore.OngoingRestore, ExpiryDate: restore.ExpiryTime}
+}
+
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "setting the storage class"}
+}
+
+// RestoreObject restores objects that lifecycle rules moved to a remote tier.
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	if err := osi.ValidateRestore(days, tier); err != nil {
+		return err
+	}
+	var request minio.RestoreRequest
+	request.SetDays(days)
+	if tier != "" {
+		request.SetGlacierJobParameters(minio.GlacierJobParameters{Tier: minio.TierType(tier)})
+	}
+	return toError(t.client.RestoreObject(ctx, t.bucket, path, "", request))
+}
@@ -0,0 +1,31 @@
+package minio
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	objectTags, err := t.client.GetObjectTagging(ctx, t.bucket, path, minio.GetObjectTaggingOptions{})
+	if err != nil {
+		return nil, toError(err)
+	}
+	return objectTags.ToMap(), nil
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tagMap map[string]string) error {
+	if err := osi.ValidateTags(tagMap); err != nil {
+		return err
+	}
+	objectTags, err := tags.NewTags(tagMap, true)
+	if err != nil {
+		return err
+	}
+	return toError(t.client.PutObjectTagging(ctx, t.bucket, path, objectTags, minio.PutObjectTaggingOptions{}))
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	return toError(t.client.RemoveObjectTagging(ctx, t.bucket, path, minio.RemoveObjectTaggingOptions{}))
+}
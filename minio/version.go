@@ -0,0 +1,82 @@
+package minio
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/minio/minio-go/v7"
+	"strings"
+)
+
+// ListObjectVersions pages over the version listing of the SDK, which keeps its
+// markers to itself: the token records the last version returned and the
+// next page skips the listing up to it.
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if options.Delimiter != "" && options.Delimiter != "/" {
+		return nil, &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "version listing supports the \"/\" delimiter only"}
+	}
+	keyMarker, versionIDMarker, err := osi.ParseVersionToken(options.ContinuationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	objects := t.client.ListObjects(ctx, t.bucket, minio.ListObjectsOptions{
+		Prefix:       prefix,
+		Recursive:    options.Delimiter == "",
+		WithVersions: true,
+	})
+
+	var page = &osi.VersionPage{Versions: make([]*osi.ObjectVersion, 0)}
+	var skipping = keyMarker != ""
+	var count = 0
+	for object := range objects {
+		if object.Err != nil {
+			return nil, toError(object.Err)
+		}
+		if skipping {
+			skipping = object.Key != keyMarker || object.VersionID != versionIDMarker
+			continue
+		}
+		if options.StartAfter != "" && object.Key <= options.StartAfter {
+			continue
+		}
+		if count == options.PageSize {
+			page.IsTruncated = true
+			break
+		}
+		count++
+		keyMarker, versionIDMarker = object.Key, object.VersionID
+		// common prefixes come through as bare keys without a version
+		if object.VersionID == "" && object.LastModified.IsZero() {
+			page.CommonPrefixes = append(page.CommonPrefixes, object.Key)
+			continue
+		}
+		if strings.HasSuffix(object.Key, "/") {
+			continue
+		}
+		info := listedObject(t.bucket, object)
+		info.VersionID = object.VersionID
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: object.IsLatest, IsDeleteMarker: object.IsDeleteMarker})
+	}
+	if page.IsTruncated {
+		page.NextContinuationToken = osi.VersionToken(keyMarker, versionIDMarker)
+	}
+	return page, nil
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	config, err := t.client.GetBucketVersioning(ctx, t.bucket)
+	if err != nil {
+		return osi.VersioningOff, toError(err)
+	}
+	return osi.VersioningStatus(config.Status), nil
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	return toError(t.client.SetBucketVersioning(ctx, t.bucket, minio.BucketVersioningConfiguration{Status: string(status)}))
+}
@@ -0,0 +1,29 @@
+package osi
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// MinPartSize is the smallest part the providers accept, except for the
+	// last part of an upload.
+	MinPartSize = 5 << 20
+	MaxPartSize = 5 << 30
+)
+
+// Part is an uploaded part. Pass the parts returned by UploadPart or
+// ListParts to CompleteMultipartUpload.
+type Part struct {
+	PartNumber   int
+	ETag         string
+	Size         int64
+	LastModified time.Time
+}
+
+func ValidatePartNumber(partNumber int) error {
+	if partNumber < 1 || partNumber > MaxPartCount {
+		return fmt.Errorf("part number must be between 1 and %d", MaxPartCount)
+	}
+	return nil
+}
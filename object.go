@@ -2,7 +2,10 @@ package osi
 
 import (
 	"io"
+	"net/http"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type ObjectMeta interface {
@@ -51,3 +54,52 @@ func NewObject(bucket string, path string, acl ACL, reader io.ReadCloser) Object
 func (t *object) ObjectACL() ACL {
 	return t.acl
 }
+
+type ObjectInfo struct {
+	ObjectMeta
+	Size               int64
+	ETag               string
+	VersionID          string
+	LastModified       time.Time
+	ContentType        string
+	ContentEncoding    string
+	CacheControl       string
+	ContentDisposition string
+	Expires            time.Time
+	StorageClass       string
+	// Restore is nil unless a restore of the archived object was requested.
+	Restore    *RestoreStatus
+	Encryption EncryptionMode
+	KMSKeyID   string
+	Metadata   map[string]string
+}
+
+func NewObjectInfo(bucket string, path string) *ObjectInfo {
+	return &ObjectInfo{ObjectMeta: NewObjectMeta(bucket, path), Metadata: make(map[string]string)}
+}
+
+// MetadataFromHeader collects the user metadata carried in header under the
+// given prefix, e.g. "x-amz-meta-". Keys are returned lower-cased without the prefix.
+func MetadataFromHeader(header http.Header, prefix string) map[string]string {
+	var metadata = make(map[string]string)
+	for key, values := range header {
+		if len(values) == 0 || !strings.HasPrefix(strings.ToLower(key), prefix) {
+			continue
+		}
+		metadata[strings.ToLower(key[len(prefix):])] = values[0]
+	}
+	return metadata
+}
+
+// NormalizeMetadata lower-cases the keys of provider metadata maps.
+func NormalizeMetadata(metadata map[string]string) map[string]string {
+	var normalized = make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		normalized[strings.ToLower(key)] = value
+	}
+	return normalized
+}
+
+func TrimETag(etag string) string {
+	return strings.Trim(etag, "\"")
+}
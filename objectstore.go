@@ -1,7 +1,29 @@
 package osi
 
+import (
+	"context"
+	"time"
+)
+
 type ObjectStore interface {
 	Name() string
 	Bucket(name string) Bucket
 	ACLEnum() ACLEnum
+	BucketManager
+}
+
+// BucketManager creates and removes the buckets handed out by ObjectStore.Bucket.
+type BucketManager interface {
+	CreateBucket(ctx context.Context, name string, opts ...BucketOption) error
+	// DeleteBucket removes an empty bucket; it fails with BucketNotEmpty otherwise.
+	DeleteBucket(ctx context.Context, name string) error
+	ListBuckets(ctx context.Context) ([]BucketInfo, error)
+	BucketExists(ctx context.Context, name string) (bool, error)
+}
+
+type BucketInfo struct {
+	Name string
+	// Region is empty for providers that leave it out of the bucket listing.
+	Region       string
+	CreationDate time.Time
 }
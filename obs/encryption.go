@@ -0,0 +1,38 @@
+package obs
+
+import (
+	"github.com/burybell/osi"
+	"github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+)
+
+// sseHeader returns the encryption headers of a put. SSE-S3 is SSE-OBS, which
+// the SDK sends as an SseKmsHeader with the AES256 algorithm.
+func sseHeader(encryption osi.Encryption) obs.ISseHeader {
+	switch encryption.Mode {
+	case osi.EncryptionSSES3:
+		return obs.SseKmsHeader{Encryption: "AES256"}
+	case osi.EncryptionSSEKMS:
+		return obs.SseKmsHeader{Key: encryption.KMSKeyID}
+	case osi.EncryptionSSEC:
+		return customerKey(encryption.CustomerKey)
+	}
+	return nil
+}
+
+func customerKey(key []byte) obs.ISseHeader {
+	if len(key) == 0 {
+		return nil
+	}
+	encoded, digest := osi.CustomerKeyHeaders(key)
+	return obs.SseCHeader{Encryption: obs.DEFAULT_SSE_C_ENCRYPTION, Key: encoded, KeyMD5: digest}
+}
+
+func parseSseHeader(header obs.ISseHeader) (osi.EncryptionMode, string) {
+	switch header := header.(type) {
+	case obs.SseCHeader:
+		return osi.EncryptionSSEC, ""
+	case obs.SseKmsHeader:
+		return osi.ParseEncryptionMode(header.Encryption, ""), header.Key
+	}
+	return osi.EncryptionNone, ""
+}
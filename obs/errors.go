@@ -0,0 +1,21 @@
+package obs
+
+import (
+	"github.com/burybell/osi"
+	"github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+)
+
+// errConditionalPut is returned for puts with conditions, which OBS does not
+// evaluate on writes.
+var errConditionalPut = &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional put"}
+
+// toError maps a huaweicloud-sdk-go-obs error onto the osi error taxonomy.
+func toError(err error) error {
+	switch obsErr := err.(type) {
+	case nil:
+		return nil
+	case obs.ObsError:
+		return osi.NewError(Name, obsErr.Code, obsErr.StatusCode, obsErr.RequestId, obsErr.Message, err)
+	}
+	return osi.TransportError(Name, err)
+}
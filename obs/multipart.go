@@ -0,0 +1,110 @@
+package obs
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+	"io"
+	"sort"
+)
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	options := osi.NewPutOptions(path, opts...)
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return "", err
+	}
+	if err := options.Encryption.Validate(); err != nil {
+		return "", err
+	}
+	input := &obs.InitiateMultipartUploadInput{
+		ObjectOperationInput: objectOperationInput(t.bucket, path, options),
+	}
+	var upload *obs.InitiateMultipartUploadOutput
+	var err error
+	if len(options.Tags) > 0 {
+		upload, err = t.client.InitiateMultipartUpload(input, obs.WithCustomHeader(taggingHeader, osi.EncodeTags(options.Tags)))
+	} else {
+		upload, err = t.client.InitiateMultipartUpload(input)
+	}
+	if err != nil {
+		return "", toError(err)
+	}
+	return upload.UploadId, nil
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	if err := osi.ValidatePartNumber(partNumber); err != nil {
+		return osi.Part{}, err
+	}
+	part, err := t.client.UploadPart(&obs.UploadPartInput{
+		Bucket:     t.bucket,
+		Key:        path,
+		UploadId:   uploadID,
+		PartNumber: partNumber,
+		Body:       reader,
+		PartSize:   size,
+		SseHeader:  customerKey(osi.NewPutOptions(path, opts...).Encryption.CustomerKey),
+	})
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	return osi.Part{PartNumber: partNumber, ETag: osi.TrimETag(part.ETag), Size: size}, nil
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	var parts = make([]osi.Part, 0)
+	var marker = 0
+	for {
+		result, err := t.client.ListParts(&obs.ListPartsInput{
+			Bucket:           t.bucket,
+			Key:              path,
+			UploadId:         uploadID,
+			PartNumberMarker: marker,
+		})
+		if err != nil {
+			return nil, toError(err)
+		}
+		for _, part := range result.Parts {
+			parts = append(parts, osi.Part{
+				PartNumber:   part.PartNumber,
+				ETag:         osi.TrimETag(part.ETag),
+				Size:         part.Size,
+				LastModified: part.LastModified,
+			})
+		}
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	if !options.Conditions.IsZero() {
+		return errConditionalPut
+	}
+	var completed = make([]obs.Part, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, obs.Part{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].PartNumber < completed[j].PartNumber
+	})
+	_, err := t.client.CompleteMultipartUpload(&obs.CompleteMultipartUploadInput{
+		Bucket:   t.bucket,
+		Key:      path,
+		UploadId: uploadID,
+		Parts:    completed,
+	})
+	return toError(err)
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	_, err := t.client.AbortMultipartUpload(&obs.AbortMultipartUploadInput{
+		Bucket:   t.bucket,
+		Key:      path,
+		UploadId: uploadID,
+	})
+	return toError(err)
+}
@@ -2,11 +2,12 @@ package obs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/burybell/osi"
 	"github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
 	"io"
-	"strconv"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -20,22 +21,52 @@ type Config struct {
 	KeyID    string `yaml:"key_id" mapstructure:"key_id" json:"key_id"`
 	Secret   string `yaml:"secret" mapstructure:"secret" json:"secret"`
 	Endpoint string `yaml:"endpoint" mapstructure:"endpoint" json:"endpoint"`
+	// Logger receives the requests sent and the URLs presigned.
+	Logger osi.Logger `yaml:"-" mapstructure:"-" json:"-"`
 }
 
+// ObjectStore is backed by the OBS SDK, which cannot take a context per
+// request: the contexts of the calls neither cancel nor bound their requests,
+// which run to the timeouts of the SDK.
 type ObjectStore struct {
-	config Config
-	client *obs.ObsClient
+	config     Config
+	client     *obs.ObsClient
+	httpClient *http.Client
 }
 
 func NewObjectStore(config Config) (osi.ObjectStore, error) {
 	if config.Endpoint == "" {
 		config.Endpoint = fmt.Sprintf("https://obs.%s.myhuaweicloud.com", config.Region)
 	}
-	client, err := obs.New(config.KeyID, config.Secret, config.Endpoint)
+	client, err := config.newClient(config.Endpoint)
 	if err != nil {
 		return nil, err
 	}
-	return &ObjectStore{config: config, client: client}, nil
+	return &ObjectStore{config: config, client: client, httpClient: config.newHTTPClient()}, nil
+}
+
+// newClient returns a client of endpoint that sends the requests through
+// Logger when one is set. Its HTTP client follows no redirects, as the one of
+// the SDK, which handles them itself.
+func (t Config) newClient(endpoint string) (*obs.ObsClient, error) {
+	if t.Logger == nil {
+		return obs.New(t.KeyID, t.Secret, endpoint)
+	}
+	return obs.New(t.KeyID, t.Secret, endpoint, obs.WithHttpClient(t.newHTTPClient()))
+}
+
+// newHTTPClient returns the HTTP client of the requests sent outside the SDK,
+// which follows no redirects and goes through Logger when one is set.
+func (t Config) newHTTPClient() *http.Client {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	if t.Logger != nil {
+		client.Transport = osi.NewLoggingTransport(Name, t.Logger, nil)
+	}
+	return client
 }
 
 func MustNewObjectStore(config Config) osi.ObjectStore {
@@ -52,30 +83,98 @@ func (t *ObjectStore) Name() string {
 
 func (t *ObjectStore) Bucket(name string) osi.Bucket {
 	return &bucket{
-		config: t.config,
-		client: t.client,
-		bucket: name,
+		config:     t.config,
+		client:     t.client,
+		httpClient: t.httpClient,
+		bucket:     name,
+	}
+}
+
+// CreateBucket creates buckets outside the configured region through the
+// endpoint of their region, which OBS requires to match the location.
+func (t *ObjectStore) CreateBucket(ctx context.Context, name string, opts ...osi.BucketOption) error {
+	options := osi.NewBucketOptions(t.config.Region, opts...)
+	client := t.client
+	if options.Region != t.config.Region {
+		var err error
+		client, err = t.config.newClient(fmt.Sprintf("https://obs.%s.myhuaweicloud.com", options.Region))
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+	}
+	input := &obs.CreateBucketInput{Bucket: name, ACL: obs.AclType(options.ACL)}
+	input.Location = options.Region
+	_, err := client.CreateBucket(input)
+	return toError(err)
+}
+
+func (t *ObjectStore) DeleteBucket(ctx context.Context, name string) error {
+	_, err := t.client.DeleteBucket(name)
+	return toError(err)
+}
+
+func (t *ObjectStore) ListBuckets(ctx context.Context) ([]osi.BucketInfo, error) {
+	var buckets = make([]osi.BucketInfo, 0)
+	input := &obs.ListBucketsInput{QueryLocation: true}
+	for {
+		output, err := t.client.ListBuckets(input)
+		if err != nil {
+			return nil, toError(err)
+		}
+		for _, b := range output.Buckets {
+			buckets = append(buckets, osi.BucketInfo{
+				Name:         b.Name,
+				Region:       b.Location,
+				CreationDate: b.CreationDate,
+			})
+		}
+		if !output.IsTruncated {
+			return buckets, nil
+		}
+		input.Marker = output.NextMarker
 	}
 }
 
+func (t *ObjectStore) BucketExists(ctx context.Context, name string) (bool, error) {
+	_, err := t.client.HeadBucket(name)
+	if err != nil {
+		err = toError(err)
+		if errors.Is(err, osi.ObjectNotFound) || errors.Is(err, osi.BucketNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (t *ObjectStore) ACLEnum() osi.ACLEnum {
 	return aclEnum{}
 }
 
 type bucket struct {
-	config Config
-	client *obs.ObsClient
-	bucket string
+	config     Config
+	client     *obs.ObsClient
+	httpClient *http.Client
+	bucket     string
 }
 
-func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error) {
-	acl, err := t.client.GetObjectAcl(&obs.GetObjectAclInput{Bucket: t.bucket, Key: path})
-	if err != nil {
-		if err.(obs.ObsError).Code == "NoSuchKey" {
-			return nil, osi.ObjectNotFound
-		}
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	return t.getObject(ctx, path, "", osi.NewGetOptions(opts...))
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	if err := rng.Validate(); err != nil {
 		return nil, err
 	}
+	return t.getObject(ctx, path, rng.HeaderValue(), osi.NewGetOptions(opts...))
+}
+
+func (t *bucket) getObject(ctx context.Context, path string, rng string, options *osi.GetOptions) (osi.Object, error) {
+	acl, err := t.client.GetObjectAcl(&obs.GetObjectAclInput{Bucket: t.bucket, Key: path, VersionId: options.VersionID})
+	if err != nil {
+		return nil, toError(err)
+	}
 
 	var publicACL = make(map[obs.PermissionType]int)
 	for _, grant := range acl.Grants {
@@ -91,26 +190,58 @@ func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error)
 		ACL = "private"
 	}
 
-	resp, err := t.client.GetObject(&obs.GetObjectInput{GetObjectMetadataInput: obs.GetObjectMetadataInput{Bucket: t.bucket, Key: path}})
+	var resp *obs.GetObjectOutput
+	conditions := options.Conditions
+	input := &obs.GetObjectInput{
+		GetObjectMetadataInput: obs.GetObjectMetadataInput{Bucket: t.bucket, Key: path, VersionId: options.VersionID, SseHeader: customerKey(options.CustomerKey)},
+		IfMatch:                conditions.IfMatch,
+		IfNoneMatch:            conditions.IfNoneMatch,
+		IfModifiedSince:        conditions.IfModifiedSince,
+		IfUnmodifiedSince:      conditions.IfUnmodifiedSince,
+	}
+	if rng != "" {
+		resp, err = t.client.GetObject(input, obs.WithCustomHeader("Range", rng))
+	} else {
+		resp, err = t.client.GetObject(input)
+	}
 	if err != nil {
-		return nil, err
+		return nil, toError(err)
 	}
 	return osi.NewObject(t.bucket, path, ACL, resp.Body), nil
 }
 
-func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader) error {
-	return t.PutObjectWithACL(ctx, path, reader, aclEnum{}.Default())
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	if !options.Conditions.IsZero() {
+		return errConditionalPut
+	}
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return err
+	}
+	if err := options.Encryption.Validate(); err != nil {
+		return err
+	}
+	return osi.Upload(ctx, t, path, reader, func(ctx context.Context, reader io.Reader, size int64) error {
+		input := &obs.PutObjectInput{PutObjectBasicInput: obs.PutObjectBasicInput{ObjectOperationInput: objectOperationInput(t.bucket, path, options), ContentLength: size}, Body: reader}
+		var err error
+		if len(options.Tags) > 0 {
+			_, err = t.client.PutObject(input, obs.WithCustomHeader(taggingHeader, osi.EncodeTags(options.Tags)))
+		} else {
+			_, err = t.client.PutObject(input)
+		}
+		return toError(err)
+	}, opts...)
 }
 
 func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
-	_, err := t.client.PutObject(&obs.PutObjectInput{PutObjectBasicInput: obs.PutObjectBasicInput{ObjectOperationInput: obs.ObjectOperationInput{Bucket: t.bucket, Key: path, ACL: obs.AclType(acl)}}, Body: reader})
-	return err
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
 }
 
 func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
 	_, err := t.client.HeadObject(&obs.HeadObjectInput{Bucket: t.bucket, Key: path})
 	if err != nil {
-		if err.(obs.ObsError).Code == "NoSuchKey" {
+		err = toError(err)
+		if errors.Is(err, osi.ObjectNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -118,69 +249,172 @@ func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
 	return true, nil
 }
 
-func (t *bucket) DeleteObject(ctx context.Context, path string) error {
-	_, err := t.client.DeleteObject(&obs.DeleteObjectInput{Bucket: t.bucket, Key: path})
-	return err
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	options := osi.NewDeleteOptions(opts...)
+	if !options.Conditions.IsZero() {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional delete"}
+	}
+	_, err := t.client.DeleteObject(&obs.DeleteObjectInput{Bucket: t.bucket, Key: path, VersionId: options.VersionID})
+	return toError(err)
 }
 
-func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
-	var oms = make([]osi.ObjectMeta, 0)
-	var marker = ""
-	for {
-		objects, err := t.client.ListObjects(&obs.ListObjectsInput{
-			ListObjsInput: obs.ListObjsInput{Prefix: prefix, MaxKeys: 200},
-			Bucket:        t.bucket,
-			Marker:        marker,
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	head, err := t.client.GetObjectMetadata(&obs.GetObjectMetadataInput{Bucket: options.SourceBucket, Key: src})
+	if err != nil {
+		return toError(err)
+	}
+	if head.ContentLength <= osi.MaxCopyObjectSize {
+		_, err = t.client.CopyObject(&obs.CopyObjectInput{
+			ObjectOperationInput: obs.ObjectOperationInput{Bucket: t.bucket, Key: dst},
+			CopySourceBucket:     options.SourceBucket,
+			CopySourceKey:        src,
+		})
+		return toError(err)
+	}
+	return toError(t.multipartCopy(options.SourceBucket, src, dst, head))
+}
+
+func (t *bucket) multipartCopy(srcBucket string, src string, dst string, head *obs.GetObjectMetadataOutput) error {
+	upload, err := t.client.InitiateMultipartUpload(&obs.InitiateMultipartUploadInput{
+		ObjectOperationInput: obs.ObjectOperationInput{Bucket: t.bucket, Key: dst, Metadata: head.Metadata, HttpHeader: head.HttpHeader},
+	})
+	if err != nil {
+		return err
+	}
+
+	var parts = make([]obs.Part, 0)
+	for i, rng := range osi.SplitRange(head.ContentLength, osi.CopyPartSize(head.ContentLength)) {
+		part, err := t.client.CopyPart(&obs.CopyPartInput{
+			Bucket:               t.bucket,
+			Key:                  dst,
+			UploadId:             upload.UploadId,
+			PartNumber:           i + 1,
+			CopySourceBucket:     srcBucket,
+			CopySourceKey:        src,
+			CopySourceRangeStart: rng.Offset,
+			CopySourceRangeEnd:   rng.Offset + rng.Length - 1,
 		})
 		if err != nil {
-			return oms, err
-		}
-		for _, key := range objects.Contents {
-			if key.Key != "" {
-				if strings.HasSuffix(key.Key, "/") {
-					continue
-				}
-				oms = append(oms, osi.NewObjectMeta(t.bucket, key.Key))
-			}
+			_, _ = t.client.AbortMultipartUpload(&obs.AbortMultipartUploadInput{Bucket: t.bucket, Key: dst, UploadId: upload.UploadId})
+			return err
 		}
-		if !objects.IsTruncated {
-			return oms, nil
-		} else {
-			if objects.NextMarker != "" {
-				marker = objects.NextMarker
-			} else {
-				return oms, nil
-			}
+		parts = append(parts, obs.Part{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+	_, err = t.client.CompleteMultipartUpload(&obs.CompleteMultipartUploadInput{Bucket: t.bucket, Key: dst, UploadId: upload.UploadId, Parts: parts})
+	return err
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	err := t.CopyObject(ctx, src, dst, opts...)
+	if err != nil {
+		return err
+	}
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	_, err = t.client.DeleteObject(&obs.DeleteObjectInput{Bucket: options.SourceBucket, Key: src})
+	return toError(err)
+}
+
+func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
+	return osi.ListAll(ctx, t, prefix)
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	var marker = options.ContinuationToken
+	if marker == "" {
+		marker = options.StartAfter
+	}
+	objects, err := t.client.ListObjects(&obs.ListObjectsInput{
+		ListObjsInput: obs.ListObjsInput{Prefix: prefix, MaxKeys: options.PageSize, Delimiter: options.Delimiter},
+		Bucket:        t.bucket,
+		Marker:        marker,
+	})
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.ObjectPage{
+		Objects:        make([]*osi.ObjectInfo, 0, len(objects.Contents)),
+		CommonPrefixes: objects.CommonPrefixes,
+		IsTruncated:    objects.IsTruncated,
+	}
+	var lastKey = ""
+	for _, key := range objects.Contents {
+		lastKey = key.Key
+		if key.Key == "" || strings.HasSuffix(key.Key, "/") {
+			continue
 		}
+		page.Objects = append(page.Objects, listedObject(t.bucket, key))
+	}
+	if objects.IsTruncated {
+		page.NextContinuationToken = nextMarker(objects.NextMarker, lastKey, objects.CommonPrefixes)
 	}
+	return page, nil
 }
 
 func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
-	resp, err := t.client.HeadObject(&obs.HeadObjectInput{Bucket: t.bucket, Key: path})
+	info, err := t.StatObject(ctx, path)
 	if err != nil {
-		if err.(obs.ObsError).Code == "NoSuchKey" {
-			return nil, osi.ObjectNotFound
-		}
 		return nil, err
 	}
+	return osi.NewSize(info.Size), nil
+}
 
-	if len(resp.ResponseHeaders["content-length"]) > 0 {
-		sz, err := strconv.ParseInt(resp.ResponseHeaders["content-length"][0], 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		return osi.NewSize(sz), nil
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	options := osi.NewGetOptions(opts...)
+	resp, err := t.client.GetObjectMetadata(&obs.GetObjectMetadataInput{Bucket: t.bucket, Key: path, VersionId: options.VersionID, SseHeader: customerKey(options.CustomerKey)})
+	if err != nil {
+		return nil, toError(err)
 	}
-	return osi.NewSize(0), nil
+	info := osi.NewObjectInfo(t.bucket, path)
+	info.Size = resp.ContentLength
+	info.ETag = osi.TrimETag(resp.ETag)
+	info.VersionID = resp.VersionId
+	info.LastModified = resp.LastModified
+	info.ContentType = resp.ContentType
+	info.ContentEncoding = resp.ContentEncoding
+	info.CacheControl = resp.CacheControl
+	info.ContentDisposition = resp.ContentDisposition
+	info.Expires, _ = http.ParseTime(resp.HttpExpires)
+	info.StorageClass = storageClass(string(resp.StorageClass))
+	if classes := resp.ResponseHeaders["storage-class"]; len(classes) > 0 {
+		// the SDK drops the classes it does not know, DEEP_ARCHIVE among them
+		info.StorageClass = storageClass(classes[0])
+	}
+	info.Restore = osi.ParseRestoreStatus(resp.Restore)
+	info.Encryption, info.KMSKeyID = parseSseHeader(resp.SseHeader)
+	info.Metadata = osi.NormalizeMetadata(resp.Metadata)
+	return info, nil
 }
 
-func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration) (string, error) {
-	url, err := t.client.CreateSignedUrl(&obs.CreateSignedUrlInput{
-		Method:  obs.HttpMethodType(method),
-		Bucket:  t.bucket,
-		Key:     path,
-		Expires: int(expiredInDur.Seconds()),
-	})
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	signed, err := t.signURL(ctx, path, method, expiredInDur, opts...)
+	osi.LogSignURL(ctx, t.config.Logger, Name, t.bucket, path, method, expiredInDur, signed, err)
+	return signed, err
+}
+
+func (t *bucket) signURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	options := osi.NewSignOptions(opts...)
+	query, header := options.Query(), options.Header("x-obs-acl")
+	input := &obs.CreateSignedUrlInput{
+		Method:      obs.HttpMethodType(method),
+		Bucket:      t.bucket,
+		Key:         path,
+		Expires:     int(expiredInDur.Seconds()),
+		Headers:     make(map[string]string, len(header)),
+		QueryParams: make(map[string]string, len(query)),
+	}
+	for key := range header {
+		input.Headers[key] = header.Get(key)
+	}
+	for key := range query {
+		input.QueryParams[key] = query.Get(key)
+	}
+	url, err := t.client.CreateSignedUrl(input)
 	if err != nil {
 		return "", err
 	}
@@ -212,8 +446,65 @@ func (t *bucket) deleteFiles(ctx context.Context, paths []string) error {
 			Key: paths[i],
 		})
 	}
-	_, err := t.client.DeleteObjects(input)
-	return err
+	output, err := t.client.DeleteObjects(input)
+	if err != nil {
+		return toError(err)
+	}
+	if len(output.Errors) > 0 {
+		keys := make([]string, len(output.Errors))
+		for i, failed := range output.Errors {
+			keys[i] = failed.Key
+		}
+		failed := output.Errors[0]
+		return osi.DeleteError(Name, failed.Code, failed.Message, keys)
+	}
+	return nil
+}
+
+func objectOperationInput(bucket string, path string, options *osi.PutOptions) obs.ObjectOperationInput {
+	input := obs.ObjectOperationInput{
+		Bucket:   bucket,
+		Key:      path,
+		ACL:      obs.AclType(options.ACL),
+		Metadata: options.Metadata,
+		HttpHeader: obs.HttpHeader{
+			ContentType:        options.ContentType,
+			ContentEncoding:    options.ContentEncoding,
+			CacheControl:       options.CacheControl,
+			ContentDisposition: options.ContentDisposition,
+		},
+	}
+	if !options.Expires.IsZero() {
+		input.HttpExpires = options.Expires.UTC().Format(http.TimeFormat)
+	}
+	if options.StorageClass != "" {
+		input.StorageClass = obs.StorageClassType(storageClasses.Provider(options.StorageClass))
+	}
+	input.SseHeader = sseHeader(options.Encryption)
+	return input
+}
+
+func listedObject(bucket string, object obs.Content) *osi.ObjectInfo {
+	info := osi.NewObjectInfo(bucket, object.Key)
+	info.Size = object.Size
+	info.ETag = osi.TrimETag(object.ETag)
+	info.LastModified = object.LastModified
+	info.StorageClass = storageClass(string(object.StorageClass))
+	return info
+}
+
+// nextMarker picks the marker for the next page when the service omits
+// NextMarker, which it does for listings without a delimiter.
+func nextMarker(marker string, lastKey string, commonPrefixes []string) string {
+	if marker != "" {
+		return marker
+	}
+	for _, commonPrefix := range commonPrefixes {
+		if commonPrefix > lastKey {
+			lastKey = commonPrefix
+		}
+	}
+	return lastKey
 }
 
 type aclEnum struct {
@@ -0,0 +1,46 @@
+package obs
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+)
+
+var storageClasses = osi.StorageClassNames{
+	osi.StorageClassStandard:    string(obs.StorageClassStandard),
+	osi.StorageClassIA:          string(obs.StorageClassWarm),
+	osi.StorageClassArchive:     string(obs.StorageClassCold),
+	osi.StorageClassDeepArchive: "DEEP_ARCHIVE",
+}
+
+// storageClass returns the class of an object, which OBS leaves out for
+// STANDARD objects and names as S3 does for requests signed the S3 way.
+func storageClass(class string) string {
+	if class == "" {
+		return osi.StorageClassStandard
+	}
+	if parsed := obs.ParseStringToStorageClassType(class); parsed != "" {
+		class = string(parsed)
+	}
+	return storageClasses.Class(class)
+}
+
+// SetStorageClass changes the class in place, keeping the metadata of the
+// object as it is.
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	_, err := t.client.SetObjectMetadata(&obs.SetObjectMetadataInput{
+		Bucket:            t.bucket,
+		Key:               path,
+		MetadataDirective: obs.ReplaceNew,
+		StorageClass:      obs.StorageClassType(storageClasses.Provider(class)),
+	})
+	return toError(err)
+}
+
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	if err := osi.ValidateRestore(days, tier); err != nil {
+		return err
+	}
+	_, err := t.client.RestoreObject(&obs.RestoreObjectInput{Bucket: t.bucket, Key: path, Days: days, Tier: obs.RestoreTierType(tier)})
+	return toError(err)
+}
@@ -0,0 +1,116 @@
+package obs
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"github.com/burybell/osi"
+	"github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+	"net/http"
+	"sort"
+)
+
+const (
+	// noSuchTagSet is the error code OBS answers reads of objects without
+	// tags with.
+	noSuchTagSet = "NoSuchTagSet"
+	// taggingHeader carries the tags of puts, which the SDK has no field for.
+	// The client signs its requests as V2, under which OBS reads the x-amz-
+	// headers.
+	taggingHeader = "x-amz-tagging"
+)
+
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	var tagging obs.BucketTagging
+	err := t.doTagging(ctx, http.MethodGet, path, nil, &tagging)
+	var obsErr obs.ObsError
+	if errors.As(err, &obsErr) && obsErr.Code == noSuchTagSet {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(tagging.Tags))
+	for _, tag := range tagging.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tags map[string]string) error {
+	if err := osi.ValidateTags(tags); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var tagging obs.BucketTagging
+	for _, key := range keys {
+		tagging.Tags = append(tagging.Tags, obs.Tag{Key: key, Value: tags[key]})
+	}
+	body, err := xml.Marshal(tagging)
+	if err != nil {
+		return err
+	}
+	return t.doTagging(ctx, http.MethodPut, path, body, nil)
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	return t.doTagging(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// doTagging sends method to the tagging subresource of the object at path
+// through a URL signed by the SDK, which manages the tags of buckets only, and
+// decodes the response into out when it is set. Unlike the calls of the SDK,
+// the request is bound to ctx.
+func (t *bucket) doTagging(ctx context.Context, method string, path string, body []byte, out interface{}) error {
+	headers := make(map[string]string)
+	if body != nil {
+		sum := md5.Sum(body)
+		headers["Content-MD5"] = base64.StdEncoding.EncodeToString(sum[:])
+		headers["Content-Type"] = "application/xml"
+	}
+	signed, err := t.client.CreateSignedUrl(&obs.CreateSignedUrlInput{
+		Method:      obs.HttpMethodType(method),
+		Bucket:      t.bucket,
+		Key:         path,
+		SubResource: obs.SubResourceTagging,
+		Expires:     60,
+		Headers:     headers,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, signed.SignedUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range signed.ActualSignedRequestHeaders {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return osi.TransportError(Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		obsErr := obs.ObsError{Status: resp.Status}
+		_ = xml.NewDecoder(resp.Body).Decode(&obsErr)
+		obsErr.StatusCode = resp.StatusCode
+		if obsErr.RequestId == "" {
+			obsErr.RequestId = resp.Header.Get("X-Obs-Request-Id")
+		}
+		return toError(obsErr)
+	}
+	if out == nil {
+		return nil
+	}
+	return xml.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,76 @@
+package obs
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/huaweicloud/huaweicloud-sdk-go-obs/obs"
+	"strings"
+)
+
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	keyMarker, versionIDMarker, err := osi.ParseVersionToken(options.ContinuationToken)
+	if err != nil {
+		return nil, err
+	}
+	if keyMarker == "" {
+		keyMarker = options.StartAfter
+	}
+	resp, err := t.client.ListVersions(&obs.ListVersionsInput{
+		ListObjsInput:   obs.ListObjsInput{Prefix: prefix, MaxKeys: options.PageSize, Delimiter: options.Delimiter},
+		Bucket:          t.bucket,
+		KeyMarker:       keyMarker,
+		VersionIdMarker: versionIDMarker,
+	})
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.VersionPage{
+		Versions:       make([]*osi.ObjectVersion, 0, len(resp.Versions)+len(resp.DeleteMarkers)),
+		CommonPrefixes: resp.CommonPrefixes,
+		IsTruncated:    resp.IsTruncated,
+	}
+	if resp.IsTruncated {
+		page.NextContinuationToken = osi.VersionToken(resp.NextKeyMarker, resp.NextVersionIdMarker)
+	}
+	for _, version := range resp.Versions {
+		if strings.HasSuffix(version.Key, "/") {
+			continue
+		}
+		info := osi.NewObjectInfo(t.bucket, version.Key)
+		info.Size = version.Size
+		info.ETag = osi.TrimETag(version.ETag)
+		info.VersionID = version.VersionId
+		info.LastModified = version.LastModified
+		info.StorageClass = storageClass(string(version.StorageClass))
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: version.IsLatest})
+	}
+	for _, marker := range resp.DeleteMarkers {
+		info := osi.NewObjectInfo(t.bucket, marker.Key)
+		info.VersionID = marker.VersionId
+		info.LastModified = marker.LastModified
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: marker.IsLatest, IsDeleteMarker: true})
+	}
+	osi.SortVersions(page.Versions)
+	return page, nil
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	resp, err := t.client.GetBucketVersioning(t.bucket)
+	if err != nil {
+		return osi.VersioningOff, toError(err)
+	}
+	return osi.VersioningStatus(resp.Status), nil
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	_, err := t.client.SetBucketVersioning(&obs.SetBucketVersioningInput{
+		Bucket:                        t.bucket,
+		BucketVersioningConfiguration: obs.BucketVersioningConfiguration{Status: obs.VersioningStatusType(status)},
+	})
+	return toError(err)
+}
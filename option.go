@@ -0,0 +1,178 @@
+package osi
+
+import (
+	"mime"
+	"path/filepath"
+	"time"
+)
+
+type PutOptions struct {
+	ACL                ACL
+	ContentType        string
+	ContentEncoding    string
+	CacheControl       string
+	ContentDisposition string
+	Expires            time.Time
+	Metadata           map[string]string
+	Tags               map[string]string
+	PartSize           int64
+	Concurrency        int
+	Conditions         Conditions
+	Encryption         Encryption
+	StorageClass       string
+}
+
+type PutOption func(opts *PutOptions)
+
+func WithACL(acl ACL) PutOption {
+	return func(opts *PutOptions) {
+		opts.ACL = acl
+	}
+}
+
+func WithContentType(contentType string) PutOption {
+	return func(opts *PutOptions) {
+		opts.ContentType = contentType
+	}
+}
+
+func WithContentEncoding(contentEncoding string) PutOption {
+	return func(opts *PutOptions) {
+		opts.ContentEncoding = contentEncoding
+	}
+}
+
+func WithCacheControl(cacheControl string) PutOption {
+	return func(opts *PutOptions) {
+		opts.CacheControl = cacheControl
+	}
+}
+
+func WithContentDisposition(contentDisposition string) PutOption {
+	return func(opts *PutOptions) {
+		opts.ContentDisposition = contentDisposition
+	}
+}
+
+func WithExpires(expires time.Time) PutOption {
+	return func(opts *PutOptions) {
+		opts.Expires = expires
+	}
+}
+
+// WithMetadata adds user metadata, sent as x-*-meta-* headers by the cloud backends.
+func WithMetadata(metadata map[string]string) PutOption {
+	return func(opts *PutOptions) {
+		if opts.Metadata == nil {
+			opts.Metadata = make(map[string]string, len(metadata))
+		}
+		for key, value := range metadata {
+			opts.Metadata[key] = value
+		}
+	}
+}
+
+// WithTags tags the object with tags, which must pass ValidateTags.
+func WithTags(tags map[string]string) PutOption {
+	return func(opts *PutOptions) {
+		if opts.Tags == nil {
+			opts.Tags = make(map[string]string, len(tags))
+		}
+		for key, value := range tags {
+			opts.Tags[key] = value
+		}
+	}
+}
+
+// WithPartSize sets the size of the parts large uploads are split into. It is
+// raised to MinPartSize, and further when the object would not fit into
+// MaxPartCount parts.
+func WithPartSize(partSize int64) PutOption {
+	return func(opts *PutOptions) {
+		opts.PartSize = partSize
+	}
+}
+
+// WithConcurrency sets how many parts of a large upload are sent at once.
+func WithConcurrency(concurrency int) PutOption {
+	return func(opts *PutOptions) {
+		opts.Concurrency = concurrency
+	}
+}
+
+// WithPutConditions makes the put fail with PreconditionFailed unless the
+// object currently stored at the path satisfies conditions.
+func WithPutConditions(conditions Conditions) PutOption {
+	return func(opts *PutOptions) {
+		opts.Conditions = conditions
+	}
+}
+
+// WithEncryption encrypts the object at rest as encryption selects.
+func WithEncryption(encryption Encryption) PutOption {
+	return func(opts *PutOptions) {
+		opts.Encryption = encryption
+	}
+}
+
+// WithStorageClass stores the object in class, one of the StorageClass
+// constants or a class the provider names.
+func WithStorageClass(class string) PutOption {
+	return func(opts *PutOptions) {
+		opts.StorageClass = class
+	}
+}
+
+// NewPutOptions applies opts for the object at path. ContentType falls back to
+// the type registered for the path extension.
+func NewPutOptions(path string, opts ...PutOption) *PutOptions {
+	options := &PutOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.ContentType == "" {
+		options.ContentType = mime.TypeByExtension(filepath.Ext(path))
+	}
+	if options.PartSize == 0 {
+		options.PartSize = DefaultPartSize
+	}
+	if options.PartSize < MinPartSize {
+		options.PartSize = MinPartSize
+	}
+	if options.Concurrency < 1 {
+		options.Concurrency = DefaultConcurrency
+	}
+	return options
+}
+
+type BucketOptions struct {
+	Region string
+	ACL    ACL
+}
+
+type BucketOption func(opts *BucketOptions)
+
+// WithRegion creates the bucket in region instead of the region of the store.
+func WithRegion(region string) BucketOption {
+	return func(opts *BucketOptions) {
+		opts.Region = region
+	}
+}
+
+func WithBucketACL(acl ACL) BucketOption {
+	return func(opts *BucketOptions) {
+		opts.ACL = acl
+	}
+}
+
+// NewBucketOptions applies opts; Region falls back to region, the region of the store.
+func NewBucketOptions(region string, opts ...BucketOption) *BucketOptions {
+	options := &BucketOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Region == "" {
+		options.Region = region
+	}
+	return options
+}
@@ -0,0 +1,33 @@
+package oss
+
+import (
+	aliyun "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/burybell/osi"
+)
+
+// errCustomerKey is returned for SSE-C, which OSS does not offer.
+var errCustomerKey = &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "SSE-C"}
+
+func checkEncryption(encryption osi.Encryption) error {
+	if err := encryption.Validate(); err != nil {
+		return err
+	}
+	if encryption.Mode == osi.EncryptionSSEC {
+		return errCustomerKey
+	}
+	return nil
+}
+
+func encryptionOptions(encryption osi.Encryption) []aliyun.Option {
+	switch encryption.Mode {
+	case osi.EncryptionSSES3:
+		return []aliyun.Option{aliyun.ServerSideEncryption("AES256")}
+	case osi.EncryptionSSEKMS:
+		opts := []aliyun.Option{aliyun.ServerSideEncryption("KMS")}
+		if encryption.KMSKeyID != "" {
+			opts = append(opts, aliyun.ServerSideEncryptionKeyID(encryption.KMSKeyID))
+		}
+		return opts
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package oss
+
+import (
+	"errors"
+	aliyun "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/burybell/osi"
+)
+
+// toError maps an aliyun-oss-go-sdk error onto the osi error taxonomy.
+func toError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var serviceError aliyun.ServiceError
+	if errors.As(err, &serviceError) {
+		return osi.NewError(Name, serviceError.Code, serviceError.StatusCode, serviceError.RequestID, serviceError.Message, err)
+	}
+	var statusError aliyun.UnexpectedStatusCodeError
+	if errors.As(err, &statusError) {
+		return osi.NewError(Name, "", statusError.Got(), "", "", err)
+	}
+	return osi.TransportError(Name, err)
+}
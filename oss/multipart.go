@@ -0,0 +1,108 @@
+package oss
+
+import (
+	"context"
+	aliyun "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/burybell/osi"
+	"io"
+	"sort"
+	"strconv"
+)
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	options := osi.NewPutOptions(path, opts...)
+	if options.ACL == "" {
+		options.ACL = aclEnum{}.Default()
+	}
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return "", err
+	}
+	if err := checkEncryption(options.Encryption); err != nil {
+		return "", err
+	}
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return "", err
+	}
+	imur, err := bkt.InitiateMultipartUpload(path, append(putOptions(options), aliyun.WithContext(ctx))...)
+	if err != nil {
+		return "", toError(err)
+	}
+	return imur.UploadID, nil
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	if err := osi.ValidatePartNumber(partNumber); err != nil {
+		return osi.Part{}, err
+	}
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return osi.Part{}, err
+	}
+	part, err := bkt.UploadPart(t.upload(path, uploadID), reader, size, partNumber, aliyun.WithContext(ctx))
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	return osi.Part{PartNumber: part.PartNumber, ETag: osi.TrimETag(part.ETag), Size: size}, nil
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return nil, err
+	}
+	var parts = make([]osi.Part, 0)
+	var marker = 0
+	for {
+		result, err := bkt.ListUploadedParts(t.upload(path, uploadID), aliyun.PartNumberMarker(marker), aliyun.WithContext(ctx))
+		if err != nil {
+			return nil, toError(err)
+		}
+		for _, part := range result.UploadedParts {
+			parts = append(parts, osi.Part{
+				PartNumber:   part.PartNumber,
+				ETag:         osi.TrimETag(part.ETag),
+				Size:         int64(part.Size),
+				LastModified: part.LastModified,
+			})
+		}
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker, err = strconv.Atoi(result.NextPartNumberMarker)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	conditionOpts, err := writeConditions(options.Conditions)
+	if err != nil {
+		return err
+	}
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return err
+	}
+	var uploaded = make([]aliyun.UploadPart, 0, len(parts))
+	for _, part := range parts {
+		uploaded = append(uploaded, aliyun.UploadPart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+	sort.Sort(aliyun.UploadParts(uploaded))
+	_, err = bkt.CompleteMultipartUpload(t.upload(path, uploadID), uploaded, append(conditionOpts, aliyun.WithContext(ctx))...)
+	return toError(err)
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return err
+	}
+	return toError(bkt.AbortMultipartUpload(t.upload(path, uploadID), aliyun.WithContext(ctx)))
+}
+
+func (t *bucket) upload(path string, uploadID string) aliyun.InitiateMultipartUploadResult {
+	return aliyun.InitiateMultipartUploadResult{Bucket: t.bucket, Key: path, UploadID: uploadID}
+}
@@ -2,13 +2,11 @@ package oss
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	aliyun "github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/burybell/osi"
 	"io"
-	"mime"
-	"path/filepath"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +14,10 @@ import (
 
 const (
 	Name = "oss"
+	// MaxCopyObjectSize is the largest object OSS copies in a single request,
+	// below the limit of the other providers; larger objects are copied part
+	// by part.
+	MaxCopyObjectSize = 1 << 30
 )
 
 type Config struct {
@@ -23,6 +25,8 @@ type Config struct {
 	KeyID    string `yaml:"key_id" mapstructure:"key_id" json:"key_id"`
 	Secret   string `yaml:"secret" mapstructure:"secret" json:"secret"`
 	Endpoint string `yaml:"endpoint" mapstructure:"endpoint" json:"endpoint"`
+	// Logger receives the requests sent and the URLs presigned.
+	Logger osi.Logger `yaml:"-" mapstructure:"-" json:"-"`
 }
 
 type ObjectStore struct {
@@ -34,13 +38,21 @@ func NewObjectStore(config Config) (osi.ObjectStore, error) {
 	if config.Endpoint == "" {
 		config.Endpoint = fmt.Sprintf("https://oss-%s.aliyuncs.com", config.Region)
 	}
-	client, err := aliyun.New(config.Endpoint, config.KeyID, config.Secret)
+	client, err := aliyun.New(config.Endpoint, config.KeyID, config.Secret, config.clientOptions()...)
 	if err != nil {
 		return nil, err
 	}
 	return &ObjectStore{config: config, client: client}, nil
 }
 
+// clientOptions sends the requests through Logger when one is set.
+func (t Config) clientOptions() []aliyun.ClientOption {
+	if t.Logger == nil {
+		return nil
+	}
+	return []aliyun.ClientOption{aliyun.HTTPClient(&http.Client{Transport: osi.NewLoggingTransport(Name, t.Logger, nil)})}
+}
+
 func MustNewObjectStore(config Config) osi.ObjectStore {
 	store, err := NewObjectStore(config)
 	if err != nil {
@@ -57,6 +69,56 @@ func (t *ObjectStore) Bucket(name string) osi.Bucket {
 	return &bucket{config: t.config, client: t.client, bucket: name}
 }
 
+// CreateBucket creates buckets outside the configured region through the
+// public endpoint of their region.
+func (t *ObjectStore) CreateBucket(ctx context.Context, name string, opts ...osi.BucketOption) error {
+	options := osi.NewBucketOptions(t.config.Region, opts...)
+	client := t.client
+	if options.Region != t.config.Region {
+		var err error
+		client, err = aliyun.New(fmt.Sprintf("https://oss-%s.aliyuncs.com", options.Region), t.config.KeyID, t.config.Secret, t.config.clientOptions()...)
+		if err != nil {
+			return err
+		}
+	}
+	var ossOpts []aliyun.Option
+	if options.ACL != "" && options.ACL != (aclEnum{}).Default() {
+		ossOpts = append(ossOpts, aliyun.ACL(aliyun.ACLType(options.ACL)))
+	}
+	return toError(client.CreateBucket(name, ossOpts...))
+}
+
+func (t *ObjectStore) DeleteBucket(ctx context.Context, name string) error {
+	return toError(t.client.DeleteBucket(name))
+}
+
+func (t *ObjectStore) ListBuckets(ctx context.Context) ([]osi.BucketInfo, error) {
+	var buckets = make([]osi.BucketInfo, 0)
+	marker := ""
+	for {
+		result, err := t.client.ListBuckets(aliyun.Marker(marker))
+		if err != nil {
+			return nil, toError(err)
+		}
+		for _, b := range result.Buckets {
+			buckets = append(buckets, osi.BucketInfo{
+				Name:         b.Name,
+				Region:       b.Region,
+				CreationDate: b.CreationDate,
+			})
+		}
+		if !result.IsTruncated {
+			return buckets, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+func (t *ObjectStore) BucketExists(ctx context.Context, name string) (bool, error) {
+	exist, err := t.client.IsBucketExist(name)
+	return exist, toError(err)
+}
+
 func (t *ObjectStore) ACLEnum() osi.ACLEnum {
 	return aclEnum{}
 }
@@ -67,37 +129,75 @@ type bucket struct {
 	bucket string
 }
 
-func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error) {
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	options := osi.NewGetOptions(opts...)
+	if options.CustomerKey != nil {
+		return nil, errCustomerKey
+	}
+	return t.getObject(ctx, path, options.VersionID, readConditions(options.Conditions)...)
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	if err := rng.Validate(); err != nil {
+		return nil, err
+	}
+	options := osi.NewGetOptions(opts...)
+	if options.CustomerKey != nil {
+		return nil, errCustomerKey
+	}
+	ossOpts := append(readConditions(options.Conditions), aliyun.NormalizedRange(strings.TrimPrefix(rng.HeaderValue(), "bytes=")), aliyun.RangeBehavior("standard"))
+	return t.getObject(ctx, path, options.VersionID, ossOpts...)
+}
+
+func (t *bucket) getObject(ctx context.Context, path string, versionID string, options ...aliyun.Option) (osi.Object, error) {
 	bkt, err := t.client.Bucket(t.bucket)
 	if err != nil {
 		return nil, err
 	}
-	acl, err := bkt.GetObjectACL(path)
+	var aclOpts = []aliyun.Option{aliyun.WithContext(ctx)}
+	options = append(options, aliyun.WithContext(ctx))
+	if versionID != "" {
+		aclOpts = append(aclOpts, aliyun.VersionId(versionID))
+		options = append(options, aliyun.VersionId(versionID))
+	}
+	acl, err := bkt.GetObjectACL(path, aclOpts...)
 	if err != nil {
-		var serverError aliyun.ServiceError
-		if errors.As(err, &serverError) && serverError.Code == "NoSuchKey" {
-			return nil, osi.ObjectNotFound
-		}
-		return nil, err
+		return nil, toError(err)
 	}
-	object, err := bkt.GetObject(path)
+	object, err := bkt.GetObject(path, options...)
 	if err != nil {
-		return nil, err
+		return nil, toError(err)
 	}
 	return osi.NewObject(t.bucket, path, acl.ACL, object), nil
 }
 
-func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader) error {
-	return t.PutObjectWithACL(ctx, path, reader, aclEnum{}.Default())
-}
-
-func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
-	object := osi.NewObject(t.bucket, path, acl, io.NopCloser(reader))
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	if options.ACL == "" {
+		options.ACL = aclEnum{}.Default()
+	}
+	conditionOpts, err := writeConditions(options.Conditions)
+	if err != nil {
+		return err
+	}
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return err
+	}
+	if err := checkEncryption(options.Encryption); err != nil {
+		return err
+	}
 	bkt, err := t.client.Bucket(t.bucket)
 	if err != nil {
 		return err
 	}
-	return bkt.PutObject(object.ObjectPath(), object, aliyun.ObjectACL(aliyun.ACLType(acl)), aliyun.ContentType(mime.TypeByExtension(filepath.Ext(path))))
+	return osi.Upload(ctx, t, path, reader, func(ctx context.Context, reader io.Reader, size int64) error {
+		ossOpts := append(putOptions(options), conditionOpts...)
+		return toError(bkt.PutObject(path, reader, append(ossOpts, aliyun.ContentLength(size), aliyun.WithContext(ctx))...))
+	}, opts...)
+}
+
+func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
 }
 
 func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
@@ -105,70 +205,185 @@ func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return bkt.IsObjectExist(path)
+	exist, err := bkt.IsObjectExist(path, aliyun.WithContext(ctx))
+	return exist, toError(err)
 }
 
-func (t *bucket) DeleteObject(ctx context.Context, path string) error {
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	options := osi.NewDeleteOptions(opts...)
+	if !options.Conditions.IsZero() {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional delete"}
+	}
 	bkt, err := t.client.Bucket(t.bucket)
 	if err != nil {
 		return err
 	}
-	return bkt.DeleteObject(path)
+	var ossOpts []aliyun.Option
+	if options.VersionID != "" {
+		ossOpts = append(ossOpts, aliyun.VersionId(options.VersionID))
+	}
+	return toError(bkt.DeleteObject(path, append(ossOpts, aliyun.WithContext(ctx))...))
+}
+
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	srcBkt, err := t.client.Bucket(options.SourceBucket)
+	if err != nil {
+		return err
+	}
+	meta, err := srcBkt.GetObjectDetailedMeta(src, aliyun.WithContext(ctx))
+	if err != nil {
+		return toError(err)
+	}
+	size, err := strconv.ParseInt(meta.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return err
+	}
+	if size <= MaxCopyObjectSize {
+		_, err = bkt.CopyObjectFrom(options.SourceBucket, src, dst, aliyun.WithContext(ctx))
+		return toError(err)
+	}
+	return toError(bkt.CopyFile(options.SourceBucket, src, dst, osi.CopyPartSize(size), aliyun.WithContext(ctx)))
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	err := t.CopyObject(ctx, src, dst, opts...)
+	if err != nil {
+		return err
+	}
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	srcBkt, err := t.client.Bucket(options.SourceBucket)
+	if err != nil {
+		return err
+	}
+	return toError(srcBkt.DeleteObject(src, aliyun.WithContext(ctx)))
 }
 
 func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
+	return osi.ListAll(ctx, t, prefix)
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 	bkt, err := t.client.Bucket(t.bucket)
 	if err != nil {
 		return nil, err
 	}
 
-	var oms = make([]osi.ObjectMeta, 0)
-	var marker = ""
-	for {
-		objects, err := bkt.ListObjects(aliyun.Prefix(prefix), aliyun.MaxKeys(200), aliyun.Marker(marker))
-		if err != nil {
-			return oms, err
-		}
-		for _, o := range objects.Objects {
-			if strings.HasSuffix(o.Key, "/") {
-				continue
-			}
-			oms = append(oms, osi.NewObjectMeta(t.bucket, o.Key))
-		}
-		if !objects.IsTruncated {
-			return oms, nil
-		} else {
-			marker = objects.NextMarker
+	var listOptions = []aliyun.Option{aliyun.Prefix(prefix), aliyun.MaxKeys(options.PageSize)}
+	if options.ContinuationToken != "" {
+		listOptions = append(listOptions, aliyun.ContinuationToken(options.ContinuationToken))
+	}
+	if options.StartAfter != "" {
+		listOptions = append(listOptions, aliyun.StartAfter(options.StartAfter))
+	}
+	if options.Delimiter != "" {
+		listOptions = append(listOptions, aliyun.Delimiter(options.Delimiter))
+	}
+	objects, err := bkt.ListObjectsV2(append(listOptions, aliyun.WithContext(ctx))...)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.ObjectPage{
+		Objects:               make([]*osi.ObjectInfo, 0, len(objects.Objects)),
+		CommonPrefixes:        objects.CommonPrefixes,
+		IsTruncated:           objects.IsTruncated,
+		NextContinuationToken: objects.NextContinuationToken,
+	}
+	for _, o := range objects.Objects {
+		if strings.HasSuffix(o.Key, "/") {
+			continue
 		}
+		page.Objects = append(page.Objects, listedObject(t.bucket, o))
 	}
+	return page, nil
 }
 
 func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
-	bkt, err := t.client.Bucket(t.bucket)
+	info, err := t.StatObject(ctx, path)
 	if err != nil {
 		return nil, err
 	}
-	meta, err := bkt.GetObjectMeta(path)
+	return osi.NewSize(info.Size), nil
+}
+
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	options := osi.NewGetOptions(opts...)
+	if options.CustomerKey != nil {
+		return nil, errCustomerKey
+	}
+	bkt, err := t.client.Bucket(t.bucket)
 	if err != nil {
-		var serverError aliyun.ServiceError
-		if errors.As(err, &serverError) && serverError.Code == "NoSuchKey" {
-			return nil, osi.ObjectNotFound
-		}
 		return nil, err
 	}
+	var ossOpts []aliyun.Option
+	if options.VersionID != "" {
+		ossOpts = append(ossOpts, aliyun.VersionId(options.VersionID))
+	}
+	meta, err := bkt.GetObjectDetailedMeta(path, append(ossOpts, aliyun.WithContext(ctx))...)
+	if err != nil {
+		return nil, toError(err)
+	}
 	size, err := strconv.ParseInt(meta.Get("Content-Length"), 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	return osi.NewSize(size), nil
+	info := osi.NewObjectInfo(t.bucket, path)
+	info.Size = size
+	info.ETag = osi.TrimETag(meta.Get("ETag"))
+	info.VersionID = meta.Get("X-Oss-Version-Id")
+	info.LastModified, _ = http.ParseTime(meta.Get("Last-Modified"))
+	info.ContentType = meta.Get("Content-Type")
+	info.ContentEncoding = meta.Get("Content-Encoding")
+	info.CacheControl = meta.Get("Cache-Control")
+	info.ContentDisposition = meta.Get("Content-Disposition")
+	info.Expires, _ = http.ParseTime(meta.Get("Expires"))
+	info.StorageClass = storageClass(meta.Get("X-Oss-Storage-Class"))
+	info.Restore = osi.ParseRestoreStatus(meta.Get("X-Oss-Restore"))
+	info.Encryption = osi.ParseEncryptionMode(meta.Get("X-Oss-Server-Side-Encryption"), "")
+	info.KMSKeyID = meta.Get("X-Oss-Server-Side-Encryption-Key-Id")
+	info.Metadata = osi.MetadataFromHeader(meta, "x-oss-meta-")
+	return info, nil
+}
+
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	signed, err := t.signURL(ctx, path, method, expiredInDur, opts...)
+	osi.LogSignURL(ctx, t.config.Logger, Name, t.bucket, path, method, expiredInDur, signed, err)
+	return signed, err
 }
 
-func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration) (string, error) {
+func (t *bucket) signURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
 	bkt, err := t.client.Bucket(t.bucket)
 	if err != nil {
 		return "", err
 	}
-	return bkt.SignURL(path, aliyun.HTTPMethod(method), int64(expiredInDur.Seconds()))
+	options := osi.NewSignOptions(opts...)
+	var signOpts []aliyun.Option
+	if options.ResponseContentType != "" {
+		signOpts = append(signOpts, aliyun.ResponseContentType(options.ResponseContentType))
+	}
+	if options.ResponseContentDisposition != "" {
+		signOpts = append(signOpts, aliyun.ResponseContentDisposition(options.ResponseContentDisposition))
+	}
+	if options.ContentType != "" {
+		signOpts = append(signOpts, aliyun.ContentType(options.ContentType))
+	}
+	if options.ContentMD5 != "" {
+		signOpts = append(signOpts, aliyun.ContentMD5(options.ContentMD5))
+	}
+	if options.ACL != "" {
+		signOpts = append(signOpts, aliyun.ObjectACL(aliyun.ACLType(options.ACL)))
+	}
+	return bkt.SignURL(path, aliyun.HTTPMethod(method), int64(expiredInDur.Seconds()), signOpts...)
 }
 
 func (t *bucket) DeleteObjects(ctx context.Context, paths []string) error {
@@ -194,8 +409,76 @@ func (t *bucket) deleteFiles(ctx context.Context, paths []string) error {
 	if err != nil {
 		return err
 	}
-	_, err = bkt.DeleteObjects(paths)
-	return err
+	_, err = bkt.DeleteObjects(paths, aliyun.WithContext(ctx))
+	return toError(err)
+}
+
+func listedObject(bucket string, object aliyun.ObjectProperties) *osi.ObjectInfo {
+	info := osi.NewObjectInfo(bucket, object.Key)
+	info.Size = object.Size
+	info.ETag = osi.TrimETag(object.ETag)
+	info.LastModified = object.LastModified
+	info.StorageClass = storageClass(object.StorageClass)
+	return info
+}
+
+// readConditions maps the conditions of a read onto OSS request options.
+func readConditions(conditions osi.Conditions) []aliyun.Option {
+	var opts []aliyun.Option
+	if conditions.IfMatch != "" {
+		opts = append(opts, aliyun.IfMatch(osi.QuoteETag(conditions.IfMatch)))
+	}
+	if conditions.IfNoneMatch != "" {
+		opts = append(opts, aliyun.IfNoneMatch(osi.QuoteETag(conditions.IfNoneMatch)))
+	}
+	if !conditions.IfModifiedSince.IsZero() {
+		opts = append(opts, aliyun.IfModifiedSince(conditions.IfModifiedSince))
+	}
+	if !conditions.IfUnmodifiedSince.IsZero() {
+		opts = append(opts, aliyun.IfUnmodifiedSince(conditions.IfUnmodifiedSince))
+	}
+	return opts
+}
+
+// writeConditions maps conditions onto OSS, which can only refuse to
+// overwrite an existing object.
+func writeConditions(conditions osi.Conditions) ([]aliyun.Option, error) {
+	if conditions.IsZero() {
+		return nil, nil
+	}
+	if conditions != (osi.Conditions{IfNoneMatch: "*"}) {
+		return nil, &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional put supports IfNoneMatch \"*\" only"}
+	}
+	return []aliyun.Option{aliyun.ForbidOverWrite(true)}, nil
+}
+
+func putOptions(options *osi.PutOptions) []aliyun.Option {
+	var opts = []aliyun.Option{aliyun.ObjectACL(aliyun.ACLType(options.ACL))}
+	if options.ContentType != "" {
+		opts = append(opts, aliyun.ContentType(options.ContentType))
+	}
+	if options.ContentEncoding != "" {
+		opts = append(opts, aliyun.ContentEncoding(options.ContentEncoding))
+	}
+	if options.CacheControl != "" {
+		opts = append(opts, aliyun.CacheControl(options.CacheControl))
+	}
+	if options.ContentDisposition != "" {
+		opts = append(opts, aliyun.ContentDisposition(options.ContentDisposition))
+	}
+	if !options.Expires.IsZero() {
+		opts = append(opts, aliyun.Expires(options.Expires))
+	}
+	for key, value := range options.Metadata {
+		opts = append(opts, aliyun.Meta(key, value))
+	}
+	if len(options.Tags) > 0 {
+		opts = append(opts, aliyun.SetTagging(newTagging(options.Tags)))
+	}
+	if options.StorageClass != "" {
+		opts = append(opts, storageClassOption(options.StorageClass))
+	}
+	return append(opts, encryptionOptions(options.Encryption)...)
 }
 
 type aclEnum struct {
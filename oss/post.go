@@ -0,0 +1,49 @@
+package oss
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"github.com/burybell/osi"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SignPostPolicy signs the policy with the OSS V1 signature, which the SDK
+// implements for requests only.
+func (t *bucket) SignPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	form, err := t.signPostPolicy(ctx, policy, expiredInDur)
+	osi.LogSignPostPolicy(ctx, t.config.Logger, Name, t.bucket, policy, expiredInDur, form, err)
+	return form, err
+}
+
+func (t *bucket) signPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	rawURL := t.config.Endpoint
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	endpoint, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Host = t.bucket + "." + endpoint.Host
+	endpoint.Path = "/"
+
+	fields := policy.FormFields("x-oss-object-acl")
+	document, err := policy.Document(t.bucket, time.Now().Add(expiredInDur), fields)
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(document)
+	mac := hmac.New(sha1.New, []byte(t.config.Secret))
+	mac.Write([]byte(encoded))
+	fields["OSSAccessKeyId"] = t.config.KeyID
+	fields["policy"] = encoded
+	fields["Signature"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return &osi.PostForm{URL: endpoint.String(), Fields: fields}, nil
+}
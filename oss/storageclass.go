@@ -0,0 +1,76 @@
+package oss
+
+import (
+	"context"
+	aliyun "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/burybell/osi"
+	"strconv"
+)
+
+var storageClasses = osi.StorageClassNames{
+	osi.StorageClassStandard:    string(aliyun.StorageStandard),
+	osi.StorageClassIA:          string(aliyun.StorageIA),
+	osi.StorageClassArchive:     string(aliyun.StorageArchive),
+	osi.StorageClassDeepArchive: string(aliyun.StorageColdArchive),
+}
+
+func storageClass(class string) string {
+	if class == "" {
+		return osi.StorageClassStandard
+	}
+	return storageClasses.Class(class)
+}
+
+func storageClassOption(class string) aliyun.Option {
+	return aliyun.ObjectStorageClass(aliyun.StorageClassType(storageClasses.Provider(class)))
+}
+
+// SetStorageClass keeps the content headers, metadata and managed key
+// encryption of the object.
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return err
+	}
+	meta, err := bkt.GetObjectDetailedMeta(path, aliyun.WithContext(ctx))
+	if err != nil {
+		return toError(err)
+	}
+	size, err := strconv.ParseInt(meta.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return err
+	}
+	var opts = []aliyun.Option{storageClassOption(class)}
+	if encryption := meta.Get("X-Oss-Server-Side-Encryption"); encryption != "" {
+		opts = append(opts, aliyun.ServerSideEncryption(encryption))
+	}
+	if keyID := meta.Get("X-Oss-Server-Side-Encryption-Key-Id"); keyID != "" {
+		opts = append(opts, aliyun.ServerSideEncryptionKeyID(keyID))
+	}
+	if size <= MaxCopyObjectSize {
+		_, err = bkt.CopyObject(path, path, append(opts, aliyun.MetadataDirective(aliyun.MetaCopy), aliyun.WithContext(ctx))...)
+		return toError(err)
+	}
+	// the parts of a multipart copy carry no metadata, so the upload is
+	// started with the metadata read
+	for _, header := range []string{"Content-Type", "Content-Encoding", "Cache-Control", "Content-Disposition", "Expires"} {
+		if value := meta.Get(header); value != "" {
+			opts = append(opts, aliyun.SetHeader(header, value))
+		}
+	}
+	for key, value := range osi.MetadataFromHeader(meta, "x-oss-meta-") {
+		opts = append(opts, aliyun.Meta(key, value))
+	}
+	return toError(bkt.CopyFile(t.bucket, path, path, osi.CopyPartSize(size), append(opts, aliyun.WithContext(ctx))...))
+}
+
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	if err := osi.ValidateRestore(days, tier); err != nil {
+		return err
+	}
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return err
+	}
+	return toError(bkt.RestoreObjectDetail(path, aliyun.RestoreConfiguration{Days: int32(days), Tier: string(tier)}, aliyun.WithContext(ctx)))
+}
@@ -0,0 +1,50 @@
+package oss
+
+import (
+	"context"
+	aliyun "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/burybell/osi"
+)
+
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return nil, err
+	}
+	result, err := bkt.GetObjectTagging(path, aliyun.WithContext(ctx))
+	if err != nil {
+		return nil, toError(err)
+	}
+	var tags = make(map[string]string, len(result.Tags))
+	for _, tag := range result.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tags map[string]string) error {
+	if err := osi.ValidateTags(tags); err != nil {
+		return err
+	}
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return err
+	}
+	return toError(bkt.PutObjectTagging(path, newTagging(tags), aliyun.WithContext(ctx)))
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return err
+	}
+	return toError(bkt.DeleteObjectTagging(path, aliyun.WithContext(ctx)))
+}
+
+func newTagging(tags map[string]string) aliyun.Tagging {
+	var tagging = aliyun.Tagging{Tags: make([]aliyun.Tag, 0, len(tags))}
+	for key, value := range tags {
+		tagging.Tags = append(tagging.Tags, aliyun.Tag{Key: key, Value: value})
+	}
+	return tagging
+}
@@ -0,0 +1,82 @@
+package oss
+
+import (
+	"context"
+	aliyun "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/burybell/osi"
+	"strings"
+)
+
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	keyMarker, versionIDMarker, err := osi.ParseVersionToken(options.ContinuationToken)
+	if err != nil {
+		return nil, err
+	}
+	if keyMarker == "" {
+		keyMarker = options.StartAfter
+	}
+	bkt, err := t.client.Bucket(t.bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var listOptions = []aliyun.Option{aliyun.Prefix(prefix), aliyun.MaxKeys(options.PageSize)}
+	if keyMarker != "" {
+		listOptions = append(listOptions, aliyun.KeyMarker(keyMarker))
+	}
+	if versionIDMarker != "" {
+		listOptions = append(listOptions, aliyun.VersionIdMarker(versionIDMarker))
+	}
+	if options.Delimiter != "" {
+		listOptions = append(listOptions, aliyun.Delimiter(options.Delimiter))
+	}
+	versions, err := bkt.ListObjectVersions(append(listOptions, aliyun.WithContext(ctx))...)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.VersionPage{
+		Versions:       make([]*osi.ObjectVersion, 0, len(versions.ObjectVersions)+len(versions.ObjectDeleteMarkers)),
+		CommonPrefixes: versions.CommonPrefixes,
+		IsTruncated:    versions.IsTruncated,
+	}
+	if page.IsTruncated {
+		page.NextContinuationToken = osi.VersionToken(versions.NextKeyMarker, versions.NextVersionIdMarker)
+	}
+	for _, version := range versions.ObjectVersions {
+		if strings.HasSuffix(version.Key, "/") {
+			continue
+		}
+		info := osi.NewObjectInfo(t.bucket, version.Key)
+		info.Size = version.Size
+		info.ETag = osi.TrimETag(version.ETag)
+		info.VersionID = version.VersionId
+		info.LastModified = version.LastModified
+		info.StorageClass = storageClass(version.StorageClass)
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: version.IsLatest})
+	}
+	for _, marker := range versions.ObjectDeleteMarkers {
+		info := osi.NewObjectInfo(t.bucket, marker.Key)
+		info.VersionID = marker.VersionId
+		info.LastModified = marker.LastModified
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: marker.IsLatest, IsDeleteMarker: true})
+	}
+	osi.SortVersions(page.Versions)
+	return page, nil
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	result, err := t.client.GetBucketVersioning(t.bucket)
+	if err != nil {
+		return osi.VersioningOff, toError(err)
+	}
+	return osi.VersioningStatus(result.Status), nil
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	return toError(t.client.SetBucketVersioning(t.bucket, aliyun.VersioningConfig{Status: string(status)}))
+}
@@ -0,0 +1,104 @@
+package osi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// PostFileField is the form field carrying the object, which must follow
+	// all other fields of a PostForm.
+	PostFileField = "file"
+	// PostFilename stands for the name of the uploaded file in the key field.
+	PostFilename = "${filename}"
+)
+
+// PostPolicy restricts the uploads a presigned POST form accepts. Exactly one
+// of Key and KeyPrefix is set: Key fixes the object path, KeyPrefix admits any
+// path below it, by default the prefix followed by the uploaded file name.
+type PostPolicy struct {
+	Key       string
+	KeyPrefix string
+	// ContentType is the only content type accepted when set.
+	ContentType string
+	// MinContentLength and MaxContentLength bound the object size when
+	// MaxContentLength is positive.
+	MinContentLength int64
+	MaxContentLength int64
+	ACL              ACL
+}
+
+// PostForm is a presigned browser upload: Fields, followed by the object in
+// PostFileField, posted to URL as multipart/form-data.
+type PostForm struct {
+	URL    string
+	Fields map[string]string
+}
+
+func (t PostPolicy) Validate() error {
+	if (t.Key == "") == (t.KeyPrefix == "") {
+		return fmt.Errorf("%w: exactly one of key and key prefix must be set", InvalidPolicy)
+	}
+	if t.MinContentLength < 0 || t.MaxContentLength < 0 {
+		return fmt.Errorf("%w: negative content length", InvalidPolicy)
+	}
+	if t.MaxContentLength > 0 && t.MinContentLength > t.MaxContentLength {
+		return fmt.Errorf("%w: content length range %d-%d is empty", InvalidPolicy, t.MinContentLength, t.MaxContentLength)
+	}
+	return nil
+}
+
+// FormKey returns the value of the key field.
+func (t PostPolicy) FormKey() string {
+	if t.Key != "" {
+		return t.Key
+	}
+	return t.KeyPrefix + PostFilename
+}
+
+// FormFields returns the form fields the policy fixes, with the ACL in the
+// field named aclField.
+func (t PostPolicy) FormFields(aclField string) map[string]string {
+	var fields = map[string]string{"key": t.FormKey()}
+	if t.ContentType != "" {
+		fields["Content-Type"] = t.ContentType
+	}
+	if t.ACL != "" {
+		fields[aclField] = t.ACL
+	}
+	return fields
+}
+
+// Document renders the policy document of the S3 compatible POST APIs,
+// expiring at expiration. Besides the key and size conditions of the policy,
+// it requires the upload to go to bucket, and every one of fields but the key
+// to be posted as given.
+func (t PostPolicy) Document(bucket string, expiration time.Time, fields map[string]string) ([]byte, error) {
+	var names = make([]string, 0, len(fields))
+	for name := range fields {
+		if !strings.EqualFold(name, "key") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var conditions = []interface{}{map[string]string{"bucket": bucket}}
+	for _, name := range names {
+		conditions = append(conditions, map[string]string{name: fields[name]})
+	}
+	if t.Key != "" {
+		conditions = append(conditions, []interface{}{"eq", "$key", t.Key})
+	} else {
+		conditions = append(conditions, []interface{}{"starts-with", "$key", t.KeyPrefix})
+	}
+	if t.MaxContentLength > 0 {
+		conditions = append(conditions, []interface{}{"content-length-range", t.MinContentLength, t.MaxContentLength})
+	}
+	return json.Marshal(map[string]interface{}{
+		"expiration": expiration.UTC().Format("2006-01-02T15:04:05.000Z"),
+		"conditions": conditions,
+	})
+}
@@ -0,0 +1,38 @@
+package osi_test
+
+import (
+	"encoding/json"
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPostPolicy_Validate(t *testing.T) {
+	assert.NoError(t, osi.PostPolicy{Key: "test/example.txt"}.Validate())
+	assert.NoError(t, osi.PostPolicy{KeyPrefix: "test/", MaxContentLength: 10}.Validate())
+	assert.ErrorIs(t, osi.PostPolicy{}.Validate(), osi.InvalidPolicy)
+	assert.ErrorIs(t, osi.PostPolicy{Key: "test/example.txt", KeyPrefix: "test/"}.Validate(), osi.InvalidPolicy)
+	assert.ErrorIs(t, osi.PostPolicy{Key: "test/example.txt", MinContentLength: -1}.Validate(), osi.InvalidPolicy)
+	assert.ErrorIs(t, osi.PostPolicy{Key: "test/example.txt", MinContentLength: 10, MaxContentLength: 5}.Validate(), osi.InvalidPolicy)
+}
+
+func TestPostPolicy_Document(t *testing.T) {
+	policy := osi.PostPolicy{KeyPrefix: "test/", ContentType: "text/plain", MinContentLength: 1, MaxContentLength: 1024, ACL: "private"}
+	fields := policy.FormFields("acl")
+	assert.Equal(t, map[string]string{"key": "test/${filename}", "Content-Type": "text/plain", "acl": "private"}, fields)
+
+	expiration := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	document, err := policy.Document("example", expiration, fields)
+	assert.NoError(t, err)
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(document, &decoded))
+	assert.Equal(t, "2024-01-02T03:04:05.000Z", decoded["expiration"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"bucket": "example"},
+		map[string]interface{}{"Content-Type": "text/plain"},
+		map[string]interface{}{"acl": "private"},
+		[]interface{}{"starts-with", "$key", "test/"},
+		[]interface{}{"content-length-range", float64(1), float64(1024)},
+	}, decoded["conditions"])
+}
@@ -0,0 +1,115 @@
+package osi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range is a byte range of an object. A negative Length reads from Offset to
+// the end of the object, a negative Offset reads the last Length bytes.
+type Range struct {
+	Offset int64
+	Length int64
+}
+
+func NewRange(offset int64, length int64) Range {
+	return Range{Offset: offset, Length: length}
+}
+
+func RangeFrom(offset int64) Range {
+	return Range{Offset: offset, Length: -1}
+}
+
+func RangeLast(n int64) Range {
+	return Range{Offset: -1, Length: n}
+}
+
+func (t Range) IsSuffix() bool {
+	return t.Offset < 0
+}
+
+func (t Range) IsOpen() bool {
+	return t.Offset >= 0 && t.Length < 0
+}
+
+func (t Range) Validate() error {
+	if t.Offset < 0 && t.Length <= 0 {
+		return InvalidRange
+	}
+	if t.Offset >= 0 && t.Length == 0 {
+		return InvalidRange
+	}
+	return nil
+}
+
+// HeaderValue renders the range as an HTTP Range header value.
+func (t Range) HeaderValue() string {
+	switch {
+	case t.IsSuffix():
+		return fmt.Sprintf("bytes=-%d", t.Length)
+	case t.IsOpen():
+		return fmt.Sprintf("bytes=%d-", t.Offset)
+	default:
+		return fmt.Sprintf("bytes=%d-%d", t.Offset, t.Offset+t.Length-1)
+	}
+}
+
+// Resolve returns the absolute start and length of the range against an
+// object of the given size.
+func (t Range) Resolve(size int64) (int64, int64, error) {
+	if err := t.Validate(); err != nil {
+		return 0, 0, err
+	}
+	if t.IsSuffix() {
+		if t.Length > size {
+			return 0, size, nil
+		}
+		return size - t.Length, t.Length, nil
+	}
+	if t.Offset >= size {
+		return 0, 0, InvalidRange
+	}
+	if t.IsOpen() || t.Offset+t.Length > size {
+		return t.Offset, size - t.Offset, nil
+	}
+	return t.Offset, t.Length, nil
+}
+
+// ParseRange parses a single range HTTP Range header such as "bytes=0-99",
+// "bytes=100-" or "bytes=-100".
+func ParseRange(header string) (Range, error) {
+	spec := strings.TrimSpace(header)
+	if !strings.HasPrefix(spec, "bytes=") {
+		return Range{}, InvalidRange
+	}
+	spec = strings.TrimPrefix(spec, "bytes=")
+	if strings.Contains(spec, ",") {
+		return Range{}, InvalidRange
+	}
+	items := strings.SplitN(spec, "-", 2)
+	if len(items) != 2 {
+		return Range{}, InvalidRange
+	}
+	first, last := strings.TrimSpace(items[0]), strings.TrimSpace(items[1])
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil {
+			return Range{}, InvalidRange
+		}
+		rng := RangeLast(n)
+		return rng, rng.Validate()
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return Range{}, InvalidRange
+	}
+	if last == "" {
+		return RangeFrom(start), nil
+	}
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < start {
+		return Range{}, InvalidRange
+	}
+	return NewRange(start, end-start+1), nil
+}
@@ -0,0 +1,47 @@
+package osi_test
+
+import (
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	rng, err := osi.ParseRange("bytes=0-99")
+	assert.NoError(t, err)
+	assert.Equal(t, osi.NewRange(0, 100), rng)
+
+	rng, err = osi.ParseRange("bytes=100-")
+	assert.NoError(t, err)
+	assert.Equal(t, osi.RangeFrom(100), rng)
+
+	rng, err = osi.ParseRange("bytes=-100")
+	assert.NoError(t, err)
+	assert.Equal(t, osi.RangeLast(100), rng)
+
+	_, err = osi.ParseRange("bytes=0-1,5-6")
+	assert.ErrorIs(t, err, osi.InvalidRange)
+	_, err = osi.ParseRange("bytes=9-1")
+	assert.ErrorIs(t, err, osi.InvalidRange)
+}
+
+func TestRange_Resolve(t *testing.T) {
+	offset, length, err := osi.NewRange(2, 100).Resolve(10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), offset)
+	assert.Equal(t, int64(8), length)
+
+	offset, length, err = osi.RangeLast(3).Resolve(10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), offset)
+	assert.Equal(t, int64(3), length)
+
+	_, _, err = osi.RangeFrom(10).Resolve(10)
+	assert.ErrorIs(t, err, osi.InvalidRange)
+}
+
+func TestRange_HeaderValue(t *testing.T) {
+	assert.Equal(t, "bytes=0-99", osi.NewRange(0, 100).HeaderValue())
+	assert.Equal(t, "bytes=100-", osi.RangeFrom(100).HeaderValue())
+	assert.Equal(t, "bytes=-100", osi.RangeLast(100).HeaderValue())
+}
@@ -0,0 +1,248 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"io"
+)
+
+// bucket waits for the limits of the calls on the bucket it wraps before
+// making them, and throttles the bodies sent and received to the byte limits.
+// Reads of the object returned by a get wait within the context of the get.
+// Presigning makes no requests and is passed through.
+type bucket struct {
+	osi.Bucket
+	name     string
+	limiters *limiters
+}
+
+// NewBucket wraps bkt, the bucket bucketName, so that its calls are rate
+// limited.
+func NewBucket(bkt osi.Bucket, bucketName string, opts ...Option) osi.Bucket {
+	return &bucket{Bucket: bkt, name: bucketName, limiters: newLimiters(NewOptions(opts...))}
+}
+
+func (t *bucket) read(ctx context.Context, path string) (*limits, error) {
+	l := t.limiters.get(t.name, path)
+	return l, wait(ctx, l.readOps, 1)
+}
+
+func (t *bucket) write(ctx context.Context, path string) (*limits, error) {
+	l := t.limiters.get(t.name, path)
+	return l, wait(ctx, l.writeOps, 1)
+}
+
+type object struct {
+	osi.Object
+	reader io.Reader
+}
+
+func (t *object) Read(p []byte) (int, error) {
+	return t.reader.Read(p)
+}
+
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	l, err := t.read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := t.Bucket.GetObject(ctx, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &object{Object: obj, reader: NewReader(ctx, obj, l.readBytes)}, nil
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	l, err := t.read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := t.Bucket.GetObjectRange(ctx, path, rng, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &object{Object: obj, reader: NewReader(ctx, obj, l.readBytes)}, nil
+}
+
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	l, err := t.write(ctx, path)
+	if err != nil {
+		return err
+	}
+	return t.Bucket.PutObject(ctx, path, NewReader(ctx, reader, l.writeBytes), opts...)
+}
+
+func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
+	l, err := t.write(ctx, path)
+	if err != nil {
+		return err
+	}
+	return t.Bucket.PutObjectWithACL(ctx, path, NewReader(ctx, reader, l.writeBytes), acl)
+}
+
+func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
+	if _, err := t.read(ctx, path); err != nil {
+		return false, err
+	}
+	return t.Bucket.HeadObject(ctx, path)
+}
+
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	if _, err := t.read(ctx, path); err != nil {
+		return nil, err
+	}
+	return t.Bucket.StatObject(ctx, path, opts...)
+}
+
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	if _, err := t.write(ctx, path); err != nil {
+		return err
+	}
+	return t.Bucket.DeleteObject(ctx, path, opts...)
+}
+
+// CopyObject counts against the limits of dst; the copy is made by the
+// provider and does not count against the byte limits.
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	if _, err := t.write(ctx, dst); err != nil {
+		return err
+	}
+	return t.Bucket.CopyObject(ctx, src, dst, opts...)
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	if _, err := t.write(ctx, dst); err != nil {
+		return err
+	}
+	return t.Bucket.MoveObject(ctx, src, dst, opts...)
+}
+
+func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
+	if _, err := t.read(ctx, path); err != nil {
+		return nil, err
+	}
+	return t.Bucket.GetObjectSize(ctx, path)
+}
+
+func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
+	if _, err := t.read(ctx, prefix); err != nil {
+		return nil, err
+	}
+	return t.Bucket.ListObjects(ctx, prefix)
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	if _, err := t.read(ctx, prefix); err != nil {
+		return nil, err
+	}
+	return t.Bucket.ListObjectsPage(ctx, prefix, opts...)
+}
+
+// DeleteObjects counts once against the limits of every key among paths.
+func (t *bucket) DeleteObjects(ctx context.Context, paths []string) error {
+	seen := make(map[*limits]bool)
+	for _, path := range paths {
+		l := t.limiters.get(t.name, path)
+		if seen[l] {
+			continue
+		}
+		seen[l] = true
+		if err := wait(ctx, l.writeOps, 1); err != nil {
+			return err
+		}
+	}
+	return t.Bucket.DeleteObjects(ctx, paths)
+}
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	if _, err := t.write(ctx, path); err != nil {
+		return "", err
+	}
+	return t.Bucket.InitiateMultipartUpload(ctx, path, opts...)
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	l, err := t.write(ctx, path)
+	if err != nil {
+		return osi.Part{}, err
+	}
+	return t.Bucket.UploadPart(ctx, path, uploadID, partNumber, NewReader(ctx, reader, l.writeBytes), size, opts...)
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	if _, err := t.read(ctx, path); err != nil {
+		return nil, err
+	}
+	return t.Bucket.ListParts(ctx, path, uploadID)
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	if _, err := t.write(ctx, path); err != nil {
+		return err
+	}
+	return t.Bucket.CompleteMultipartUpload(ctx, path, uploadID, parts, opts...)
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	if _, err := t.write(ctx, path); err != nil {
+		return err
+	}
+	return t.Bucket.AbortMultipartUpload(ctx, path, uploadID)
+}
+
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	if _, err := t.read(ctx, prefix); err != nil {
+		return nil, err
+	}
+	return t.Bucket.ListObjectVersions(ctx, prefix, opts...)
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	if _, err := t.read(ctx, ""); err != nil {
+		return "", err
+	}
+	return t.Bucket.GetVersioning(ctx)
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	if _, err := t.write(ctx, ""); err != nil {
+		return err
+	}
+	return t.Bucket.SetVersioning(ctx, status)
+}
+
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	if _, err := t.read(ctx, path); err != nil {
+		return nil, err
+	}
+	return t.Bucket.GetObjectTags(ctx, path)
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tags map[string]string) error {
+	if _, err := t.write(ctx, path); err != nil {
+		return err
+	}
+	return t.Bucket.PutObjectTags(ctx, path, tags)
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	if _, err := t.write(ctx, path); err != nil {
+		return err
+	}
+	return t.Bucket.DeleteObjectTags(ctx, path)
+}
+
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	if _, err := t.write(ctx, path); err != nil {
+		return err
+	}
+	return t.Bucket.SetStorageClass(ctx, path, class)
+}
+
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	if _, err := t.write(ctx, path); err != nil {
+		return err
+	}
+	return t.Bucket.RestoreObject(ctx, path, days, tier)
+}
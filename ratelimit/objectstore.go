@@ -0,0 +1,21 @@
+package ratelimit
+
+import (
+	"github.com/burybell/osi"
+)
+
+type objectStore struct {
+	osi.ObjectStore
+	limiters *limiters
+}
+
+// NewObjectStore wraps store so that the calls on its buckets are rate
+// limited: by limits shared by all buckets, or by limits of their own with
+// WithPerBucket.
+func NewObjectStore(store osi.ObjectStore, opts ...Option) osi.ObjectStore {
+	return &objectStore{ObjectStore: store, limiters: newLimiters(NewOptions(opts...))}
+}
+
+func (t *objectStore) Bucket(name string) osi.Bucket {
+	return &bucket{Bucket: t.ObjectStore.Bucket(name), name: name, limiters: t.limiters}
+}
@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"golang.org/x/time/rate"
+	"strings"
+	"sync"
+)
+
+const Name = "ratelimit"
+
+// Limit bounds the calls per second and the body bytes per second. Zero
+// leaves either unbounded.
+type Limit struct {
+	Ops   float64
+	Bytes float64
+}
+
+type Options struct {
+	Read  Limit
+	Write Limit
+	// PerBucket gives every bucket of a store its own limits instead of one
+	// set shared by all.
+	PerBucket bool
+	// Key maps the path of a call, or the prefix of a listing, onto the key
+	// whose limits it counts against. Nil shares the limits across paths.
+	Key func(path string) string
+}
+
+type Option func(opts *Options)
+
+// WithReadLimit bounds gets, heads and listings.
+func WithReadLimit(ops float64, bytes float64) Option {
+	return func(opts *Options) {
+		opts.Read = Limit{Ops: ops, Bytes: bytes}
+	}
+}
+
+// WithWriteLimit bounds puts, copies, deletes and the other calls that
+// change objects.
+func WithWriteLimit(ops float64, bytes float64) Option {
+	return func(opts *Options) {
+		opts.Write = Limit{Ops: ops, Bytes: bytes}
+	}
+}
+
+func WithPerBucket() Option {
+	return func(opts *Options) {
+		opts.PerBucket = true
+	}
+}
+
+// WithKey keys the limits by key, as in WithKey(PrefixKey(1)) for limits per
+// top-level directory. Every key seen keeps its limits for the life of the
+// wrapper, so key should map onto a bounded set.
+func WithKey(key func(path string) string) Option {
+	return func(opts *Options) {
+		opts.Key = key
+	}
+}
+
+func NewOptions(opts ...Option) *Options {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// PrefixKey returns a Key mapping paths onto their first depth directories.
+func PrefixKey(depth int) func(path string) string {
+	return func(path string) string {
+		parts := strings.SplitN(path, "/", depth+1)
+		if len(parts) <= depth {
+			return strings.Join(parts[:len(parts)-1], "/")
+		}
+		return strings.Join(parts[:depth], "/")
+	}
+}
+
+// NewLimiter returns a limiter of limit events per second, allowing bursts of
+// a second's worth. A limit of zero is unbounded.
+func NewLimiter(limit float64) *rate.Limiter {
+	if limit <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	burst := int(limit)
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(limit), burst)
+}
+
+type limits struct {
+	readOps    *rate.Limiter
+	readBytes  *rate.Limiter
+	writeOps   *rate.Limiter
+	writeBytes *rate.Limiter
+}
+
+// limiters holds the limits of every key.
+type limiters struct {
+	options *Options
+	mu      sync.Mutex
+	limits  map[string]*limits
+}
+
+func newLimiters(options *Options) *limiters {
+	return &limiters{options: options, limits: make(map[string]*limits)}
+}
+
+func (t *limiters) get(bucket string, path string) *limits {
+	var key string
+	if t.options.Key != nil {
+		key = t.options.Key(path)
+	}
+	if t.options.PerBucket {
+		key = bucket + "/" + key
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l, ok := t.limits[key]
+	if !ok {
+		l = &limits{
+			readOps:    NewLimiter(t.options.Read.Ops),
+			readBytes:  NewLimiter(t.options.Read.Bytes),
+			writeOps:   NewLimiter(t.options.Write.Ops),
+			writeBytes: NewLimiter(t.options.Write.Bytes),
+		}
+		t.limits[key] = l
+	}
+	return l
+}
+
+// wait waits until limiter allows n events, in steps of its burst. Waits the
+// deadline of ctx does not leave time for fail with osi.Throttled at once.
+func wait(ctx context.Context, limiter *rate.Limiter, n int) error {
+	if limiter.Limit() == rate.Inf {
+		return nil
+	}
+	for n > 0 {
+		step := n
+		if burst := limiter.Burst(); step > burst {
+			step = burst
+		}
+		if err := limiter.WaitN(ctx, step); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return &osi.Error{Kind: osi.Throttled, Provider: Name, Message: "rate limit wait exceeds the deadline", Err: err}
+		}
+		n -= step
+	}
+	return nil
+}
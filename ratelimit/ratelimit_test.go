@@ -0,0 +1,173 @@
+package ratelimit_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/local"
+	"github.com/burybell/osi/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	ctx   = context.Background()
+	store osi.ObjectStore
+	plain osi.Bucket
+)
+
+func init() {
+	dir, err := os.MkdirTemp("", "osi-ratelimit-")
+	if err != nil {
+		panic(err)
+	}
+	store = local.MustNewObjectStore(local.Config{BasePath: dir})
+	for _, name := range []string{"example", "other"} {
+		if err := store.CreateBucket(ctx, name); err != nil {
+			panic(err)
+		}
+	}
+	plain = store.Bucket("example")
+}
+
+func elapsed(fn func()) time.Duration {
+	start := time.Now()
+	fn()
+	return time.Since(start)
+}
+
+func TestBucket_Ops(t *testing.T) {
+	bucket := ratelimit.NewBucket(plain, "example", ratelimit.WithReadLimit(20, 0))
+	// a second's worth passes at once, the rest at the rate
+	d := elapsed(func() {
+		for i := 0; i < 30; i++ {
+			_, err := bucket.HeadObject(ctx, "test/missing.txt")
+			assert.NoError(t, err)
+		}
+	})
+	assert.Greater(t, d, 400*time.Millisecond)
+	assert.Less(t, d, 2*time.Second)
+
+	// writes are limited apart from reads
+	d = elapsed(func() {
+		assert.NoError(t, bucket.PutObject(ctx, "test/ops.txt", strings.NewReader("some text")))
+		assert.NoError(t, bucket.DeleteObject(ctx, "test/ops.txt"))
+	})
+	assert.Less(t, d, 100*time.Millisecond)
+}
+
+func TestBucket_Bytes(t *testing.T) {
+	bucket := ratelimit.NewBucket(plain, "example", ratelimit.WithWriteLimit(0, 4096), ratelimit.WithReadLimit(0, 4096))
+	content := bytes.Repeat([]byte("a"), 6144)
+	d := elapsed(func() {
+		assert.NoError(t, bucket.PutObject(ctx, "test/bytes.txt", bytes.NewReader(content)))
+	})
+	defer plain.DeleteObject(ctx, "test/bytes.txt")
+	assert.Greater(t, d, 400*time.Millisecond)
+
+	object, err := bucket.GetObject(ctx, "test/bytes.txt")
+	assert.NoError(t, err)
+	var bs []byte
+	d = elapsed(func() {
+		bs, err = io.ReadAll(object)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, content, bs)
+	assert.Greater(t, d, 400*time.Millisecond)
+	_ = object.Close()
+}
+
+func TestWithKey(t *testing.T) {
+	// every prefix has a burst of its own
+	bucket := ratelimit.NewBucket(plain, "example", ratelimit.WithReadLimit(5, 0), ratelimit.WithKey(ratelimit.PrefixKey(1)))
+	d := elapsed(func() {
+		for _, prefix := range []string{"a", "b", "c"} {
+			for i := 0; i < 5; i++ {
+				_, err := bucket.HeadObject(ctx, prefix+"/missing.txt")
+				assert.NoError(t, err)
+			}
+		}
+	})
+	assert.Less(t, d, 100*time.Millisecond)
+
+	assert.Equal(t, "a", ratelimit.PrefixKey(1)("a/b/c.txt"))
+	assert.Equal(t, "a/b", ratelimit.PrefixKey(2)("a/b/c.txt"))
+	assert.Equal(t, "a", ratelimit.PrefixKey(2)("a/c.txt"))
+	assert.Equal(t, "", ratelimit.PrefixKey(1)("c.txt"))
+
+	// buckets share the limits of a store unless limited per bucket
+	for _, perBucket := range []bool{false, true} {
+		opts := []ratelimit.Option{ratelimit.WithReadLimit(5, 0)}
+		if perBucket {
+			opts = append(opts, ratelimit.WithPerBucket())
+		}
+		limited := ratelimit.NewObjectStore(store, opts...)
+		d = elapsed(func() {
+			for _, name := range []string{"example", "other"} {
+				for i := 0; i < 5; i++ {
+					_, err := limited.Bucket(name).HeadObject(ctx, "test/missing.txt")
+					assert.NoError(t, err)
+				}
+			}
+		})
+		assert.Equal(t, perBucket, d < 100*time.Millisecond, "per bucket %v took %s", perBucket, d)
+	}
+
+	// a bucket wrapped alone keys its limits by its name and the prefix
+	bucket = ratelimit.NewBucket(plain, "example", ratelimit.WithReadLimit(5, 0), ratelimit.WithPerBucket(), ratelimit.WithKey(ratelimit.PrefixKey(1)))
+	d = elapsed(func() {
+		for _, prefix := range []string{"a", "b"} {
+			for i := 0; i < 5; i++ {
+				_, err := bucket.HeadObject(ctx, prefix+"/missing.txt")
+				assert.NoError(t, err)
+			}
+		}
+	})
+	assert.Less(t, d, 100*time.Millisecond)
+	d = elapsed(func() {
+		_, err := bucket.HeadObject(ctx, "a/missing.txt")
+		assert.NoError(t, err)
+	})
+	assert.Greater(t, d, 100*time.Millisecond)
+}
+
+func TestBucket_Context(t *testing.T) {
+	bucket := ratelimit.NewBucket(plain, "example", ratelimit.WithReadLimit(1, 0))
+	_, err := bucket.HeadObject(ctx, "test/missing.txt")
+	assert.NoError(t, err)
+
+	// a wait the deadline leaves no time for fails at once
+	deadlineCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	d := elapsed(func() {
+		_, err = bucket.HeadObject(deadlineCtx, "test/missing.txt")
+	})
+	assert.ErrorIs(t, err, osi.Throttled)
+	assert.Less(t, d, 50*time.Millisecond)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = bucket.HeadObject(cancelCtx, "test/missing.txt")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := ratelimit.NewWriter(ctx, &buf, ratelimit.NewLimiter(1024))
+	d := elapsed(func() {
+		n, err := w.Write(bytes.Repeat([]byte("a"), 1536))
+		assert.NoError(t, err)
+		assert.Equal(t, 1536, n)
+	})
+	assert.Equal(t, 1536, buf.Len())
+	assert.Greater(t, d, 400*time.Millisecond)
+
+	// bodies keep telling their size
+	size, ok := osi.ReaderSize(ratelimit.NewReader(ctx, strings.NewReader("some text"), ratelimit.NewLimiter(1024)))
+	assert.True(t, ok)
+	assert.Equal(t, int64(9), size)
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"golang.org/x/time/rate"
+	"io"
+)
+
+type reader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *rate.Limiter
+}
+
+// NewReader throttles the reads from r to the rate of limiter, waiting as
+// long as ctx allows. Readers that can seek or tell their length keep doing
+// so, for osi.ReaderSize and retries.
+func NewReader(ctx context.Context, r io.Reader, limiter *rate.Limiter) io.Reader {
+	if limiter.Limit() == rate.Inf {
+		return r
+	}
+	throttled := &reader{ctx: ctx, reader: r, limiter: limiter}
+	switch r := r.(type) {
+	case io.Seeker:
+		return &readSeeker{reader: throttled, seeker: r}
+	case interface{ Len() int }:
+		return &lenReader{reader: throttled, len: r}
+	}
+	return throttled
+}
+
+func (t *reader) Read(p []byte) (int, error) {
+	if burst := t.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := t.reader.Read(p)
+	if n > 0 {
+		if waitErr := wait(t.ctx, t.limiter, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+type readSeeker struct {
+	*reader
+	seeker io.Seeker
+}
+
+func (t *readSeeker) Seek(offset int64, whence int) (int64, error) {
+	return t.seeker.Seek(offset, whence)
+}
+
+type lenReader struct {
+	*reader
+	len interface{ Len() int }
+}
+
+func (t *lenReader) Len() int {
+	return t.len.Len()
+}
+
+type writer struct {
+	ctx     context.Context
+	writer  io.Writer
+	limiter *rate.Limiter
+}
+
+// NewWriter throttles the writes to w to the rate of limiter, waiting as long
+// as ctx allows.
+func NewWriter(ctx context.Context, w io.Writer, limiter *rate.Limiter) io.Writer {
+	if limiter.Limit() == rate.Inf {
+		return w
+	}
+	return &writer{ctx: ctx, writer: w, limiter: limiter}
+}
+
+func (t *writer) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		step := p
+		if burst := t.limiter.Burst(); len(step) > burst {
+			step = step[:burst]
+		}
+		if err := wait(t.ctx, t.limiter, len(step)); err != nil {
+			return written, err
+		}
+		n, err := t.writer.Write(step)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
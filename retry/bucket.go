@@ -0,0 +1,216 @@
+package retry
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"io"
+)
+
+// bucket retries the calls of the bucket it wraps that fail with a retryable
+// error, waiting with exponential backoff between attempts. Writes are
+// retried only while their body can be rewound: bodies that implement
+// io.Seeker are sought back to where the first attempt started reading, and
+// others fail with NotRewindable rather than being sent again incomplete.
+//
+// A write that succeeded but whose response was lost is sent again, so a
+// retried conditional put may fail with PreconditionFailed and a retried
+// CompleteMultipartUpload with UploadNotFound. Reads are retried until the
+// object is returned; failures while reading its body are left to the
+// caller, as osi.Download does with its part retries. Presigning makes no
+// requests and is passed through.
+type bucket struct {
+	osi.Bucket
+	options *Options
+}
+
+// NewBucket wraps bkt so that its calls are retried.
+func NewBucket(bkt osi.Bucket, opts ...Option) osi.Bucket {
+	return &bucket{Bucket: bkt, options: NewOptions(opts...)}
+}
+
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	return t.open(ctx, func(ctx context.Context) (osi.Object, error) {
+		return t.Bucket.GetObject(ctx, path, opts...)
+	})
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	return t.open(ctx, func(ctx context.Context) (osi.Object, error) {
+		return t.Bucket.GetObjectRange(ctx, path, rng, opts...)
+	})
+}
+
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	return t.do(ctx, rewinder(reader), func(ctx context.Context) error {
+		return t.Bucket.PutObject(ctx, path, reader, opts...)
+	})
+}
+
+func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
+}
+
+func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
+	var exists bool
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		exists, err = t.Bucket.HeadObject(ctx, path)
+		return err
+	})
+	return exists, err
+}
+
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	var info *osi.ObjectInfo
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		info, err = t.Bucket.StatObject(ctx, path, opts...)
+		return err
+	})
+	return info, err
+}
+
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.DeleteObject(ctx, path, opts...)
+	})
+}
+
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.CopyObject(ctx, src, dst, opts...)
+	})
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.MoveObject(ctx, src, dst, opts...)
+	})
+}
+
+func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
+	var size osi.Size
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		size, err = t.Bucket.GetObjectSize(ctx, path)
+		return err
+	})
+	return size, err
+}
+
+func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
+	var objects []osi.ObjectMeta
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		objects, err = t.Bucket.ListObjects(ctx, prefix)
+		return err
+	})
+	return objects, err
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	var page *osi.ObjectPage
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		page, err = t.Bucket.ListObjectsPage(ctx, prefix, opts...)
+		return err
+	})
+	return page, err
+}
+
+func (t *bucket) DeleteObjects(ctx context.Context, paths []string) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.DeleteObjects(ctx, paths)
+	})
+}
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	var uploadID string
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		uploadID, err = t.Bucket.InitiateMultipartUpload(ctx, path, opts...)
+		return err
+	})
+	return uploadID, err
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	var part osi.Part
+	err := t.do(ctx, rewinder(reader), func(ctx context.Context) (err error) {
+		part, err = t.Bucket.UploadPart(ctx, path, uploadID, partNumber, reader, size, opts...)
+		return err
+	})
+	return part, err
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	var parts []osi.Part
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		parts, err = t.Bucket.ListParts(ctx, path, uploadID)
+		return err
+	})
+	return parts, err
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.CompleteMultipartUpload(ctx, path, uploadID, parts, opts...)
+	})
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.AbortMultipartUpload(ctx, path, uploadID)
+	})
+}
+
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	var page *osi.VersionPage
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		page, err = t.Bucket.ListObjectVersions(ctx, prefix, opts...)
+		return err
+	})
+	return page, err
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	var status osi.VersioningStatus
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		status, err = t.Bucket.GetVersioning(ctx)
+		return err
+	})
+	return status, err
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.SetVersioning(ctx, status)
+	})
+}
+
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	var tags map[string]string
+	err := t.do(ctx, nil, func(ctx context.Context) (err error) {
+		tags, err = t.Bucket.GetObjectTags(ctx, path)
+		return err
+	})
+	return tags, err
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tags map[string]string) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.PutObjectTags(ctx, path, tags)
+	})
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.DeleteObjectTags(ctx, path)
+	})
+}
+
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.SetStorageClass(ctx, path, class)
+	})
+}
+
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	return t.do(ctx, nil, func(ctx context.Context) error {
+		return t.Bucket.RestoreObject(ctx, path, days, tier)
+	})
+}
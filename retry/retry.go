@@ -0,0 +1,238 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/burybell/osi"
+	"io"
+	"math/rand"
+	"time"
+)
+
+const (
+	Name = "retry"
+
+	DefaultMaxAttempts = 3
+	DefaultBaseDelay   = 100 * time.Millisecond
+	DefaultMaxDelay    = 5 * time.Second
+)
+
+// NotRewindable fails the retry of a write whose body cannot be read again.
+// The error it is matched on unwraps to the error of the last attempt.
+var NotRewindable = errors.New("NotRewindable")
+
+type Options struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// AttemptTimeout bounds every attempt, and for reads the time to the
+	// response only, not the reading of the body. Zero leaves attempts to
+	// the deadline of the context. It cuts an attempt short through its
+	// context, which the SDKs of some backends cannot take per request: an
+	// attempt against OBS, or against OSS to create, delete or list buckets
+	// or to read or set versioning, runs to the timeouts of the SDK.
+	AttemptTimeout time.Duration
+	Retryable      func(err error) bool
+	// Logger receives a warning for every retry.
+	Logger osi.Logger
+}
+
+type Option func(opts *Options)
+
+// WithMaxAttempts sets how often a call is made at most, the first
+// included.
+func WithMaxAttempts(maxAttempts int) Option {
+	return func(opts *Options) {
+		opts.MaxAttempts = maxAttempts
+	}
+}
+
+// WithBackoff waits a random duration of up to base before the second
+// attempt, doubling the bound with every further attempt up to max.
+func WithBackoff(base time.Duration, max time.Duration) Option {
+	return func(opts *Options) {
+		opts.BaseDelay = base
+		opts.MaxDelay = max
+	}
+}
+
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(opts *Options) {
+		opts.AttemptTimeout = timeout
+	}
+}
+
+// WithRetryable replaces Retryable in deciding which errors are retried.
+func WithRetryable(retryable func(err error) bool) Option {
+	return func(opts *Options) {
+		opts.Retryable = retryable
+	}
+}
+
+func WithLogger(logger osi.Logger) Option {
+	return func(opts *Options) {
+		opts.Logger = logger
+	}
+}
+
+func NewOptions(opts ...Option) *Options {
+	options := &Options{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+		Retryable:   Retryable,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	if options.MaxDelay < options.BaseDelay {
+		options.MaxDelay = options.BaseDelay
+	}
+	return options
+}
+
+// delay returns the wait after attempt failed: full jitter over an
+// exponentially growing bound.
+func (t *Options) delay(attempt int) time.Duration {
+	bound := t.MaxDelay
+	if shift := uint(attempt - 1); shift < 32 && t.BaseDelay<<shift < t.MaxDelay {
+		bound = t.BaseDelay << shift
+	}
+	if bound <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(bound) + 1))
+}
+
+// Retryable reports whether err is throttling, a transient provider error or
+// a broken connection. The backends map the first two onto osi.Throttled and
+// osi.Transient whatever their SDK; network errors that reach the caller
+// unmapped are classified with osi.TransportError. Cancellation and expired
+// deadlines of the caller are not retried.
+func Retryable(err error) bool {
+	err = osi.TransportError(Name, err)
+	return errors.Is(err, osi.Throttled) || errors.Is(err, osi.Transient)
+}
+
+// retry calls fn until it succeeds, fails with an error that is not
+// retryable, or MaxAttempts is reached. rewind, when set, prepares the body of
+// a write for every attempt but the first.
+func (t *bucket) retry(ctx context.Context, rewind func() error, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= t.options.MaxAttempts || !t.options.Retryable(err) {
+			return err
+		}
+		if rewind != nil {
+			if rewindErr := rewind(); rewindErr != nil {
+				return &rewindError{err: err, cause: rewindErr}
+			}
+		}
+		delay := t.options.delay(attempt)
+		osi.Log(ctx, t.options.Logger, osi.LogWarn, "retrying call", osi.Field("provider", Name), osi.Field("attempt", attempt+1), osi.Field("delay", delay), osi.Field("error", err))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// do retries fn, bounding every attempt by AttemptTimeout.
+func (t *bucket) do(ctx context.Context, rewind func() error, fn func(ctx context.Context) error) error {
+	return t.retry(ctx, rewind, func(ctx context.Context) error {
+		if t.options.AttemptTimeout <= 0 {
+			return fn(ctx)
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, t.options.AttemptTimeout)
+		defer cancel()
+		err := fn(attemptCtx)
+		if err != nil && attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			return t.timeoutError(err)
+		}
+		return err
+	})
+}
+
+// open retries get, bounding every attempt by AttemptTimeout until the
+// object is returned. The context of the object returned is released when it
+// is closed.
+func (t *bucket) open(ctx context.Context, get func(ctx context.Context) (osi.Object, error)) (osi.Object, error) {
+	var object osi.Object
+	err := t.retry(ctx, nil, func(ctx context.Context) error {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		var timer *time.Timer
+		if t.options.AttemptTimeout > 0 {
+			timer = time.AfterFunc(t.options.AttemptTimeout, cancel)
+		}
+		opened, err := get(attemptCtx)
+		if timer != nil && !timer.Stop() && ctx.Err() == nil {
+			if err == nil {
+				_ = opened.Close()
+			}
+			cancel()
+			return t.timeoutError(err)
+		}
+		if err != nil {
+			cancel()
+			return err
+		}
+		object = &cancelObject{Object: opened, cancel: cancel}
+		return nil
+	})
+	return object, err
+}
+
+func (t *bucket) timeoutError(err error) error {
+	return &osi.Error{Kind: osi.Transient, Provider: Name, Message: fmt.Sprintf("attempt timed out after %s", t.options.AttemptTimeout), Err: err}
+}
+
+// rewinder returns a func seeking reader back to where it is now. Readers
+// that cannot seek cannot be rewound.
+func rewinder(reader io.Reader) func() error {
+	if seeker, ok := reader.(io.Seeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			return func() error {
+				_, err := seeker.Seek(offset, io.SeekStart)
+				return err
+			}
+		}
+	}
+	return func() error {
+		return errors.New("body is not seekable")
+	}
+}
+
+type rewindError struct {
+	err   error
+	cause error
+}
+
+func (t *rewindError) Error() string {
+	return fmt.Sprintf("%v (not retried: %v)", t.err, t.cause)
+}
+
+func (t *rewindError) Is(target error) bool {
+	return target == NotRewindable
+}
+
+func (t *rewindError) Unwrap() error {
+	return t.err
+}
+
+type cancelObject struct {
+	osi.Object
+	cancel context.CancelFunc
+}
+
+func (t *cancelObject) Close() error {
+	err := t.Object.Close()
+	t.cancel()
+	return err
+}
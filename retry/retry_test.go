@@ -0,0 +1,189 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/local"
+	"github.com/burybell/osi/retry"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var (
+	ctx   = context.Background()
+	plain osi.Bucket
+)
+
+func init() {
+	dir, err := os.MkdirTemp("", "osi-retry-")
+	if err != nil {
+		panic(err)
+	}
+	store := local.MustNewObjectStore(local.Config{BasePath: dir})
+	if err := store.CreateBucket(ctx, "example"); err != nil {
+		panic(err)
+	}
+	plain = store.Bucket("example")
+}
+
+// flakyBucket fails its first failures puts, gets and stats with err, after
+// a delay when set. Failed puts read their body first.
+type flakyBucket struct {
+	osi.Bucket
+	failures int
+	err      error
+	calls    int
+	delay    time.Duration
+}
+
+func (t *flakyBucket) fail(ctx context.Context) error {
+	t.calls++
+	if t.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(t.delay):
+		}
+	}
+	if t.calls <= t.failures {
+		return t.err
+	}
+	return nil
+}
+
+func (t *flakyBucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	if err := t.fail(ctx); err != nil {
+		_, _ = io.Copy(io.Discard, reader)
+		return err
+	}
+	return t.Bucket.PutObject(ctx, path, reader, opts...)
+}
+
+func (t *flakyBucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	if err := t.fail(ctx); err != nil {
+		return nil, err
+	}
+	return t.Bucket.GetObject(ctx, path, opts...)
+}
+
+func (t *flakyBucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	if err := t.fail(ctx); err != nil {
+		return nil, err
+	}
+	return t.Bucket.StatObject(ctx, path, opts...)
+}
+
+var transient = &osi.Error{Kind: osi.Transient, Provider: "test", Code: "InternalError"}
+
+func newBucket(flaky *flakyBucket, opts ...retry.Option) osi.Bucket {
+	return retry.NewBucket(flaky, append([]retry.Option{retry.WithBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)...)
+}
+
+func TestBucket_PutObject(t *testing.T) {
+	flaky := &flakyBucket{Bucket: plain, failures: 2, err: transient}
+	bucket := newBucket(flaky)
+	assert.NoError(t, bucket.PutObject(ctx, "test/put.txt", strings.NewReader("some text")))
+	defer plain.DeleteObject(ctx, "test/put.txt")
+	assert.Equal(t, 3, flaky.calls)
+	object, err := plain.GetObject(ctx, "test/put.txt")
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "some text", string(bs))
+	_ = object.Close()
+
+	// a body read from the middle is sent again from where it started
+	flaky = &flakyBucket{Bucket: plain, failures: 1, err: transient}
+	reader := strings.NewReader("some text")
+	_, _ = reader.Seek(5, io.SeekStart)
+	assert.NoError(t, newBucket(flaky).PutObject(ctx, "test/put.txt", reader))
+	size, err := plain.GetObjectSize(ctx, "test/put.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), size.Size())
+
+	flaky = &flakyBucket{Bucket: plain, failures: 1, err: transient}
+	err = newBucket(flaky).PutObject(ctx, "test/put.txt", io.NopCloser(strings.NewReader("some text")))
+	assert.ErrorIs(t, err, retry.NotRewindable)
+	assert.ErrorIs(t, err, osi.Transient)
+	assert.Equal(t, 1, flaky.calls)
+}
+
+func TestBucket_Attempts(t *testing.T) {
+	flaky := &flakyBucket{Bucket: plain, failures: 5, err: transient}
+	_, err := newBucket(flaky, retry.WithMaxAttempts(4)).StatObject(ctx, "test/missing.txt")
+	assert.ErrorIs(t, err, osi.Transient)
+	assert.Equal(t, 4, flaky.calls)
+
+	// errors that are not retryable are returned at once
+	flaky = &flakyBucket{Bucket: plain}
+	_, err = newBucket(flaky).StatObject(ctx, "test/missing.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	assert.Equal(t, 1, flaky.calls)
+
+	flaky = &flakyBucket{Bucket: plain, failures: 5, err: transient}
+	_, err = newBucket(flaky, retry.WithRetryable(func(err error) bool { return false })).StatObject(ctx, "test/missing.txt")
+	assert.ErrorIs(t, err, osi.Transient)
+	assert.Equal(t, 1, flaky.calls)
+
+	// the caller's cancellation ends the backoff
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	flaky = &flakyBucket{Bucket: plain, failures: 5, err: transient}
+	_, err = retry.NewBucket(flaky, retry.WithBackoff(time.Hour, time.Hour)).StatObject(cancelCtx, "test/missing.txt")
+	assert.ErrorIs(t, err, osi.Transient)
+	assert.Equal(t, 1, flaky.calls)
+}
+
+func TestBucket_AttemptTimeout(t *testing.T) {
+	assert.NoError(t, plain.PutObject(ctx, "test/timeout.txt", strings.NewReader("some text")))
+	defer plain.DeleteObject(ctx, "test/timeout.txt")
+
+	flaky := &flakyBucket{Bucket: plain, delay: 50 * time.Millisecond}
+	_, err := newBucket(flaky, retry.WithAttemptTimeout(10*time.Millisecond)).StatObject(ctx, "test/timeout.txt")
+	assert.ErrorIs(t, err, osi.Transient)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, retry.DefaultMaxAttempts, flaky.calls)
+
+	// the timeout bounds the time to the response, not the reading of the body
+	flaky = &flakyBucket{Bucket: plain, delay: 5 * time.Millisecond}
+	object, err := newBucket(flaky, retry.WithAttemptTimeout(20*time.Millisecond)).GetObject(ctx, "test/timeout.txt")
+	assert.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.Equal(t, "some text", string(bs))
+	assert.NoError(t, object.Close())
+}
+
+func TestRetryable(t *testing.T) {
+	assert.True(t, retry.Retryable(transient))
+	assert.True(t, retry.Retryable(osi.NewError("s3", "SlowDown", 503, "", "", nil)))
+	assert.True(t, retry.Retryable(&net.OpError{Op: "read", Err: syscall.ECONNRESET}))
+	assert.True(t, retry.Retryable(io.ErrUnexpectedEOF))
+	assert.False(t, retry.Retryable(osi.NewError("s3", "NoSuchKey", 404, "", "", nil)))
+	assert.False(t, retry.Retryable(context.Canceled))
+	assert.False(t, retry.Retryable(errors.New("invalid argument")))
+}
+
+type logger struct {
+	msgs []string
+}
+
+func (t *logger) Log(ctx context.Context, level osi.LogLevel, msg string, fields ...osi.LogField) {
+	t.msgs = append(t.msgs, level.String()+" "+msg)
+}
+
+func TestWithLogger(t *testing.T) {
+	log := &logger{}
+	flaky := &flakyBucket{Bucket: plain, failures: 2, err: transient}
+	_, err := newBucket(flaky, retry.WithLogger(log)).StatObject(ctx, "test/missing.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	assert.Equal(t, []string{"warn retrying call", "warn retrying call"}, log.msgs)
+}
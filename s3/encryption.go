@@ -0,0 +1,28 @@
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/burybell/osi"
+)
+
+// serverSideEncryption returns the x-amz-server-side-encryption value of the
+// managed key modes.
+func serverSideEncryption(mode osi.EncryptionMode) *string {
+	switch mode {
+	case osi.EncryptionSSES3:
+		return aws.String(s3.ServerSideEncryptionAes256)
+	case osi.EncryptionSSEKMS:
+		return aws.String(s3.ServerSideEncryptionAwsKms)
+	}
+	return nil
+}
+
+// customerKey returns the SSE-C algorithm and key, which aws-sdk-go encodes
+// and digests itself.
+func customerKey(key []byte) (*string, *string) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	return aws.String(s3.ServerSideEncryptionAes256), aws.String(string(key))
+}
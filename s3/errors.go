@@ -0,0 +1,74 @@
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/burybell/osi"
+)
+
+// toError maps an aws-sdk-go error onto the osi error taxonomy.
+func toError(err error) error {
+	switch aerr := err.(type) {
+	case nil:
+		return nil
+	case awserr.RequestFailure:
+		return osi.NewError(Name, aerr.Code(), aerr.StatusCode(), aerr.RequestID(), aerr.Message(), err)
+	case awserr.Error:
+		if failure := requestFailure(aerr); failure != nil {
+			return osi.NewError(Name, failure.Code(), failure.StatusCode(), failure.RequestID(), failure.Message(), err)
+		}
+		switch aerr.Code() {
+		case request.CanceledErrorCode:
+			return err
+		case request.ErrCodeRequestError, request.ErrCodeResponseTimeout, request.ErrCodeRead, request.ErrCodeSerialization:
+			return &osi.Error{Kind: osi.Transient, Provider: Name, Code: aerr.Code(), Message: aerr.Message(), Err: err}
+		}
+		return err
+	}
+	return osi.TransportError(Name, err)
+}
+
+// requestFailure returns the response error err wraps, such as the failed
+// request behind an s3manager.MultiUploadFailure or an awserr.BatchedErrors,
+// or nil when there is none.
+func requestFailure(err awserr.Error) awserr.RequestFailure {
+	origErrs := []error{err.OrigErr()}
+	if batched, ok := err.(awserr.BatchedErrors); ok {
+		origErrs = batched.OrigErrs()
+	}
+	for _, origErr := range origErrs {
+		switch aerr := origErr.(type) {
+		case awserr.RequestFailure:
+			return aerr
+		case awserr.Error:
+			if failure := requestFailure(aerr); failure != nil {
+				return failure
+			}
+		}
+	}
+	return nil
+}
+
+// checkWriteConditions rejects the conditions S3 does not evaluate on writes
+// rather than letting it ignore them.
+func checkWriteConditions(conditions osi.Conditions) error {
+	if !conditions.IfModifiedSince.IsZero() || !conditions.IfUnmodifiedSince.IsZero() {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional put supports IfMatch and IfNoneMatch only"}
+	}
+	return nil
+}
+
+// withConditions sends conditions as headers with the requests of the named
+// operations, which aws-sdk-go models without them.
+func withConditions(conditions osi.Conditions, operations ...string) request.Option {
+	header := conditions.Header()
+	return func(r *request.Request) {
+		for _, operation := range operations {
+			if r.Operation.Name == operation {
+				for key := range header {
+					r.HTTPRequest.Header.Set(key, header.Get(key))
+				}
+			}
+		}
+	}
+}
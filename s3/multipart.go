@@ -0,0 +1,139 @@
+package s3
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/burybell/osi"
+	"io"
+	"sort"
+)
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	options := osi.NewPutOptions(path, opts...)
+	if err := osi.ValidateTags(options.Tags); err != nil {
+		return "", err
+	}
+	if err := options.Encryption.Validate(); err != nil {
+		return "", err
+	}
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:               &t.bucket,
+		Key:                  &path,
+		Metadata:             aws.StringMap(options.Metadata),
+		ServerSideEncryption: serverSideEncryption(options.Encryption.Mode),
+		SSEKMSKeyId:          optionalString(options.Encryption.KMSKeyID),
+	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = customerKey(options.Encryption.CustomerKey)
+	if options.ACL != "" {
+		input.ACL = aws.String(options.ACL)
+	}
+	if options.ContentType != "" {
+		input.ContentType = aws.String(options.ContentType)
+	}
+	if options.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(options.ContentEncoding)
+	}
+	if options.CacheControl != "" {
+		input.CacheControl = aws.String(options.CacheControl)
+	}
+	if options.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(options.ContentDisposition)
+	}
+	if !options.Expires.IsZero() {
+		input.Expires = aws.Time(options.Expires)
+	}
+	if len(options.Tags) > 0 {
+		input.Tagging = aws.String(osi.EncodeTags(options.Tags))
+	}
+	if options.StorageClass != "" {
+		input.StorageClass = aws.String(storageClasses.Provider(options.StorageClass))
+	}
+	upload, err := t.client.CreateMultipartUploadWithContext(ctx, input)
+	if err != nil {
+		return "", toError(err)
+	}
+	return aws.StringValue(upload.UploadId), nil
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	if err := osi.ValidatePartNumber(partNumber); err != nil {
+		return osi.Part{}, err
+	}
+	body, ok := reader.(io.ReadSeeker)
+	if !ok {
+		body = aws.ReadSeekCloser(reader)
+	}
+	input := &s3.UploadPartInput{
+		Bucket:        &t.bucket,
+		Key:           &path,
+		UploadId:      &uploadID,
+		PartNumber:    aws.Int64(int64(partNumber)),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = customerKey(osi.NewPutOptions(path, opts...).Encryption.CustomerKey)
+	resp, err := t.client.UploadPartWithContext(ctx, input)
+	if err != nil {
+		return osi.Part{}, toError(err)
+	}
+	return osi.Part{PartNumber: partNumber, ETag: osi.TrimETag(aws.StringValue(resp.ETag)), Size: size}, nil
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	var parts = make([]osi.Part, 0)
+	err := t.client.ListPartsPagesWithContext(ctx, &s3.ListPartsInput{
+		Bucket:   &t.bucket,
+		Key:      &path,
+		UploadId: &uploadID,
+	}, func(output *s3.ListPartsOutput, lastPage bool) bool {
+		for _, part := range output.Parts {
+			parts = append(parts, osi.Part{
+				PartNumber:   int(aws.Int64Value(part.PartNumber)),
+				ETag:         osi.TrimETag(aws.StringValue(part.ETag)),
+				Size:         aws.Int64Value(part.Size),
+				LastModified: aws.TimeValue(part.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, toError(err)
+	}
+	return parts, nil
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	if err := checkWriteConditions(options.Conditions); err != nil {
+		return err
+	}
+	var requestOptions []request.Option
+	if !options.Conditions.IsZero() {
+		requestOptions = append(requestOptions, withConditions(options.Conditions, "CompleteMultipartUpload"))
+	}
+	var completed = make([]*s3.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, &s3.CompletedPart{ETag: aws.String(part.ETag), PartNumber: aws.Int64(int64(part.PartNumber))})
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return *completed[i].PartNumber < *completed[j].PartNumber
+	})
+	_, err := t.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          &t.bucket,
+		Key:             &path,
+		UploadId:        &uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	}, requestOptions...)
+	return toError(err)
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	_, err := t.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   &t.bucket,
+		Key:      &path,
+		UploadId: &uploadID,
+	})
+	return toError(err)
+}
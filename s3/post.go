@@ -0,0 +1,68 @@
+package s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/burybell/osi"
+	"net/url"
+	"time"
+)
+
+// SignPostPolicy signs the policy with Signature Version 4, which aws-sdk-go
+// implements for requests only.
+func (t *bucket) SignPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	form, err := t.signPostPolicy(ctx, policy, expiredInDur)
+	osi.LogSignPostPolicy(ctx, t.config.Logger, Name, t.bucket, policy, expiredInDur, form, err)
+	return form, err
+}
+
+func (t *bucket) signPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	creds, err := t.client.Config.Credentials.GetWithContext(ctx)
+	if err != nil {
+		return nil, toError(err)
+	}
+	endpoint, err := url.Parse(t.client.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Host = t.bucket + "." + endpoint.Host
+	endpoint.Path = "/"
+
+	now := time.Now().UTC()
+	date := now.Format("20060102")
+	region := aws.StringValue(t.client.Config.Region)
+	fields := policy.FormFields("acl")
+	fields["x-amz-algorithm"] = "AWS4-HMAC-SHA256"
+	fields["x-amz-credential"] = fmt.Sprintf("%s/%s/%s/s3/aws4_request", creds.AccessKeyID, date, region)
+	fields["x-amz-date"] = now.Format("20060102T150405Z")
+	if creds.SessionToken != "" {
+		fields["x-amz-security-token"] = creds.SessionToken
+	}
+	document, err := policy.Document(t.bucket, now.Add(expiredInDur), fields)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(document)
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, scope := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, scope)
+	}
+	fields["policy"] = encoded
+	fields["x-amz-signature"] = hex.EncodeToString(hmacSHA256(key, encoded))
+	return &osi.PostForm{URL: endpoint.String(), Fields: fields}, nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -4,17 +4,15 @@ import (
 	"context"
 	"errors"
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/burybell/osi"
 	"io"
-	"mime"
 	"net/http"
-	"os"
-	"path/filepath"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -27,19 +25,40 @@ type Config struct {
 	Region string `yaml:"region" mapstructure:"region" json:"region"`
 	KeyID  string `yaml:"key_id" mapstructure:"key_id" json:"key_id"`
 	Secret string `yaml:"secret" mapstructure:"secret" json:"secret"`
+	// Logger receives the requests sent, their retries and the URLs presigned.
+	Logger osi.Logger `yaml:"-" mapstructure:"-" json:"-"`
 }
 
 type ObjectStore struct {
-	config Config
-	client *s3.S3
+	config  Config
+	session *session.Session
+	client  *s3.S3
 }
 
 func NewObjectStore(config Config) (osi.ObjectStore, error) {
-	provider, err := session.NewSession(aws.NewConfig().WithRegion(config.Region).WithCredentials(credentials.NewStaticCredentials(config.KeyID, config.Secret, "")))
+	awsConfig := aws.NewConfig().WithRegion(config.Region).WithCredentials(credentials.NewStaticCredentials(config.KeyID, config.Secret, ""))
+	if config.Logger != nil {
+		awsConfig = awsConfig.WithHTTPClient(&http.Client{Transport: osi.NewLoggingTransport(Name, config.Logger, nil)})
+	}
+	provider, err := session.NewSession(awsConfig)
 	if err != nil {
 		return nil, err
 	}
-	return &ObjectStore{config: config, client: s3.New(provider)}, nil
+	if config.Logger != nil {
+		provider.Handlers.AfterRetry.PushBack(logRetry(config.Logger))
+	}
+	return &ObjectStore{config: config, session: provider, client: s3.New(provider)}, nil
+}
+
+// logRetry logs the retries of the SDK. It follows its retry handler, which
+// clears the error of the requests it retries.
+func logRetry(logger osi.Logger) func(r *request.Request) {
+	return func(r *request.Request) {
+		if r.Error != nil {
+			return
+		}
+		osi.Log(r.Context(), logger, osi.LogWarn, "retrying request", osi.Field("provider", Name), osi.Field("operation", r.Operation.Name), osi.Field("attempt", r.RetryCount+1), osi.Field("delay", r.RetryDelay))
+	}
 }
 
 func MustNewObjectStore(config Config) osi.ObjectStore {
@@ -62,6 +81,58 @@ func (t *ObjectStore) Bucket(name string) osi.Bucket {
 	}
 }
 
+// CreateBucket sends the request to the region the bucket is created in,
+// since regional endpoints reject other location constraints.
+func (t *ObjectStore) CreateBucket(ctx context.Context, name string, opts ...osi.BucketOption) error {
+	options := osi.NewBucketOptions(t.config.Region, opts...)
+	input := &s3.CreateBucketInput{Bucket: aws.String(name)}
+	if options.ACL != "" {
+		input.ACL = aws.String(options.ACL)
+	}
+	client := t.client
+	if options.Region != t.config.Region {
+		client = s3.New(t.session, aws.NewConfig().WithRegion(options.Region))
+	}
+	// us-east-1 is the default location and must not be sent as constraint
+	if options.Region != "" && options.Region != "us-east-1" {
+		input.CreateBucketConfiguration = &s3.CreateBucketConfiguration{LocationConstraint: aws.String(options.Region)}
+	}
+	_, err := client.CreateBucketWithContext(ctx, input)
+	return toError(err)
+}
+
+func (t *ObjectStore) DeleteBucket(ctx context.Context, name string) error {
+	_, err := t.client.DeleteBucketWithContext(ctx, &s3.DeleteBucketInput{Bucket: aws.String(name)})
+	return toError(err)
+}
+
+func (t *ObjectStore) ListBuckets(ctx context.Context) ([]osi.BucketInfo, error) {
+	output, err := t.client.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, toError(err)
+	}
+	var buckets = make([]osi.BucketInfo, 0, len(output.Buckets))
+	for _, b := range output.Buckets {
+		buckets = append(buckets, osi.BucketInfo{
+			Name:         aws.StringValue(b.Name),
+			CreationDate: aws.TimeValue(b.CreationDate),
+		})
+	}
+	return buckets, nil
+}
+
+func (t *ObjectStore) BucketExists(ctx context.Context, name string) (bool, error) {
+	_, err := t.client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(name)})
+	if err != nil {
+		err = toError(err)
+		if errors.Is(err, osi.ObjectNotFound) || errors.Is(err, osi.BucketNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (t *ObjectStore) ACLEnum() osi.ACLEnum {
 	return aclEnum{}
 }
@@ -72,14 +143,22 @@ type bucket struct {
 	bucket string
 }
 
-func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error) {
-	acl, err := t.client.GetObjectAcl(&s3.GetObjectAclInput{Bucket: &t.bucket, Key: &path})
-	if err != nil {
-		if err.(awserr.Error).Code() == "NoSuchKey" {
-			return nil, osi.ObjectNotFound
-		}
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	return t.getObject(ctx, path, nil, osi.NewGetOptions(opts...))
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	if err := rng.Validate(); err != nil {
 		return nil, err
 	}
+	return t.getObject(ctx, path, aws.String(rng.HeaderValue()), osi.NewGetOptions(opts...))
+}
+
+func (t *bucket) getObject(ctx context.Context, path string, rng *string, options *osi.GetOptions) (osi.Object, error) {
+	acl, err := t.client.GetObjectAclWithContext(ctx, &s3.GetObjectAclInput{Bucket: &t.bucket, Key: &path, VersionId: versionID(options.VersionID)})
+	if err != nil {
+		return nil, toError(err)
+	}
 
 	var publicACL = make(map[string]int)
 	for _, grant := range acl.Grants {
@@ -95,47 +174,92 @@ func (t *bucket) GetObject(ctx context.Context, path string) (osi.Object, error)
 		ACL = "private"
 	}
 
-	resp, err := t.client.GetObject(&s3.GetObjectInput{Bucket: &t.bucket, Key: &path})
+	input := &s3.GetObjectInput{Bucket: &t.bucket, Key: &path, Range: rng, VersionId: versionID(options.VersionID)}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = customerKey(options.CustomerKey)
+	conditions := options.Conditions
+	if conditions.IfMatch != "" {
+		input.IfMatch = aws.String(osi.QuoteETag(conditions.IfMatch))
+	}
+	if conditions.IfNoneMatch != "" {
+		input.IfNoneMatch = aws.String(osi.QuoteETag(conditions.IfNoneMatch))
+	}
+	if !conditions.IfModifiedSince.IsZero() {
+		input.IfModifiedSince = aws.Time(conditions.IfModifiedSince)
+	}
+	if !conditions.IfUnmodifiedSince.IsZero() {
+		input.IfUnmodifiedSince = aws.Time(conditions.IfUnmodifiedSince)
+	}
+	resp, err := t.client.GetObjectWithContext(ctx, input)
 	if err != nil {
-		return nil, err
+		return nil, toError(err)
 	}
 	return osi.NewObject(t.bucket, path, ACL, resp.Body), nil
 }
 
-func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader) error {
-	return t.PutObjectWithACL(ctx, path, reader, aclEnum{}.Default())
-}
-
-func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
-	temp, err := os.CreateTemp("", "temp")
-	if err != nil {
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	options := osi.NewPutOptions(path, opts...)
+	if err := checkWriteConditions(options.Conditions); err != nil {
 		return err
 	}
-	_, _ = io.Copy(temp, reader)
-	_ = temp.Close()
-
-	f, err := os.Open(temp.Name())
-	if err != nil {
+	if err := osi.ValidateTags(options.Tags); err != nil {
 		return err
 	}
-	defer func() {
-		_ = f.Close()
-		_ = os.Remove(temp.Name())
-	}()
-	_, err = t.client.PutObject(&s3.PutObjectInput{
-		Bucket:      &t.bucket,
-		Key:         &path,
-		Body:        f,
-		ContentType: aws.String(mime.TypeByExtension(filepath.Ext(path))),
-		ACL:         aws.String(acl),
+	if err := options.Encryption.Validate(); err != nil {
+		return err
+	}
+	input := &s3manager.UploadInput{
+		Bucket:               &t.bucket,
+		Key:                  &path,
+		Body:                 reader,
+		Metadata:             aws.StringMap(options.Metadata),
+		ServerSideEncryption: serverSideEncryption(options.Encryption.Mode),
+		SSEKMSKeyId:          optionalString(options.Encryption.KMSKeyID),
+	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = customerKey(options.Encryption.CustomerKey)
+	if options.ACL != "" {
+		input.ACL = aws.String(options.ACL)
+	}
+	if options.ContentType != "" {
+		input.ContentType = aws.String(options.ContentType)
+	}
+	if options.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(options.ContentEncoding)
+	}
+	if options.CacheControl != "" {
+		input.CacheControl = aws.String(options.CacheControl)
+	}
+	if options.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(options.ContentDisposition)
+	}
+	if !options.Expires.IsZero() {
+		input.Expires = aws.Time(options.Expires)
+	}
+	if len(options.Tags) > 0 {
+		input.Tagging = aws.String(osi.EncodeTags(options.Tags))
+	}
+	if options.StorageClass != "" {
+		input.StorageClass = aws.String(storageClasses.Provider(options.StorageClass))
+	}
+	uploader := s3manager.NewUploaderWithClient(t.client, func(u *s3manager.Uploader) {
+		u.PartSize = options.PartSize
+		u.Concurrency = options.Concurrency
+		if !options.Conditions.IsZero() {
+			u.RequestOptions = append(u.RequestOptions, withConditions(options.Conditions, "PutObject", "CompleteMultipartUpload"))
+		}
 	})
-	return err
+	_, err := uploader.UploadWithContext(ctx, input)
+	return toError(err)
+}
+
+func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
+	return t.PutObject(ctx, path, reader, osi.WithACL(acl))
 }
 
 func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
-	_, err := t.client.HeadObject(&s3.HeadObjectInput{Bucket: &t.bucket, Key: &path})
+	_, err := t.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{Bucket: &t.bucket, Key: &path})
 	if err != nil {
-		if err.(awserr.Error).Code() == "NotFound" {
+		err = toError(err)
+		if errors.Is(err, osi.ObjectNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -143,70 +267,199 @@ func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
 	return true, nil
 }
 
-func (t *bucket) DeleteObject(ctx context.Context, path string) error {
-	_, err := t.client.DeleteObject(&s3.DeleteObjectInput{Bucket: &t.bucket, Key: &path})
-	return err
+// DeleteObject supports IfMatch only, the one condition S3 evaluates on deletes.
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	options := osi.NewDeleteOptions(opts...)
+	conditions := options.Conditions
+	if conditions.IfNoneMatch != "" || !conditions.IfModifiedSince.IsZero() || !conditions.IfUnmodifiedSince.IsZero() {
+		return &osi.Error{Kind: osi.NotSupported, Provider: Name, Message: "conditional delete supports IfMatch only"}
+	}
+	var requestOptions []request.Option
+	if !conditions.IsZero() {
+		requestOptions = append(requestOptions, withConditions(conditions, "DeleteObject"))
+	}
+	_, err := t.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{Bucket: &t.bucket, Key: &path, VersionId: versionID(options.VersionID)}, requestOptions...)
+	return toError(err)
 }
 
-func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	head, err := t.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{Bucket: &options.SourceBucket, Key: &src})
+	if err != nil {
+		return toError(err)
+	}
+	copySource := options.SourceBucket + "/" + url.PathEscape(src)
+	if aws.Int64Value(head.ContentLength) <= osi.MaxCopyObjectSize {
+		_, err = t.client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+			Bucket:     &t.bucket,
+			Key:        &dst,
+			CopySource: &copySource,
+		})
+		return toError(err)
+	}
+	return toError(t.multipartCopy(ctx, copySource, copyUploadInput(t.bucket, dst, head), aws.Int64Value(head.ContentLength)))
+}
+
+// copyUploadInput starts the upload of a multipart copy onto dst with the
+// content headers and metadata of head.
+func copyUploadInput(bucket string, dst string, head *s3.HeadObjectOutput) *s3.CreateMultipartUploadInput {
+	return &s3.CreateMultipartUploadInput{
+		Bucket:             &bucket,
+		Key:                &dst,
+		ContentType:        head.ContentType,
+		ContentEncoding:    head.ContentEncoding,
+		CacheControl:       head.CacheControl,
+		ContentDisposition: head.ContentDisposition,
+		Metadata:           head.Metadata,
+	}
+}
+
+func (t *bucket) multipartCopy(ctx context.Context, copySource string, input *s3.CreateMultipartUploadInput, size int64) error {
+	dst := aws.StringValue(input.Key)
+	upload, err := t.client.CreateMultipartUploadWithContext(ctx, input)
+	if err != nil {
+		return err
+	}
 
-	var oms = make([]osi.ObjectMeta, 0)
-	var marker = ""
-	for {
-		objects, err := t.client.ListObjects(&s3.ListObjectsInput{
-			Bucket:  aws.String(t.bucket),
-			Prefix:  aws.String(prefix),
-			Marker:  aws.String(marker),
-			MaxKeys: aws.Int64(200),
+	var parts = make([]*s3.CompletedPart, 0)
+	for i, rng := range osi.SplitRange(size, osi.CopyPartSize(size)) {
+		part, err := t.client.UploadPartCopyWithContext(ctx, &s3.UploadPartCopyInput{
+			Bucket:          &t.bucket,
+			Key:             &dst,
+			UploadId:        upload.UploadId,
+			PartNumber:      aws.Int64(int64(i + 1)),
+			CopySource:      &copySource,
+			CopySourceRange: aws.String(rng.HeaderValue()),
 		})
 		if err != nil {
-			return oms, err
-		}
-		for _, key := range objects.Contents {
-			if key.Key != nil {
-				if strings.HasSuffix(*key.Key, "/") {
-					continue
-				}
-				oms = append(oms, osi.NewObjectMeta(t.bucket, *key.Key))
-			}
+			_, _ = t.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{Bucket: &t.bucket, Key: &dst, UploadId: upload.UploadId})
+			return err
 		}
-		if objects.IsTruncated != nil && !*objects.IsTruncated {
-			return oms, nil
-		} else {
-			if objects.NextMarker != nil {
-				marker = *objects.NextMarker
-			} else {
-				return oms, nil
-			}
+		parts = append(parts, &s3.CompletedPart{ETag: part.CopyPartResult.ETag, PartNumber: aws.Int64(int64(i + 1))})
+	}
+	_, err = t.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          &t.bucket,
+		Key:             &dst,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	err := t.CopyObject(ctx, src, dst, opts...)
+	if err != nil {
+		return err
+	}
+	options := osi.NewCopyOptions(t.bucket, opts...)
+	_, err = t.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{Bucket: &options.SourceBucket, Key: &src})
+	return toError(err)
+}
+
+func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
+	return osi.ListAll(ctx, t, prefix)
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(t.bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int64(int64(options.PageSize)),
+	}
+	if options.ContinuationToken != "" {
+		input.ContinuationToken = aws.String(options.ContinuationToken)
+	}
+	if options.StartAfter != "" {
+		input.StartAfter = aws.String(options.StartAfter)
+	}
+	if options.Delimiter != "" {
+		input.Delimiter = aws.String(options.Delimiter)
+	}
+	objects, err := t.client.ListObjectsV2WithContext(ctx, input)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.ObjectPage{
+		Objects:               make([]*osi.ObjectInfo, 0, len(objects.Contents)),
+		IsTruncated:           aws.BoolValue(objects.IsTruncated),
+		NextContinuationToken: aws.StringValue(objects.NextContinuationToken),
+	}
+	for _, key := range objects.Contents {
+		if key.Key == nil || strings.HasSuffix(*key.Key, "/") {
+			continue
 		}
+		page.Objects = append(page.Objects, listedObject(t.bucket, key))
 	}
+	for _, commonPrefix := range objects.CommonPrefixes {
+		page.CommonPrefixes = append(page.CommonPrefixes, aws.StringValue(commonPrefix.Prefix))
+	}
+	return page, nil
 }
 
 func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
-	resp, err := t.client.HeadObject(&s3.HeadObjectInput{Bucket: &t.bucket, Key: &path})
+	info, err := t.StatObject(ctx, path)
 	if err != nil {
-		if err.(awserr.Error).Code() == "NotFound" {
-			return nil, osi.ObjectNotFound
-		}
 		return nil, err
 	}
-	return osi.NewSize(*resp.ContentLength), nil
+	return osi.NewSize(info.Size), nil
+}
+
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	options := osi.NewGetOptions(opts...)
+	input := &s3.HeadObjectInput{Bucket: &t.bucket, Key: &path, VersionId: versionID(options.VersionID)}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = customerKey(options.CustomerKey)
+	resp, err := t.client.HeadObjectWithContext(ctx, input)
+	if err != nil {
+		return nil, toError(err)
+	}
+	info := osi.NewObjectInfo(t.bucket, path)
+	info.Size = aws.Int64Value(resp.ContentLength)
+	info.ETag = osi.TrimETag(aws.StringValue(resp.ETag))
+	info.VersionID = aws.StringValue(resp.VersionId)
+	info.LastModified = aws.TimeValue(resp.LastModified)
+	info.ContentType = aws.StringValue(resp.ContentType)
+	info.ContentEncoding = aws.StringValue(resp.ContentEncoding)
+	info.CacheControl = aws.StringValue(resp.CacheControl)
+	info.ContentDisposition = aws.StringValue(resp.ContentDisposition)
+	info.Expires, _ = http.ParseTime(aws.StringValue(resp.Expires))
+	info.StorageClass = storageClass(resp.StorageClass)
+	info.Restore = osi.ParseRestoreStatus(aws.StringValue(resp.Restore))
+	info.Encryption = osi.ParseEncryptionMode(aws.StringValue(resp.ServerSideEncryption), aws.StringValue(resp.SSECustomerAlgorithm))
+	info.KMSKeyID = aws.StringValue(resp.SSEKMSKeyId)
+	info.Metadata = osi.NormalizeMetadata(aws.StringValueMap(resp.Metadata))
+	return info, nil
 }
 
-func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration) (string, error) {
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	signed, err := t.signURL(ctx, path, method, expiredInDur, opts...)
+	osi.LogSignURL(ctx, t.config.Logger, Name, t.bucket, path, method, expiredInDur, signed, err)
+	return signed, err
+}
 
+func (t *bucket) signURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	options := osi.NewSignOptions(opts...)
 	var req *request.Request
 	switch method {
 	case http.MethodGet:
 		req, _ = t.client.GetObjectRequest(&s3.GetObjectInput{
-			Bucket: aws.String(t.bucket),
-			Key:    aws.String(path),
+			Bucket:                     aws.String(t.bucket),
+			Key:                        aws.String(path),
+			ResponseContentType:        optionalString(options.ResponseContentType),
+			ResponseContentDisposition: optionalString(options.ResponseContentDisposition),
 		})
 		return req.Presign(expiredInDur)
 	case http.MethodPut:
 		req, _ = t.client.PutObjectRequest(&s3.PutObjectInput{
-			Bucket: aws.String(t.bucket),
-			Key:    aws.String(path),
+			Bucket:      aws.String(t.bucket),
+			Key:         aws.String(path),
+			ContentType: optionalString(options.ContentType),
+			ContentMD5:  optionalString(options.ContentMD5),
+			ACL:         optionalString(options.ACL),
 		})
 		return req.Presign(expiredInDur)
 	case http.MethodDelete:
@@ -222,8 +475,16 @@ func (t *bucket) SignURL(ctx context.Context, path string, method string, expire
 		})
 		return req.Presign(expiredInDur)
 	default:
-		return "", errors.New("not support")
+		return "", osi.NotSupported
+	}
+}
+
+// optionalString leaves empty strings out of requests.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
 	}
+	return aws.String(s)
 }
 
 func (t *bucket) DeleteObjects(ctx context.Context, paths []string) error {
@@ -251,8 +512,28 @@ func (t *bucket) deleteFiles(ctx context.Context, paths []string) error {
 			Key: aws.String(paths[i]),
 		})
 	}
-	_, err := t.client.DeleteObjects(input)
-	return err
+	output, err := t.client.DeleteObjectsWithContext(ctx, input)
+	if err != nil {
+		return toError(err)
+	}
+	if len(output.Errors) > 0 {
+		keys := make([]string, len(output.Errors))
+		for i, failed := range output.Errors {
+			keys[i] = aws.StringValue(failed.Key)
+		}
+		failed := output.Errors[0]
+		return osi.DeleteError(Name, aws.StringValue(failed.Code), aws.StringValue(failed.Message), keys)
+	}
+	return nil
+}
+
+func listedObject(bucket string, object *s3.Object) *osi.ObjectInfo {
+	info := osi.NewObjectInfo(bucket, aws.StringValue(object.Key))
+	info.Size = aws.Int64Value(object.Size)
+	info.ETag = osi.TrimETag(aws.StringValue(object.ETag))
+	info.LastModified = aws.TimeValue(object.LastModified)
+	info.StorageClass = storageClass(object.StorageClass)
+	return info
 }
 
 type aclEnum struct {
@@ -0,0 +1,64 @@
+package s3
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/burybell/osi"
+	"net/url"
+)
+
+var storageClasses = osi.StorageClassNames{
+	osi.StorageClassStandard:    s3.StorageClassStandard,
+	osi.StorageClassIA:          s3.StorageClassStandardIa,
+	osi.StorageClassArchive:     s3.StorageClassGlacier,
+	osi.StorageClassDeepArchive: s3.StorageClassDeepArchive,
+}
+
+// storageClass returns the class of an object, which S3 leaves out for
+// STANDARD objects.
+func storageClass(class *string) string {
+	if aws.StringValue(class) == "" {
+		return osi.StorageClassStandard
+	}
+	return storageClasses.Class(aws.StringValue(class))
+}
+
+// SetStorageClass keeps the metadata and the managed key encryption of the
+// object, but not its ACL, which falls back to the bucket default.
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	head, err := t.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{Bucket: &t.bucket, Key: &path})
+	if err != nil {
+		return toError(err)
+	}
+	copySource := t.bucket + "/" + url.PathEscape(path)
+	name := aws.String(storageClasses.Provider(class))
+	if aws.Int64Value(head.ContentLength) > osi.MaxCopyObjectSize {
+		input := copyUploadInput(t.bucket, path, head)
+		input.StorageClass = name
+		input.ServerSideEncryption, input.SSEKMSKeyId = head.ServerSideEncryption, head.SSEKMSKeyId
+		return toError(t.multipartCopy(ctx, copySource, input, aws.Int64Value(head.ContentLength)))
+	}
+	_, err = t.client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+		Bucket:               &t.bucket,
+		Key:                  &path,
+		CopySource:           &copySource,
+		MetadataDirective:    aws.String(s3.MetadataDirectiveCopy),
+		StorageClass:         name,
+		ServerSideEncryption: head.ServerSideEncryption,
+		SSEKMSKeyId:          head.SSEKMSKeyId,
+	})
+	return toError(err)
+}
+
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	if err := osi.ValidateRestore(days, tier); err != nil {
+		return err
+	}
+	request := &s3.RestoreRequest{Days: aws.Int64(int64(days))}
+	if tier != "" {
+		request.GlacierJobParameters = &s3.GlacierJobParameters{Tier: aws.String(string(tier))}
+	}
+	_, err := t.client.RestoreObjectWithContext(ctx, &s3.RestoreObjectInput{Bucket: &t.bucket, Key: &path, RestoreRequest: request})
+	return toError(err)
+}
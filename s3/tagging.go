@@ -0,0 +1,41 @@
+package s3
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/burybell/osi"
+)
+
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	resp, err := t.client.GetObjectTaggingWithContext(ctx, &s3.GetObjectTaggingInput{Bucket: &t.bucket, Key: &path})
+	if err != nil {
+		return nil, toError(err)
+	}
+	var tags = make(map[string]string, len(resp.TagSet))
+	for _, tag := range resp.TagSet {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tags map[string]string) error {
+	if err := osi.ValidateTags(tags); err != nil {
+		return err
+	}
+	var tagSet = make([]*s3.Tag, 0, len(tags))
+	for key, value := range tags {
+		tagSet = append(tagSet, &s3.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	_, err := t.client.PutObjectTaggingWithContext(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  &t.bucket,
+		Key:     &path,
+		Tagging: &s3.Tagging{TagSet: tagSet},
+	})
+	return toError(err)
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	_, err := t.client.DeleteObjectTaggingWithContext(ctx, &s3.DeleteObjectTaggingInput{Bucket: &t.bucket, Key: &path})
+	return toError(err)
+}
@@ -0,0 +1,95 @@
+package s3
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/burybell/osi"
+	"strings"
+)
+
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	options, err := osi.NewListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	keyMarker, versionIDMarker, err := osi.ParseVersionToken(options.ContinuationToken)
+	if err != nil {
+		return nil, err
+	}
+	if keyMarker == "" {
+		keyMarker = options.StartAfter
+	}
+	input := &s3.ListObjectVersionsInput{
+		Bucket:  aws.String(t.bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int64(int64(options.PageSize)),
+	}
+	if keyMarker != "" {
+		input.KeyMarker = aws.String(keyMarker)
+	}
+	if versionIDMarker != "" {
+		input.VersionIdMarker = aws.String(versionIDMarker)
+	}
+	if options.Delimiter != "" {
+		input.Delimiter = aws.String(options.Delimiter)
+	}
+	resp, err := t.client.ListObjectVersionsWithContext(ctx, input)
+	if err != nil {
+		return nil, toError(err)
+	}
+
+	var page = &osi.VersionPage{
+		Versions:    make([]*osi.ObjectVersion, 0, len(resp.Versions)+len(resp.DeleteMarkers)),
+		IsTruncated: aws.BoolValue(resp.IsTruncated),
+	}
+	if page.IsTruncated {
+		page.NextContinuationToken = osi.VersionToken(aws.StringValue(resp.NextKeyMarker), aws.StringValue(resp.NextVersionIdMarker))
+	}
+	for _, version := range resp.Versions {
+		if version.Key == nil || strings.HasSuffix(*version.Key, "/") {
+			continue
+		}
+		info := osi.NewObjectInfo(t.bucket, aws.StringValue(version.Key))
+		info.Size = aws.Int64Value(version.Size)
+		info.ETag = osi.TrimETag(aws.StringValue(version.ETag))
+		info.VersionID = aws.StringValue(version.VersionId)
+		info.LastModified = aws.TimeValue(version.LastModified)
+		info.StorageClass = storageClass(version.StorageClass)
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: aws.BoolValue(version.IsLatest)})
+	}
+	for _, marker := range resp.DeleteMarkers {
+		info := osi.NewObjectInfo(t.bucket, aws.StringValue(marker.Key))
+		info.VersionID = aws.StringValue(marker.VersionId)
+		info.LastModified = aws.TimeValue(marker.LastModified)
+		page.Versions = append(page.Versions, &osi.ObjectVersion{ObjectInfo: info, IsLatest: aws.BoolValue(marker.IsLatest), IsDeleteMarker: true})
+	}
+	osi.SortVersions(page.Versions)
+	for _, commonPrefix := range resp.CommonPrefixes {
+		page.CommonPrefixes = append(page.CommonPrefixes, aws.StringValue(commonPrefix.Prefix))
+	}
+	return page, nil
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	resp, err := t.client.GetBucketVersioningWithContext(ctx, &s3.GetBucketVersioningInput{Bucket: aws.String(t.bucket)})
+	if err != nil {
+		return osi.VersioningOff, toError(err)
+	}
+	return osi.VersioningStatus(aws.StringValue(resp.Status)), nil
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	_, err := t.client.PutBucketVersioningWithContext(ctx, &s3.PutBucketVersioningInput{
+		Bucket:                  aws.String(t.bucket),
+		VersioningConfiguration: &s3.VersioningConfiguration{Status: aws.String(string(status))},
+	})
+	return toError(err)
+}
+
+func versionID(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return aws.String(id)
+}
@@ -0,0 +1,89 @@
+package osi
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// SignOptions shape a presigned URL. The response overrides set the headers
+// of the response to a GET URL. The request constraints are headers the
+// holder of a PUT URL has to send as given, ContentMD5 being the base64
+// encoded MD5 digest of the body.
+type SignOptions struct {
+	ResponseContentType        string
+	ResponseContentDisposition string
+	ContentType                string
+	ContentMD5                 string
+	ACL                        ACL
+}
+
+type SignOption func(opts *SignOptions)
+
+func WithResponseContentType(contentType string) SignOption {
+	return func(opts *SignOptions) {
+		opts.ResponseContentType = contentType
+	}
+}
+
+// WithResponseContentDisposition makes browsers download the object, under
+// the file name given by contentDisposition, as in
+// `attachment; filename="example.txt"`.
+func WithResponseContentDisposition(contentDisposition string) SignOption {
+	return func(opts *SignOptions) {
+		opts.ResponseContentDisposition = contentDisposition
+	}
+}
+
+func WithSignContentType(contentType string) SignOption {
+	return func(opts *SignOptions) {
+		opts.ContentType = contentType
+	}
+}
+
+func WithSignContentMD5(contentMD5 string) SignOption {
+	return func(opts *SignOptions) {
+		opts.ContentMD5 = contentMD5
+	}
+}
+
+func WithSignACL(acl ACL) SignOption {
+	return func(opts *SignOptions) {
+		opts.ACL = acl
+	}
+}
+
+func NewSignOptions(opts ...SignOption) *SignOptions {
+	options := &SignOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// Query returns the response overrides as query parameters.
+func (t *SignOptions) Query() url.Values {
+	var query = make(url.Values)
+	if t.ResponseContentType != "" {
+		query.Set("response-content-type", t.ResponseContentType)
+	}
+	if t.ResponseContentDisposition != "" {
+		query.Set("response-content-disposition", t.ResponseContentDisposition)
+	}
+	return query
+}
+
+// Header returns the request constraints as headers, with the ACL in the
+// header named aclHeader.
+func (t *SignOptions) Header(aclHeader string) http.Header {
+	var header = make(http.Header)
+	if t.ContentType != "" {
+		header.Set("Content-Type", t.ContentType)
+	}
+	if t.ContentMD5 != "" {
+		header.Set("Content-MD5", t.ContentMD5)
+	}
+	if t.ACL != "" {
+		header.Set(aclHeader, t.ACL)
+	}
+	return header
+}
@@ -0,0 +1,32 @@
+package osi_test
+
+import (
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSignOptions(t *testing.T) {
+	options := osi.NewSignOptions()
+	assert.Empty(t, options.Query())
+	assert.Empty(t, options.Header("x-amz-acl"))
+
+	options = osi.NewSignOptions(
+		osi.WithResponseContentType("text/plain"),
+		osi.WithResponseContentDisposition(`attachment; filename="example.txt"`),
+		osi.WithSignContentType("text/plain"),
+		osi.WithSignContentMD5("XrY7u+Ae7tCTyyK7j1rNww=="),
+		osi.WithSignACL("private"),
+	)
+	assert.Equal(t, url.Values{
+		"response-content-type":        {"text/plain"},
+		"response-content-disposition": {`attachment; filename="example.txt"`},
+	}, options.Query())
+	assert.Equal(t, http.Header{
+		"Content-Type": {"text/plain"},
+		"Content-Md5":  {"XrY7u+Ae7tCTyyK7j1rNww=="},
+		"X-Amz-Acl":    {"private"},
+	}, options.Header("x-amz-acl"))
+}
@@ -0,0 +1,167 @@
+package osi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The storage classes shared by the providers, from the most to the least
+// expensive to store. The backends map them onto the names of the provider,
+// such as STANDARD_IA on S3, IA on OSS and WARM on OBS, and report them in
+// ObjectInfo.StorageClass; classes without a shared name are passed through
+// as the provider names them.
+const (
+	StorageClassStandard = "STANDARD"
+	// StorageClassIA is for infrequent access, with a charge per read.
+	StorageClassIA = "IA"
+	// StorageClassArchive and StorageClassDeepArchive objects have to be
+	// restored with RestoreObject before they can be read.
+	StorageClassArchive     = "ARCHIVE"
+	StorageClassDeepArchive = "DEEP_ARCHIVE"
+)
+
+// StorageClassNames maps the StorageClass constants onto the names a
+// provider gives them.
+type StorageClassNames map[string]string
+
+// Provider returns the provider name of class, or class itself when it is
+// not one of the StorageClass constants.
+func (t StorageClassNames) Provider(class string) string {
+	if name, ok := t[class]; ok {
+		return name
+	}
+	return class
+}
+
+// Class returns the StorageClass constant the provider name stands for, or
+// name itself when it has none.
+func (t StorageClassNames) Class(name string) string {
+	for class, provider := range t {
+		if strings.EqualFold(provider, name) {
+			return class
+		}
+	}
+	return name
+}
+
+// RestoreTier trades the speed of a restore for its price. The empty tier
+// leaves the choice to the provider.
+type RestoreTier string
+
+const (
+	RestoreExpedited RestoreTier = "Expedited"
+	RestoreStandard  RestoreTier = "Standard"
+	RestoreBulk      RestoreTier = "Bulk"
+)
+
+// ObjectArchiving moves objects between storage classes and restores
+// archived objects.
+type ObjectArchiving interface {
+	// SetStorageClass moves the object at path to class, copying it onto
+	// itself on the providers that cannot change the class in place.
+	// Archived objects have to be restored first.
+	SetStorageClass(ctx context.Context, path string, class string) error
+	// RestoreObject makes a readable copy of an archived object that is
+	// kept for days. It completes asynchronously; ObjectInfo.Restore tells
+	// when. Restoring an object whose restore is ongoing fails with
+	// RestoreInProgress, while restoring a restored object extends its
+	// expiry.
+	RestoreObject(ctx context.Context, path string, days int, tier RestoreTier) error
+}
+
+// ValidateRestore checks that a restore keeps the copy for at least a day
+// and that tier is one of the RestoreTier constants or empty.
+func ValidateRestore(days int, tier RestoreTier) error {
+	if days < 1 {
+		return fmt.Errorf("%w: restore for %d days, at least 1 is required", InvalidRestore, days)
+	}
+	switch tier {
+	case "", RestoreExpedited, RestoreStandard, RestoreBulk:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown tier %q", InvalidRestore, string(tier))
+}
+
+// RestoreStatus is the state of the readable copy of an archived object.
+type RestoreStatus struct {
+	Ongoing bool
+	// ExpiryDate is when the restored copy is removed again; it is zero
+	// while the restore is ongoing.
+	ExpiryDate time.Time
+}
+
+// IsArchived reports whether objects of class have to be restored to be read.
+func IsArchived(class string) bool {
+	return class == StorageClassArchive || class == StorageClassDeepArchive
+}
+
+// Readable reports whether the object can be read, which archived objects can
+// only while a restored copy exists.
+func (t *ObjectInfo) Readable() bool {
+	if !IsArchived(t.StorageClass) {
+		return true
+	}
+	return t.Restore != nil && !t.Restore.Ongoing && time.Now().Before(t.Restore.ExpiryDate)
+}
+
+// ParseRestoreStatus parses the restore header the providers answer HEAD
+// requests on restored objects with, as in
+// `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`.
+// It returns nil when no restore was requested.
+func ParseRestoreStatus(header string) *RestoreStatus {
+	if header == "" {
+		return nil
+	}
+	status := &RestoreStatus{}
+	for _, field := range splitRestoreHeader(header) {
+		name, value := field[0], field[1]
+		switch strings.ToLower(name) {
+		case "ongoing-request":
+			status.Ongoing = value == "true"
+		case "expiry-date":
+			status.ExpiryDate = parseRestoreTime(value)
+		}
+	}
+	return status
+}
+
+// splitRestoreHeader splits header into name and value pairs. Values are
+// quoted and may contain commas, as dates do.
+func splitRestoreHeader(header string) [][2]string {
+	var fields [][2]string
+	for header != "" {
+		eq := strings.IndexByte(header, '=')
+		if eq < 0 {
+			break
+		}
+		name := strings.TrimSpace(strings.TrimLeft(header[:eq], ", "))
+		rest := strings.TrimSpace(header[eq+1:])
+		var value string
+		if strings.HasPrefix(rest, "\"") {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else if comma := strings.IndexByte(rest, ','); comma >= 0 {
+			value, rest = rest[:comma], rest[comma:]
+		} else {
+			value, rest = rest, ""
+		}
+		fields = append(fields, [2]string{name, value})
+		header = rest
+	}
+	return fields
+}
+
+func parseRestoreTime(value string) time.Time {
+	for _, layout := range []string{time.RFC1123, time.RFC1123Z, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
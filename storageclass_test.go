@@ -0,0 +1,48 @@
+package osi_test
+
+import (
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseRestoreStatus(t *testing.T) {
+	assert.Nil(t, osi.ParseRestoreStatus(""))
+	assert.Equal(t, &osi.RestoreStatus{Ongoing: true}, osi.ParseRestoreStatus(`ongoing-request="true"`))
+	status := osi.ParseRestoreStatus(`ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`)
+	assert.False(t, status.Ongoing)
+	assert.True(t, status.ExpiryDate.Equal(time.Date(2012, 12, 21, 0, 0, 0, 0, time.UTC)))
+	status = osi.ParseRestoreStatus(`ongoing-request="false", expiry-date="2012-12-21T00:00:00.000Z"`)
+	assert.True(t, status.ExpiryDate.Equal(time.Date(2012, 12, 21, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestObjectInfo_Readable(t *testing.T) {
+	info := osi.NewObjectInfo("example", "test/example.txt")
+	assert.True(t, info.Readable())
+	info.StorageClass = osi.StorageClassIA
+	assert.True(t, info.Readable())
+	info.StorageClass = osi.StorageClassArchive
+	assert.False(t, info.Readable())
+	info.Restore = &osi.RestoreStatus{Ongoing: true}
+	assert.False(t, info.Readable())
+	info.Restore = &osi.RestoreStatus{ExpiryDate: time.Now().Add(time.Hour)}
+	assert.True(t, info.Readable())
+	info.Restore = &osi.RestoreStatus{ExpiryDate: time.Now().Add(-time.Hour)}
+	assert.False(t, info.Readable())
+}
+
+func TestStorageClassNames(t *testing.T) {
+	names := osi.StorageClassNames{osi.StorageClassIA: "STANDARD_IA", osi.StorageClassArchive: "GLACIER"}
+	assert.Equal(t, "STANDARD_IA", names.Provider(osi.StorageClassIA))
+	assert.Equal(t, "INTELLIGENT_TIERING", names.Provider("INTELLIGENT_TIERING"))
+	assert.Equal(t, osi.StorageClassArchive, names.Class("GLACIER"))
+	assert.Equal(t, "INTELLIGENT_TIERING", names.Class("INTELLIGENT_TIERING"))
+}
+
+func TestValidateRestore(t *testing.T) {
+	assert.NoError(t, osi.ValidateRestore(1, ""))
+	assert.NoError(t, osi.ValidateRestore(7, osi.RestoreBulk))
+	assert.ErrorIs(t, osi.ValidateRestore(0, osi.RestoreStandard), osi.InvalidRestore)
+	assert.ErrorIs(t, osi.ValidateRestore(1, "Fast"), osi.InvalidRestore)
+}
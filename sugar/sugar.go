@@ -19,6 +19,8 @@ type Options struct {
 	Minio   minio.Config
 	OBS     obs.Config
 	UseName string
+	// Logger, when set, replaces the Logger of the config of the store used.
+	Logger osi.Logger
 }
 
 type Option func(opts *Options)
@@ -65,6 +67,12 @@ func UseOBS(config obs.Config) Option {
 	}
 }
 
+func WithLogger(logger osi.Logger) Option {
+	return func(opts *Options) {
+		opts.Logger = logger
+	}
+}
+
 func NewObjectStore(opt ...Option) (osi.ObjectStore, error) {
 	opts := &Options{}
 	for _, opt := range opt {
@@ -75,6 +83,14 @@ func NewObjectStore(opt ...Option) (osi.ObjectStore, error) {
 		opts.UseName = local.Name
 		opts.Local = local.Config{BasePath: "/tmp"}
 	}
+	if opts.Logger != nil {
+		opts.OSS.Logger = opts.Logger
+		opts.S3.Logger = opts.Logger
+		opts.COS.Logger = opts.Logger
+		opts.Local.Logger = opts.Logger
+		opts.Minio.Logger = opts.Logger
+		opts.OBS.Logger = opts.Logger
+	}
 
 	switch opts.UseName {
 	case oss.Name:
@@ -0,0 +1,76 @@
+package osi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTagCount is the most tags an object can carry.
+	MaxTagCount       = 10
+	MaxTagKeyLength   = 128
+	MaxTagValueLength = 256
+)
+
+// ObjectTagging reads and replaces the tags of objects. Tags are key value
+// pairs kept apart from the object data and metadata, and can change without
+// rewriting the object.
+type ObjectTagging interface {
+	GetObjectTags(ctx context.Context, path string) (map[string]string, error)
+	// PutObjectTags replaces all tags of the object at path with tags.
+	PutObjectTags(ctx context.Context, path string, tags map[string]string) error
+	DeleteObjectTags(ctx context.Context, path string) error
+}
+
+// ValidateTags checks tags against the limits shared by the providers: at
+// most MaxTagCount tags, non-empty keys of up to MaxTagKeyLength characters,
+// values of up to MaxTagValueLength characters, all made of letters, digits,
+// spaces and the symbols + - = . _ : / @.
+func ValidateTags(tags map[string]string) error {
+	if len(tags) > MaxTagCount {
+		return fmt.Errorf("%w: %d tags, at most %d are allowed", InvalidTag, len(tags), MaxTagCount)
+	}
+	for key, value := range tags {
+		if key == "" {
+			return fmt.Errorf("%w: empty tag key", InvalidTag)
+		}
+		if n := utf8.RuneCountInString(key); n > MaxTagKeyLength {
+			return fmt.Errorf("%w: tag key %q has %d characters, at most %d are allowed", InvalidTag, key, n, MaxTagKeyLength)
+		}
+		if n := utf8.RuneCountInString(value); n > MaxTagValueLength {
+			return fmt.Errorf("%w: value of tag %q has %d characters, at most %d are allowed", InvalidTag, key, n, MaxTagValueLength)
+		}
+		if !validTag(key) {
+			return fmt.Errorf("%w: tag key %q contains invalid characters", InvalidTag, key)
+		}
+		if !validTag(value) {
+			return fmt.Errorf("%w: value of tag %q contains invalid characters", InvalidTag, key)
+		}
+	}
+	return nil
+}
+
+func validTag(s string) bool {
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsSpace(r):
+		case r == '+', r == '-', r == '=', r == '.', r == '_', r == ':', r == '/', r == '@':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeTags renders tags as the URL encoded query sent in the tagging
+// header of puts.
+func EncodeTags(tags map[string]string) string {
+	var values = make(url.Values, len(tags))
+	for key, value := range tags {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
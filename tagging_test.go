@@ -0,0 +1,29 @@
+package osi_test
+
+import (
+	"fmt"
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestValidateTags(t *testing.T) {
+	assert.NoError(t, osi.ValidateTags(nil))
+	assert.NoError(t, osi.ValidateTags(map[string]string{"team": "storage", "cost center": "a-1/b_2:c@d.e+f=g", "empty": ""}))
+
+	var tooMany = make(map[string]string)
+	for i := 0; i <= osi.MaxTagCount; i++ {
+		tooMany[fmt.Sprintf("key%d", i)] = "value"
+	}
+	assert.ErrorIs(t, osi.ValidateTags(tooMany), osi.InvalidTag)
+	assert.ErrorIs(t, osi.ValidateTags(map[string]string{"": "value"}), osi.InvalidTag)
+	assert.ErrorIs(t, osi.ValidateTags(map[string]string{strings.Repeat("k", osi.MaxTagKeyLength+1): "value"}), osi.InvalidTag)
+	assert.ErrorIs(t, osi.ValidateTags(map[string]string{"key": strings.Repeat("v", osi.MaxTagValueLength+1)}), osi.InvalidTag)
+	assert.ErrorIs(t, osi.ValidateTags(map[string]string{"key?": "value"}), osi.InvalidTag)
+	assert.ErrorIs(t, osi.ValidateTags(map[string]string{"key": "value&"}), osi.InvalidTag)
+}
+
+func TestEncodeTags(t *testing.T) {
+	assert.Equal(t, "team=storage&tier=cold+storage", osi.EncodeTags(map[string]string{"tier": "cold storage", "team": "storage"}))
+}
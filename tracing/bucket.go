@@ -0,0 +1,241 @@
+package tracing
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"time"
+)
+
+// bucket starts a span for every call on the bucket it wraps, as a child of
+// the span in the context of the call, which is passed on to the bucket with
+// the new span. Gets end their span when the object returned is closed.
+type bucket struct {
+	osi.Bucket
+	store  string
+	bucket string
+	tracer trace.Tracer
+}
+
+// NewBucket wraps bkt, the bucket bucketName of the store named store, so that
+// its calls are traced.
+func NewBucket(bkt osi.Bucket, store string, bucketName string, opts ...Option) osi.Bucket {
+	options := NewOptions(opts...)
+	return &bucket{Bucket: bkt, store: store, bucket: bucketName, tracer: options.TracerProvider.Tracer(InstrumentationName)}
+}
+
+func (t *bucket) open(ctx context.Context, name string, path string, get func(ctx context.Context) (osi.Object, error)) (osi.Object, error) {
+	ctx, span := t.start(ctx, name, ObjectKey.String(path))
+	object, err := get(ctx)
+	if err != nil {
+		end(span, err)
+		return nil, err
+	}
+	return newSpanObject(object, span), nil
+}
+
+func (t *bucket) put(ctx context.Context, name string, reader io.Reader, put func(ctx context.Context, reader io.Reader) error, attrs ...attribute.KeyValue) error {
+	ctx, span := t.start(ctx, name, attrs...)
+	counted, counter := countReader(reader)
+	err := put(ctx, counted)
+	span.SetAttributes(BytesKey.Int64(counter.n))
+	end(span, err)
+	return err
+}
+
+func (t *bucket) GetObject(ctx context.Context, path string, opts ...osi.GetOption) (osi.Object, error) {
+	return t.open(ctx, "GetObject", path, func(ctx context.Context) (osi.Object, error) {
+		return t.Bucket.GetObject(ctx, path, opts...)
+	})
+}
+
+func (t *bucket) GetObjectRange(ctx context.Context, path string, rng osi.Range, opts ...osi.GetOption) (osi.Object, error) {
+	return t.open(ctx, "GetObjectRange", path, func(ctx context.Context) (osi.Object, error) {
+		return t.Bucket.GetObjectRange(ctx, path, rng, opts...)
+	})
+}
+
+func (t *bucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	return t.put(ctx, "PutObject", reader, func(ctx context.Context, reader io.Reader) error {
+		return t.Bucket.PutObject(ctx, path, reader, opts...)
+	}, ObjectKey.String(path))
+}
+
+func (t *bucket) PutObjectWithACL(ctx context.Context, path string, reader io.Reader, acl osi.ACL) error {
+	return t.put(ctx, "PutObjectWithACL", reader, func(ctx context.Context, reader io.Reader) error {
+		return t.Bucket.PutObjectWithACL(ctx, path, reader, acl)
+	}, ObjectKey.String(path))
+}
+
+func (t *bucket) HeadObject(ctx context.Context, path string) (bool, error) {
+	ctx, span := t.start(ctx, "HeadObject", ObjectKey.String(path))
+	exists, err := t.Bucket.HeadObject(ctx, path)
+	end(span, err)
+	return exists, err
+}
+
+func (t *bucket) StatObject(ctx context.Context, path string, opts ...osi.GetOption) (*osi.ObjectInfo, error) {
+	ctx, span := t.start(ctx, "StatObject", ObjectKey.String(path))
+	info, err := t.Bucket.StatObject(ctx, path, opts...)
+	end(span, err)
+	return info, err
+}
+
+func (t *bucket) DeleteObject(ctx context.Context, path string, opts ...osi.DeleteOption) error {
+	ctx, span := t.start(ctx, "DeleteObject", ObjectKey.String(path))
+	err := t.Bucket.DeleteObject(ctx, path, opts...)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) CopyObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	ctx, span := t.start(ctx, "CopyObject", ObjectKey.String(src), DestinationKey.String(dst))
+	err := t.Bucket.CopyObject(ctx, src, dst, opts...)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) MoveObject(ctx context.Context, src string, dst string, opts ...osi.CopyOption) error {
+	ctx, span := t.start(ctx, "MoveObject", ObjectKey.String(src), DestinationKey.String(dst))
+	err := t.Bucket.MoveObject(ctx, src, dst, opts...)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) GetObjectSize(ctx context.Context, path string) (osi.Size, error) {
+	ctx, span := t.start(ctx, "GetObjectSize", ObjectKey.String(path))
+	size, err := t.Bucket.GetObjectSize(ctx, path)
+	end(span, err)
+	return size, err
+}
+
+func (t *bucket) ListObjects(ctx context.Context, prefix string) ([]osi.ObjectMeta, error) {
+	ctx, span := t.start(ctx, "ListObjects", PrefixKey.String(prefix))
+	objects, err := t.Bucket.ListObjects(ctx, prefix)
+	end(span, err)
+	return objects, err
+}
+
+func (t *bucket) ListObjectsPage(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.ObjectPage, error) {
+	ctx, span := t.start(ctx, "ListObjectsPage", PrefixKey.String(prefix))
+	page, err := t.Bucket.ListObjectsPage(ctx, prefix, opts...)
+	end(span, err)
+	return page, err
+}
+
+func (t *bucket) DeleteObjects(ctx context.Context, paths []string) error {
+	ctx, span := t.start(ctx, "DeleteObjects", ObjectKey.StringSlice(paths))
+	err := t.Bucket.DeleteObjects(ctx, paths)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) InitiateMultipartUpload(ctx context.Context, path string, opts ...osi.PutOption) (string, error) {
+	ctx, span := t.start(ctx, "InitiateMultipartUpload", ObjectKey.String(path))
+	uploadID, err := t.Bucket.InitiateMultipartUpload(ctx, path, opts...)
+	span.SetAttributes(UploadIDKey.String(uploadID))
+	end(span, err)
+	return uploadID, err
+}
+
+func (t *bucket) UploadPart(ctx context.Context, path string, uploadID string, partNumber int, reader io.Reader, size int64, opts ...osi.PutOption) (osi.Part, error) {
+	var part osi.Part
+	err := t.put(ctx, "UploadPart", reader, func(ctx context.Context, reader io.Reader) (err error) {
+		part, err = t.Bucket.UploadPart(ctx, path, uploadID, partNumber, reader, size, opts...)
+		return err
+	}, ObjectKey.String(path), UploadIDKey.String(uploadID), PartNumberKey.Int(partNumber))
+	return part, err
+}
+
+func (t *bucket) ListParts(ctx context.Context, path string, uploadID string) ([]osi.Part, error) {
+	ctx, span := t.start(ctx, "ListParts", ObjectKey.String(path), UploadIDKey.String(uploadID))
+	parts, err := t.Bucket.ListParts(ctx, path, uploadID)
+	end(span, err)
+	return parts, err
+}
+
+func (t *bucket) CompleteMultipartUpload(ctx context.Context, path string, uploadID string, parts []osi.Part, opts ...osi.PutOption) error {
+	ctx, span := t.start(ctx, "CompleteMultipartUpload", ObjectKey.String(path), UploadIDKey.String(uploadID))
+	err := t.Bucket.CompleteMultipartUpload(ctx, path, uploadID, parts, opts...)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) AbortMultipartUpload(ctx context.Context, path string, uploadID string) error {
+	ctx, span := t.start(ctx, "AbortMultipartUpload", ObjectKey.String(path), UploadIDKey.String(uploadID))
+	err := t.Bucket.AbortMultipartUpload(ctx, path, uploadID)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) ListObjectVersions(ctx context.Context, prefix string, opts ...osi.ListOption) (*osi.VersionPage, error) {
+	ctx, span := t.start(ctx, "ListObjectVersions", PrefixKey.String(prefix))
+	page, err := t.Bucket.ListObjectVersions(ctx, prefix, opts...)
+	end(span, err)
+	return page, err
+}
+
+func (t *bucket) GetVersioning(ctx context.Context) (osi.VersioningStatus, error) {
+	ctx, span := t.start(ctx, "GetVersioning")
+	status, err := t.Bucket.GetVersioning(ctx)
+	end(span, err)
+	return status, err
+}
+
+func (t *bucket) SetVersioning(ctx context.Context, status osi.VersioningStatus) error {
+	ctx, span := t.start(ctx, "SetVersioning")
+	err := t.Bucket.SetVersioning(ctx, status)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) GetObjectTags(ctx context.Context, path string) (map[string]string, error) {
+	ctx, span := t.start(ctx, "GetObjectTags", ObjectKey.String(path))
+	tags, err := t.Bucket.GetObjectTags(ctx, path)
+	end(span, err)
+	return tags, err
+}
+
+func (t *bucket) PutObjectTags(ctx context.Context, path string, tags map[string]string) error {
+	ctx, span := t.start(ctx, "PutObjectTags", ObjectKey.String(path))
+	err := t.Bucket.PutObjectTags(ctx, path, tags)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) DeleteObjectTags(ctx context.Context, path string) error {
+	ctx, span := t.start(ctx, "DeleteObjectTags", ObjectKey.String(path))
+	err := t.Bucket.DeleteObjectTags(ctx, path)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) SetStorageClass(ctx context.Context, path string, class string) error {
+	ctx, span := t.start(ctx, "SetStorageClass", ObjectKey.String(path))
+	err := t.Bucket.SetStorageClass(ctx, path, class)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) RestoreObject(ctx context.Context, path string, days int, tier osi.RestoreTier) error {
+	ctx, span := t.start(ctx, "RestoreObject", ObjectKey.String(path))
+	err := t.Bucket.RestoreObject(ctx, path, days, tier)
+	end(span, err)
+	return err
+}
+
+func (t *bucket) SignURL(ctx context.Context, path string, method string, expiredInDur time.Duration, opts ...osi.SignOption) (string, error) {
+	ctx, span := t.start(ctx, "SignURL", ObjectKey.String(path))
+	url, err := t.Bucket.SignURL(ctx, path, method, expiredInDur, opts...)
+	end(span, err)
+	return url, err
+}
+
+func (t *bucket) SignPostPolicy(ctx context.Context, policy osi.PostPolicy, expiredInDur time.Duration) (*osi.PostForm, error) {
+	ctx, span := t.start(ctx, "SignPostPolicy")
+	form, err := t.Bucket.SignPostPolicy(ctx, policy, expiredInDur)
+	end(span, err)
+	return form, err
+}
@@ -0,0 +1,20 @@
+package tracing
+
+import (
+	"github.com/burybell/osi"
+)
+
+type objectStore struct {
+	osi.ObjectStore
+	opts []Option
+}
+
+// NewObjectStore wraps store so that the calls on its buckets are traced,
+// with the store's Name as StoreKey.
+func NewObjectStore(store osi.ObjectStore, opts ...Option) osi.ObjectStore {
+	return &objectStore{ObjectStore: store, opts: opts}
+}
+
+func (t *objectStore) Bucket(name string) osi.Bucket {
+	return NewBucket(t.ObjectStore.Bucket(name), t.Name(), name, t.opts...)
+}
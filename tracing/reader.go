@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"github.com/burybell/osi"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"sync"
+)
+
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (t *countingReader) Read(p []byte) (int, error) {
+	n, err := t.reader.Read(p)
+	t.n += int64(n)
+	return n, err
+}
+
+// countingSeeker keeps the body seekable, so that backends can still tell
+// its size and rewind it. It counts the bytes past the highest offset read,
+// so that a body rewound for a retry is counted once.
+type countingSeeker struct {
+	*countingReader
+	seeker io.Seeker
+	offset int64
+	high   int64
+}
+
+func (t *countingSeeker) Read(p []byte) (int, error) {
+	n, err := t.reader.Read(p)
+	t.offset += int64(n)
+	if t.offset > t.high {
+		t.n += t.offset - t.high
+		t.high = t.offset
+	}
+	return n, err
+}
+
+func (t *countingSeeker) Seek(offset int64, whence int) (int64, error) {
+	offset, err := t.seeker.Seek(offset, whence)
+	if err == nil {
+		t.offset = offset
+	}
+	return offset, err
+}
+
+type countingLen struct {
+	*countingReader
+	len interface{ Len() int }
+}
+
+func (t *countingLen) Len() int {
+	return t.len.Len()
+}
+
+// countReader wraps reader to count the bytes read from it, keeping the
+// methods osi.ReaderSize tells its size by.
+func countReader(reader io.Reader) (io.Reader, *countingReader) {
+	counter := &countingReader{reader: reader}
+	switch r := reader.(type) {
+	case io.Seeker:
+		// the body may not start at its beginning
+		offset, _ := r.Seek(0, io.SeekCurrent)
+		return &countingSeeker{countingReader: counter, seeker: r, offset: offset, high: offset}, counter
+	case interface{ Len() int }:
+		return &countingLen{countingReader: counter, len: r}, counter
+	}
+	return counter, counter
+}
+
+// spanObject ends the span of the get that returned it when it is closed,
+// so that the span covers the reading of the body.
+type spanObject struct {
+	osi.Object
+	counter *countingReader
+	span    trace.Span
+	once    sync.Once
+}
+
+func newSpanObject(object osi.Object, span trace.Span) osi.Object {
+	return &spanObject{Object: object, counter: &countingReader{reader: object}, span: span}
+}
+
+func (t *spanObject) Read(p []byte) (int, error) {
+	return t.counter.Read(p)
+}
+
+func (t *spanObject) Close() error {
+	err := t.Object.Close()
+	t.once.Do(func() {
+		t.span.SetAttributes(BytesKey.Int64(t.counter.n))
+		end(t.span, err)
+	})
+	return err
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName names the tracer the spans are started with.
+const InstrumentationName = "github.com/burybell/osi/tracing"
+
+// The attributes of the spans.
+const (
+	StoreKey  = attribute.Key("osi.store")
+	BucketKey = attribute.Key("osi.bucket")
+	// ObjectKey is the path of the object, the source of copies and moves.
+	ObjectKey      = attribute.Key("osi.key")
+	DestinationKey = attribute.Key("osi.destination_key")
+	PrefixKey      = attribute.Key("osi.prefix")
+	// BytesKey is the number of body bytes sent or received, set on puts as
+	// they return and on gets as the object is closed.
+	BytesKey      = attribute.Key("osi.bytes")
+	UploadIDKey   = attribute.Key("osi.upload_id")
+	PartNumberKey = attribute.Key("osi.part_number")
+	// ErrorClassKey is the class osi.ErrorClass reports of the error a call
+	// failed with.
+	ErrorClassKey = attribute.Key("osi.error_class")
+)
+
+type Options struct {
+	TracerProvider trace.TracerProvider
+}
+
+type Option func(opts *Options)
+
+// WithTracerProvider starts the spans with provider instead of the global
+// one otel.GetTracerProvider returns.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(opts *Options) {
+		opts.TracerProvider = provider
+	}
+}
+
+func NewOptions(opts ...Option) *Options {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.TracerProvider == nil {
+		options.TracerProvider = otel.GetTracerProvider()
+	}
+	return options
+}
+
+// start starts the span of the operation name as a child of the span in ctx.
+func (t *bucket) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append([]attribute.KeyValue{StoreKey.String(t.store), BucketKey.String(t.bucket)}, attrs...)
+	return t.tracer.Start(ctx, "osi."+name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// end ends span, failed with err when it is not nil.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.SetAttributes(ErrorClassKey.String(osi.ErrorClass(err)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
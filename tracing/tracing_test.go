@@ -0,0 +1,124 @@
+package tracing_test
+
+import (
+	"context"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/local"
+	"github.com/burybell/osi/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+var (
+	ctx   = context.Background()
+	store osi.ObjectStore
+)
+
+func init() {
+	dir, err := os.MkdirTemp("", "osi-tracing-")
+	if err != nil {
+		panic(err)
+	}
+	store = local.MustNewObjectStore(local.Config{BasePath: dir})
+	if err := store.CreateBucket(ctx, "example"); err != nil {
+		panic(err)
+	}
+}
+
+func newBucket() (osi.Bucket, *tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	store := tracing.NewObjectStore(store, tracing.WithTracerProvider(provider))
+	return store.Bucket("example"), recorder, provider
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestBucket_Spans(t *testing.T) {
+	bucket, recorder, provider := newBucket()
+	parentCtx, parent := provider.Tracer("test").Start(ctx, "request")
+
+	assert.NoError(t, bucket.PutObject(parentCtx, "test/trace.txt", strings.NewReader("some text")))
+	defer bucket.DeleteObject(ctx, "test/trace.txt")
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "osi.PutObject", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	attrs := attributes(spans[0])
+	assert.Equal(t, local.Name, attrs[tracing.StoreKey].AsString())
+	assert.Equal(t, "example", attrs[tracing.BucketKey].AsString())
+	assert.Equal(t, "test/trace.txt", attrs[tracing.ObjectKey].AsString())
+	assert.Equal(t, int64(9), attrs[tracing.BytesKey].AsInt64())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	// the span of a get ends when the object is closed
+	object, err := bucket.GetObject(parentCtx, "test/trace.txt")
+	assert.NoError(t, err)
+	assert.Len(t, recorder.Ended(), 1)
+	_, _ = io.ReadAll(object)
+	assert.NoError(t, object.Close())
+	_ = object.Close()
+	spans = recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "osi.GetObject", spans[1].Name())
+	assert.Equal(t, int64(9), attributes(spans[1])[tracing.BytesKey].AsInt64())
+
+	_, err = bucket.GetObject(parentCtx, "test/missing.txt")
+	assert.ErrorIs(t, err, osi.ObjectNotFound)
+	spans = recorder.Ended()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.Equal(t, "ObjectNotFound", attributes(spans[2])[tracing.ErrorClassKey].AsString())
+	assert.Len(t, spans[2].Events(), 1)
+
+	assert.NoError(t, bucket.CopyObject(parentCtx, "test/trace.txt", "test/copy.txt"))
+	defer bucket.DeleteObject(ctx, "test/copy.txt")
+	spans = recorder.Ended()
+	attrs = attributes(spans[3])
+	assert.Equal(t, "test/trace.txt", attrs[tracing.ObjectKey].AsString())
+	assert.Equal(t, "test/copy.txt", attrs[tracing.DestinationKey].AsString())
+	parent.End()
+}
+
+// rewindingBucket reads part of the bodies put and rewinds them, as a retried
+// put does.
+type rewindingBucket struct {
+	osi.Bucket
+}
+
+func (t *rewindingBucket) PutObject(ctx context.Context, path string, reader io.Reader, opts ...osi.PutOption) error {
+	seeker := reader.(io.ReadSeeker)
+	if _, err := io.CopyN(io.Discard, seeker, 4); err != nil {
+		return err
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return t.Bucket.PutObject(ctx, path, reader, opts...)
+}
+
+func TestBucket_PutObjectRewound(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	bucket := tracing.NewBucket(&rewindingBucket{Bucket: store.Bucket("example")}, local.Name, "example", tracing.WithTracerProvider(provider))
+	defer bucket.DeleteObject(ctx, "test/rewound.txt")
+
+	// the bytes read again after a rewind are counted once
+	assert.NoError(t, bucket.PutObject(ctx, "test/rewound.txt", strings.NewReader("some text")))
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, int64(9), attributes(spans[0])[tracing.BytesKey].AsInt64())
+}
@@ -0,0 +1,168 @@
+package osi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+const (
+	DefaultPartSize    = 16 << 20
+	DefaultConcurrency = 4
+)
+
+// PutFunc stores size bytes read from reader with a single request.
+type PutFunc func(ctx context.Context, reader io.Reader, size int64) error
+
+// Upload stores reader at path. Content that fits into one part is passed to
+// put; anything larger is split into parts of PutOptions.PartSize that are
+// uploaded through bucket with up to PutOptions.Concurrency parts in flight.
+// At most Concurrency parts are buffered in memory, whatever the length of
+// reader, and a failed upload is aborted.
+func Upload(ctx context.Context, bucket BucketMultipart, path string, reader io.Reader, put PutFunc, opts ...PutOption) error {
+	options := NewPutOptions(path, opts...)
+	partSize := options.PartSize
+	if size, ok := ReaderSize(reader); ok {
+		if size <= partSize {
+			return put(ctx, reader, size)
+		}
+		partSize = fitPartSize(size, partSize)
+	}
+
+	first := make([]byte, partSize)
+	n, err := io.ReadFull(reader, first)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return put(ctx, bytes.NewReader(first[:n]), int64(n))
+	}
+	if err != nil {
+		return err
+	}
+
+	uploadID, err := bucket.InitiateMultipartUpload(ctx, path, opts...)
+	if err != nil {
+		return err
+	}
+	parts, err := uploadParts(ctx, bucket, path, uploadID, reader, first, options.Concurrency, opts)
+	if err != nil {
+		_ = bucket.AbortMultipartUpload(context.Background(), path, uploadID)
+		return err
+	}
+	return bucket.CompleteMultipartUpload(ctx, path, uploadID, parts, opts...)
+}
+
+func uploadParts(ctx context.Context, bucket BucketMultipart, path string, uploadID string, reader io.Reader, first []byte, concurrency int, opts []PutOption) ([]Part, error) {
+	partCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		parts    = make([]Part, 0)
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+
+	// buffers recycles the part buffers; a nil entry is allocated on first use.
+	buffers := make(chan []byte, concurrency)
+	for i := 1; i < concurrency; i++ {
+		buffers <- nil
+	}
+
+	buf, n, last := first, len(first), false
+	for partNumber := 1; ; partNumber++ {
+		wg.Add(1)
+		go func(partNumber int, buf []byte, n int) {
+			defer wg.Done()
+			part, err := bucket.UploadPart(partCtx, path, uploadID, partNumber, bytes.NewReader(buf[:n]), int64(n), opts...)
+			buffers <- buf
+			if err != nil {
+				fail(err)
+				return
+			}
+			mu.Lock()
+			parts = append(parts, part)
+			mu.Unlock()
+		}(partNumber, buf, n)
+		if last {
+			break
+		}
+
+		select {
+		case buf = <-buffers:
+		case <-partCtx.Done():
+		}
+		if partCtx.Err() != nil {
+			fail(partCtx.Err())
+			break
+		}
+		if buf == nil {
+			buf = make([]byte, len(first))
+		}
+		var err error
+		n, err = io.ReadFull(reader, buf)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			last = true
+		} else if err != nil {
+			fail(err)
+			break
+		}
+		if partNumber == MaxPartCount {
+			fail(fmt.Errorf("upload %s: more than %d parts of %d bytes", path, MaxPartCount, len(first)))
+			break
+		}
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return parts, nil
+}
+
+// fitPartSize grows partSize so that size bytes fit into MaxPartCount parts.
+func fitPartSize(size int64, partSize int64) int64 {
+	if least := (size + MaxPartCount - 1) / MaxPartCount; least > partSize {
+		return least
+	}
+	return partSize
+}
+
+// ReaderSize reports the number of bytes left in reader when it can be told
+// without consuming it.
+func ReaderSize(reader io.Reader) (int64, bool) {
+	switch r := reader.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len()), true
+	case io.Seeker:
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false
+		}
+		_, err = r.Seek(offset, io.SeekStart)
+		if err != nil {
+			return 0, false
+		}
+		return end - offset, true
+	}
+	return 0, false
+}
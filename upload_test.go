@@ -0,0 +1,52 @@
+package osi_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/burybell/osi"
+	"github.com/burybell/osi/local"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestUpload(t *testing.T) {
+	ctx := context.Background()
+	store := local.MustNewObjectStore(local.Config{BasePath: t.TempDir()})
+	assert.NoError(t, store.CreateBucket(ctx, "test"))
+	bucket := store.Bucket("test")
+
+	var putSize int64 = -1
+	put := func(ctx context.Context, reader io.Reader, size int64) error {
+		putSize = size
+		return bucket.PutObject(ctx, "test/upload.txt", reader)
+	}
+	err := osi.Upload(ctx, bucket, "test/upload.txt", io.MultiReader(strings.NewReader("some text")), put)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), putSize)
+
+	putSize = -1
+	content := bytes.Repeat([]byte("0123456789"), osi.MinPartSize/4)
+	err = osi.Upload(ctx, bucket, "test/upload.txt", io.MultiReader(bytes.NewReader(content)), put, osi.WithPartSize(osi.MinPartSize), osi.WithConcurrency(2))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), putSize)
+	info, err := bucket.StatObject(ctx, "test/upload.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), info.Size)
+	assert.True(t, strings.HasSuffix(info.ETag, "-3"))
+	object, err := bucket.GetObject(ctx, "test/upload.txt")
+	assert.NoError(t, err)
+	bs, err := io.ReadAll(object)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(content, bs))
+	_ = object.Close()
+}
+
+func TestReaderSize(t *testing.T) {
+	size, ok := osi.ReaderSize(strings.NewReader("some text"))
+	assert.True(t, ok)
+	assert.Equal(t, int64(9), size)
+	_, ok = osi.ReaderSize(io.MultiReader(strings.NewReader("some text")))
+	assert.False(t, ok)
+}
@@ -0,0 +1,83 @@
+package osi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+)
+
+type VersioningStatus string
+
+const (
+	// VersioningOff is the status of buckets that never had versioning enabled.
+	VersioningOff       VersioningStatus = ""
+	VersioningEnabled   VersioningStatus = "Enabled"
+	VersioningSuspended VersioningStatus = "Suspended"
+
+	// NullVersionID is the version id of objects stored while versioning was
+	// off or suspended.
+	NullVersionID = "null"
+)
+
+// BucketVersioning reaches the prior versions kept by versioned buckets.
+// Versions are addressed through WithGetVersion and WithDeleteVersion;
+// deleting without a version id in a versioned bucket adds a delete marker.
+type BucketVersioning interface {
+	// ListObjectVersions lists the versions of the objects under prefix,
+	// ordered by key and, for each key, from the latest version back.
+	ListObjectVersions(ctx context.Context, prefix string, opts ...ListOption) (*VersionPage, error)
+	GetVersioning(ctx context.Context) (VersioningStatus, error)
+	// SetVersioning enables or suspends versioning; once enabled it cannot be turned off.
+	SetVersioning(ctx context.Context, status VersioningStatus) error
+}
+
+type ObjectVersion struct {
+	*ObjectInfo
+	IsLatest       bool
+	IsDeleteMarker bool
+}
+
+type VersionPage struct {
+	Versions              []*ObjectVersion
+	CommonPrefixes        []string
+	NextContinuationToken string
+	IsTruncated           bool
+}
+
+// VersionToken encodes the key and version id a version listing stopped at
+// into a continuation token.
+func VersionToken(key string, versionID string) string {
+	if key == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(key + "\x00" + versionID))
+}
+
+// ParseVersionToken decodes a token made by VersionToken.
+func ParseVersionToken(token string) (string, string, error) {
+	if token == "" {
+		return "", "", nil
+	}
+	bs, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", "", errors.New("invalid continuation token")
+	}
+	marker := strings.SplitN(string(bs), "\x00", 2)
+	if len(marker) != 2 {
+		return "", "", errors.New("invalid continuation token")
+	}
+	return marker[0], marker[1], nil
+}
+
+// SortVersions orders versions by key and, for each key, from the latest
+// version back, for providers that list delete markers apart from versions.
+func SortVersions(versions []*ObjectVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		if versions[i].ObjectPath() != versions[j].ObjectPath() {
+			return versions[i].ObjectPath() < versions[j].ObjectPath()
+		}
+		return versions[i].LastModified.After(versions[j].LastModified)
+	})
+}
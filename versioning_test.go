@@ -0,0 +1,46 @@
+package osi_test
+
+import (
+	"github.com/burybell/osi"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestVersionToken(t *testing.T) {
+	token := osi.VersionToken("test/example.txt", "v1")
+	key, versionID, err := osi.ParseVersionToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "test/example.txt", key)
+	assert.Equal(t, "v1", versionID)
+
+	assert.Empty(t, osi.VersionToken("", "v1"))
+	key, versionID, err = osi.ParseVersionToken("")
+	assert.NoError(t, err)
+	assert.Empty(t, key)
+	assert.Empty(t, versionID)
+	_, _, err = osi.ParseVersionToken("not a token")
+	assert.Error(t, err)
+}
+
+func TestSortVersions(t *testing.T) {
+	now := time.Now()
+	newVersion := func(path string, versionID string, lastModified time.Time, deleteMarker bool) *osi.ObjectVersion {
+		info := osi.NewObjectInfo("test", path)
+		info.VersionID = versionID
+		info.LastModified = lastModified
+		return &osi.ObjectVersion{ObjectInfo: info, IsDeleteMarker: deleteMarker}
+	}
+	versions := []*osi.ObjectVersion{
+		newVersion("b", "b1", now.Add(-time.Hour), false),
+		newVersion("a", "a1", now.Add(-time.Hour), false),
+		newVersion("a", "a2", now, false),
+		newVersion("a", "a3", now.Add(time.Hour), true),
+	}
+	osi.SortVersions(versions)
+	var ids []string
+	for _, version := range versions {
+		ids = append(ids, version.VersionID)
+	}
+	assert.Equal(t, []string{"a3", "a2", "a1", "b1"}, ids)
+}